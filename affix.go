@@ -0,0 +1,165 @@
+package gobag
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// HasAnyPrefix reports whether s begins with any of the prefixes.
+func HasAnyPrefix(s string, prefixes ...string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAnySuffix reports whether s ends with any of the suffixes.
+func HasAnySuffix(s string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// TrimPrefixes returns s without the longest of the prefixes it begins
+// with, and whether there was one. Only a single prefix is removed, so
+// TrimPrefixes("--x", "-", "--") returns "x", true.
+func TrimPrefixes(s string, prefixes ...string) (string, bool) {
+	best := -1
+	for _, prefix := range prefixes {
+		if len(prefix) > best && strings.HasPrefix(s, prefix) {
+			best = len(prefix)
+		}
+	}
+	if best < 0 {
+		return s, false
+	}
+	return s[best:], true
+}
+
+// TrimSuffixes returns s without the longest of the suffixes it ends
+// with, and whether there was one.
+func TrimSuffixes(s string, suffixes ...string) (string, bool) {
+	best := -1
+	for _, suffix := range suffixes {
+		if len(suffix) > best && strings.HasSuffix(s, suffix) {
+			best = len(suffix)
+		}
+	}
+	if best < 0 {
+		return s, false
+	}
+	return s[:len(s)-best], true
+}
+
+// HasPrefixFold reports whether s begins with prefix, ignoring case
+// under Unicode simple case folding, as strings.EqualFold does.
+func HasPrefixFold(s, prefix string) bool {
+	for _, r := range prefix {
+		if s == "" {
+			return false
+		}
+		r1, n := utf8.DecodeRuneInString(s)
+		if foldRune(r1) != foldRune(r) {
+			return false
+		}
+		s = s[n:]
+	}
+	return true
+}
+
+// HasSuffixFold reports whether s ends with suffix, ignoring case under
+// Unicode simple case folding.
+func HasSuffixFold(s, suffix string) bool {
+	for suffix != "" {
+		if s == "" {
+			return false
+		}
+		r1, n1 := utf8.DecodeLastRuneInString(s)
+		r2, n2 := utf8.DecodeLastRuneInString(suffix)
+		if foldRune(r1) != foldRune(r2) {
+			return false
+		}
+		s, suffix = s[:len(s)-n1], suffix[:len(suffix)-n2]
+	}
+	return true
+}
+
+// foldRune returns the smallest rune equivalent to r under simple case
+// folding, so runes are equal ignoring case if their folds are equal.
+func foldRune(r rune) rune {
+	fold := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		fold = min(fold, f)
+	}
+	return fold
+}
+
+// PrefixMatcher finds the longest of a set of prefixes that a string
+// begins with, ignoring case. Lookups take time proportional to the
+// length of the match, independent of the number of prefixes. The zero
+// value is an empty matcher ready to use. A PrefixMatcher is not safe
+// for concurrent use while prefixes are being added.
+type PrefixMatcher struct {
+	root prefixNode
+}
+
+type prefixNode struct {
+	children map[rune]*prefixNode
+	prefix   string
+	terminal bool
+}
+
+// NewPrefixMatcher returns a PrefixMatcher matching the given prefixes.
+func NewPrefixMatcher(prefixes ...string) *PrefixMatcher {
+	m := &PrefixMatcher{}
+	for _, prefix := range prefixes {
+		m.Add(prefix)
+	}
+	return m
+}
+
+// Add adds prefix to the matcher. Adding a prefix equal to an existing
+// one ignoring case replaces it.
+func (m *PrefixMatcher) Add(prefix string) {
+	n := &m.root
+	for _, r := range prefix {
+		r = foldRune(r)
+		child, ok := n.children[r]
+		if !ok {
+			if n.children == nil {
+				n.children = make(map[rune]*prefixNode)
+			}
+			child = &prefixNode{}
+			n.children[r] = child
+		}
+		n = child
+	}
+	n.prefix = prefix
+	n.terminal = true
+}
+
+// Match returns the longest prefix of the matcher that s begins with,
+// ignoring case, as it was added, and the rest of s after it. Reports
+// false if s begins with none of the prefixes.
+func (m *PrefixMatcher) Match(s string) (prefix, rest string, ok bool) {
+	n := &m.root
+	if n.terminal {
+		prefix, rest, ok = n.prefix, s, true
+	}
+	for i, r := range s {
+		if n = n.children[foldRune(r)]; n == nil {
+			break
+		}
+		if n.terminal {
+			_, size := utf8.DecodeRuneInString(s[i:])
+			prefix, rest, ok = n.prefix, s[i+size:], true
+		}
+	}
+	return prefix, rest, ok
+}
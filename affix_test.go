@@ -0,0 +1,124 @@
+package gobag
+
+import "testing"
+
+func TestHasAnyPrefixSuffix(t *testing.T) {
+	tests := []struct {
+		s              string
+		affixes        []string
+		prefix, suffix bool
+	}{
+		{"--verbose", []string{"-", "/"}, true, false},
+		{"file.tar.gz", []string{".gz", ".bz2"}, false, true},
+		{"abc", []string{""}, true, true},
+		{"abc", nil, false, false},
+		{"", []string{"a"}, false, false},
+	}
+	for _, tt := range tests {
+		if got := HasAnyPrefix(tt.s, tt.affixes...); got != tt.prefix {
+			t.Errorf("HasAnyPrefix(%q, %q) = %v, want %v", tt.s, tt.affixes, got, tt.prefix)
+		}
+		if got := HasAnySuffix(tt.s, tt.affixes...); got != tt.suffix {
+			t.Errorf("HasAnySuffix(%q, %q) = %v, want %v", tt.s, tt.affixes, got, tt.suffix)
+		}
+	}
+}
+
+func TestTrimPrefixes(t *testing.T) {
+	tests := []struct {
+		s        string
+		prefixes []string
+		expected string
+		ok       bool
+	}{
+		{"--verbose", []string{"-", "--"}, "verbose", true},
+		{"-v", []string{"-", "--"}, "v", true},
+		{"/v", []string{"-", "--"}, "/v", false},
+		{"--", []string{"--"}, "", true},
+		{"abc", []string{""}, "abc", true},
+		{"abc", nil, "abc", false},
+	}
+	for _, tt := range tests {
+		got, ok := TrimPrefixes(tt.s, tt.prefixes...)
+		if got != tt.expected || ok != tt.ok {
+			t.Errorf("TrimPrefixes(%q, %q) = %q, %v, want %q, %v", tt.s, tt.prefixes, got, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestTrimSuffixes(t *testing.T) {
+	tests := []struct {
+		s        string
+		suffixes []string
+		expected string
+		ok       bool
+	}{
+		{"file.tar.gz", []string{".gz", ".tar.gz"}, "file", true},
+		{"file.gz", []string{".gz", ".tar.gz"}, "file", true},
+		{"file.txt", []string{".gz", ".tar.gz"}, "file.txt", false},
+		{"abc", nil, "abc", false},
+	}
+	for _, tt := range tests {
+		got, ok := TrimSuffixes(tt.s, tt.suffixes...)
+		if got != tt.expected || ok != tt.ok {
+			t.Errorf("TrimSuffixes(%q, %q) = %q, %v, want %q, %v", tt.s, tt.suffixes, got, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestHasPrefixSuffixFold(t *testing.T) {
+	tests := []struct {
+		s, affix       string
+		prefix, suffix bool
+	}{
+		{"Content-Type", "content-", true, false},
+		{"X-FORWARDED-FOR", "-for", false, true},
+		{"\u212aelvin", "kel", true, false},
+		{"Straße", "STRASSE", false, false},
+		{"ÆØÅ", "æø", true, false},
+		{"ÆØÅ", "øå", false, true},
+		{"abc", "", true, true},
+		{"ab", "abc", false, false},
+	}
+	for _, tt := range tests {
+		if got := HasPrefixFold(tt.s, tt.affix); got != tt.prefix {
+			t.Errorf("HasPrefixFold(%q, %q) = %v, want %v", tt.s, tt.affix, got, tt.prefix)
+		}
+		if got := HasSuffixFold(tt.s, tt.affix); got != tt.suffix {
+			t.Errorf("HasSuffixFold(%q, %q) = %v, want %v", tt.s, tt.affix, got, tt.suffix)
+		}
+	}
+}
+
+func TestPrefixMatcher(t *testing.T) {
+	m := NewPrefixMatcher("db.", "db.pool.", "log", "Ærlig", "a\xff")
+	tests := []struct {
+		s, prefix, rest string
+		ok              bool
+	}{
+		{"db.host", "db.", "host", true},
+		{"DB.Pool.Size", "db.pool.", "Size", true},
+		{"db.pool", "db.", "pool", true},
+		{"logLevel", "log", "Level", true},
+		{"ærlig.svar", "Ærlig", ".svar", true},
+		{"a\xffb", "a\xff", "b", true},
+		{"a\xff", "a\xff", "", true},
+		{"cache.size", "", "", false},
+		{"", "", "", false},
+	}
+	for _, tt := range tests {
+		prefix, rest, ok := m.Match(tt.s)
+		if prefix != tt.prefix || rest != tt.rest || ok != tt.ok {
+			t.Errorf("Match(%q) = %q, %q, %v, want %q, %q, %v", tt.s, prefix, rest, ok, tt.prefix, tt.rest, tt.ok)
+		}
+	}
+
+	var zero PrefixMatcher
+	if _, _, ok := zero.Match("x"); ok {
+		t.Error("Match() on zero PrefixMatcher reported a match")
+	}
+	zero.Add("")
+	if prefix, rest, ok := zero.Match("x"); prefix != "" || rest != "x" || !ok {
+		t.Errorf("Match() with empty prefix = %q, %q, %v", prefix, rest, ok)
+	}
+}
@@ -0,0 +1,53 @@
+package gobag
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Aliases resolves alternative names to canonical names, e.g. legacy
+// config keys to their current spelling.
+type Aliases struct {
+	canonical map[string]string
+}
+
+// NewAliases returns Aliases for a table mapping each canonical name
+// to its accepted aliases. Every canonical name is also an alias of
+// itself. Returns an error if a name resolves to more than one
+// canonical name.
+func NewAliases(table map[string][]string) (*Aliases, error) {
+	a := &Aliases{canonical: make(map[string]string)}
+
+	names := Keys(table)
+	slices.Sort(names)
+	for _, name := range names {
+		if err := a.add(name, name); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range names {
+		for _, alias := range table[name] {
+			if err := a.add(alias, name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return a, nil
+}
+
+func (a *Aliases) add(alias, name string) error {
+	if prev, ok := a.canonical[alias]; ok && prev != name {
+		return fmt.Errorf("alias %q is ambiguous between %q and %q", alias, prev, name)
+	}
+	a.canonical[alias] = name
+
+	return nil
+}
+
+// Resolve returns the canonical name for key, and whether key is a
+// known name or alias.
+func (a *Aliases) Resolve(key string) (canonical string, ok bool) {
+	canonical, ok = a.canonical[key]
+	return canonical, ok
+}
@@ -0,0 +1,41 @@
+package gobag
+
+import "testing"
+
+func TestAliases(t *testing.T) {
+	a, err := NewAliases(map[string][]string{
+		"max_size": {"maxsize", "size_limit"},
+		"timeout":  {"tmo"},
+	})
+	if err != nil {
+		t.Fatalf("NewAliases() error = %v", err)
+	}
+
+	tests := []struct {
+		key       string
+		canonical string
+		ok        bool
+	}{
+		{"max_size", "max_size", true},
+		{"size_limit", "max_size", true},
+		{"tmo", "timeout", true},
+		{"unknown", "", false},
+	}
+	for _, tt := range tests {
+		if got, ok := a.Resolve(tt.key); got != tt.canonical || ok != tt.ok {
+			t.Errorf("Resolve(%q) = %q, %t, want %q, %t", tt.key, got, ok, tt.canonical, tt.ok)
+		}
+	}
+
+	_, err = NewAliases(map[string][]string{
+		"a": {"x"},
+		"b": {"x"},
+	})
+	if err == nil || err.Error() != `alias "x" is ambiguous between "a" and "b"` {
+		t.Errorf("NewAliases() error = %v, want ambiguity error", err)
+	}
+
+	if _, err := NewAliases(map[string][]string{"a": {"b"}, "b": nil}); err == nil {
+		t.Error("NewAliases() with alias shadowing a canonical name: expected error")
+	}
+}
@@ -0,0 +1,77 @@
+package gobag
+
+import "strings"
+
+// StripANSI returns s with ANSI escape sequences removed, such as the
+// SGR sequences setting colors ("\x1b[1;31m"), cursor movement, and
+// operating system commands like terminal titles and hyperlinks
+// ("\x1b]8;;url\x1b\\").
+func StripANSI(s string) string {
+	i := strings.IndexAny(s, "\x1b\u009b")
+	if i < 0 {
+		return s
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i >= 0 {
+		sb.WriteString(s[:i])
+		s = s[i+ansiSeqLen(s[i:]):]
+		i = strings.IndexAny(s, "\x1b\u009b")
+	}
+	sb.WriteString(s)
+
+	return sb.String()
+}
+
+// VisibleWidth returns the number of terminal columns needed to
+// display s, ignoring ANSI escape sequences. See Width.
+func VisibleWidth(s string) int {
+	return Width(StripANSI(s))
+}
+
+// ansiSeqLen returns the length of the escape sequence starting s,
+// which begins with ESC or the C1 control CSI. An unterminated
+// sequence extends to the end of s.
+func ansiSeqLen(s string) int {
+	var i int
+	var kind byte
+	switch {
+	case strings.HasPrefix(s, "\u009b"):
+		i, kind = len("\u009b"), '['
+	case len(s) < 2:
+		return len(s)
+	default:
+		i, kind = 2, s[1]
+	}
+
+	switch kind {
+	case '[':
+		// Control sequence: parameter and intermediate bytes in
+		// 0x20-0x3F, then a final byte in 0x40-0x7E.
+		for ; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']', 'P', 'X', '^', '_':
+		// String sequence, terminated by BEL or ST (ESC \).
+		for ; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	}
+
+	// Other sequences: intermediate bytes, then a final byte.
+	i = 1
+	for i < len(s) && s[i] >= 0x20 && s[i] <= 0x2f {
+		i++
+	}
+	return min(i+1, len(s))
+}
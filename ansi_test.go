@@ -0,0 +1,44 @@
+package gobag
+
+import "testing"
+
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"plain", "plain"},
+		{"\x1b[1;31merror\x1b[0m: bad", "error: bad"},
+		{"\x1b[38;5;208morange\x1b[m", "orange"},
+		{"\x1b]0;title\a text", " text"},
+		{"\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"\x1b(Bascii", "ascii"},
+		{"a\x1b7b\x1b8c", "abc"},
+		{"\u009b32mgreen\u009b0m", "green"},
+		{"cut\x1b[", "cut"},
+		{"end\x1b", "end"},
+	}
+	for _, tt := range tests {
+		if got := StripANSI(tt.input); got != tt.expected {
+			t.Errorf("StripANSI(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestVisibleWidth(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{"", 0},
+		{"hello", 5},
+		{"\x1b[1mhello\x1b[0m", 5},
+		{"\x1b[32m日本\x1b[0m", 4},
+		{"\x1b[4mcafé\x1b[24m", 4},
+	}
+	for _, tt := range tests {
+		if got := VisibleWidth(tt.input); got != tt.expected {
+			t.Errorf("VisibleWidth(%q) = %d, want %d", tt.input, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package gobag
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic writes data to the named file like os.WriteFile, but
+// atomically: the data is written to a temporary file in the same
+// directory, synced to disk and renamed over path, so readers see
+// either the old or the new content, never a partial file.
+func WriteFileAtomic(path string, data []byte, perm fs.FileMode) error {
+	f, err := CreateAtomic(path, perm)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	return f.Commit()
+}
+
+// AtomicFile is a file being written atomically, see WriteFileAtomic.
+// Its content replaces the target file only when Commit is called.
+type AtomicFile struct {
+	*os.File
+	path string
+	done bool
+}
+
+// CreateAtomic creates a temporary file that replaces the named file
+// with permissions perm once committed. The caller must call Close,
+// which discards the temporary file unless Commit succeeded.
+func CreateAtomic(path string, perm fs.FileMode) (*AtomicFile, error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	return &AtomicFile{File: f, path: path}, nil
+}
+
+// Commit syncs the written content to disk and renames the temporary
+// file over the target.
+func (f *AtomicFile) Commit() error {
+	if f.done {
+		return errors.New("atomic file already committed or closed")
+	}
+	f.done = true
+
+	err := f.File.Sync()
+	err = errors.Join(err, f.File.Close())
+	if err == nil {
+		err = os.Rename(f.File.Name(), f.path)
+	}
+	if err != nil {
+		os.Remove(f.File.Name())
+		return err
+	}
+
+	// Sync the directory, so the rename itself is durable.
+	if dir, err := os.Open(filepath.Dir(f.path)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+	return nil
+}
+
+// Close discards the temporary file if it was not committed. It is
+// safe to call Close after Commit.
+func (f *AtomicFile) Close() error {
+	if f.done {
+		return nil
+	}
+	f.done = true
+	return errors.Join(f.File.Close(), os.Remove(f.File.Name()))
+}
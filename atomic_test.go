@@ -0,0 +1,74 @@
+package gobag
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config")
+
+	for _, content := range []string{"first\n", "second\n"} {
+		if err := WriteFileAtomic(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFileAtomic() error = %v", err)
+		}
+		got, err := os.ReadFile(path)
+		if err != nil || string(got) != content {
+			t.Fatalf("file content = %q, %v, want %q", got, err, content)
+		}
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0o600 {
+		t.Errorf("file mode = %v, want %v", fi.Mode().Perm(), os.FileMode(0o600))
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("directory has %d entries, want 1", len(entries))
+	}
+
+	if err := WriteFileAtomic(filepath.Join(dir, "missing", "file"), nil, 0o644); err == nil {
+		t.Error("WriteFileAtomic() into missing directory succeeded")
+	}
+}
+
+func TestCreateAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out")
+	os.WriteFile(path, []byte("old"), 0o644)
+
+	f, err := CreateAtomic(path, 0o644)
+	if err != nil {
+		t.Fatalf("CreateAtomic() error = %v", err)
+	}
+	f.WriteString("partial")
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "old" {
+		t.Errorf("content after discarding = %q, want %q", got, "old")
+	}
+
+	f, err = CreateAtomic(path, 0o644)
+	if err != nil {
+		t.Fatalf("CreateAtomic() error = %v", err)
+	}
+	defer f.Close()
+	f.WriteString("new")
+	if err := f.Commit(); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if err := f.Commit(); err == nil {
+		t.Error("second Commit() succeeded")
+	}
+	if got, _ := os.ReadFile(path); string(got) != "new" {
+		t.Errorf("content after commit = %q, want %q", got, "new")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("directory has %d entries, want 1", len(entries))
+	}
+}
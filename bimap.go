@@ -0,0 +1,83 @@
+package gobag
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by BiMap.Put when a key or value is already taken.
+var (
+	ErrDuplicateKey   = errors.New("duplicate key")
+	ErrDuplicateValue = errors.New("duplicate value")
+)
+
+// BiMap is a one-to-one map that can be looked up in both directions.
+// Every key maps to a single value and every value to a single key.
+// The zero value is an empty map. A BiMap is not safe for concurrent
+// use.
+type BiMap[K, V comparable] struct {
+	forward map[K]V
+	reverse map[V]K
+}
+
+// NewBiMap returns an empty BiMap.
+func NewBiMap[K, V comparable]() *BiMap[K, V] {
+	return &BiMap[K, V]{}
+}
+
+// Put maps k to v. Putting an existing pair again is a no-op. Returns
+// an error wrapping ErrDuplicateKey if k is mapped to another value,
+// or ErrDuplicateValue if v is mapped to another key.
+func (m *BiMap[K, V]) Put(k K, v V) error {
+	if old, ok := m.forward[k]; ok {
+		if old == v {
+			return nil
+		}
+		return fmt.Errorf("%w: %v is mapped to %v", ErrDuplicateKey, k, old)
+	}
+	if old, ok := m.reverse[v]; ok {
+		return fmt.Errorf("%w: %v is mapped from %v", ErrDuplicateValue, v, old)
+	}
+
+	if m.forward == nil {
+		m.forward = make(map[K]V)
+		m.reverse = make(map[V]K)
+	}
+	m.forward[k] = v
+	m.reverse[v] = k
+
+	return nil
+}
+
+// Get returns the value mapped from k, and whether it was found.
+func (m *BiMap[K, V]) Get(k K) (V, bool) {
+	v, ok := m.forward[k]
+	return v, ok
+}
+
+// GetKey returns the key mapped to v, and whether it was found.
+func (m *BiMap[K, V]) GetKey(v V) (K, bool) {
+	k, ok := m.reverse[v]
+	return k, ok
+}
+
+// Delete removes the pair with key k, if any.
+func (m *BiMap[K, V]) Delete(k K) {
+	if v, ok := m.forward[k]; ok {
+		delete(m.forward, k)
+		delete(m.reverse, v)
+	}
+}
+
+// DeleteValue removes the pair with value v, if any.
+func (m *BiMap[K, V]) DeleteValue(v V) {
+	if k, ok := m.reverse[v]; ok {
+		delete(m.forward, k)
+		delete(m.reverse, v)
+	}
+}
+
+// Len returns the number of pairs.
+func (m *BiMap[K, V]) Len() int {
+	return len(m.forward)
+}
@@ -0,0 +1,41 @@
+package gobag
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBiMap(t *testing.T) {
+	m := NewBiMap[int, string]()
+	if err := m.Put(1, "root"); err != nil {
+		t.Fatalf("Put(1, root) error = %v", err)
+	}
+	if err := m.Put(1000, "bob"); err != nil {
+		t.Fatalf("Put(1000, bob) error = %v", err)
+	}
+	if err := m.Put(1, "root"); err != nil {
+		t.Errorf("Put() of existing pair error = %v, want nil", err)
+	}
+	if err := m.Put(1, "admin"); !errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("Put(1, admin) error = %v, want %v", err, ErrDuplicateKey)
+	}
+	if err := m.Put(2, "bob"); !errors.Is(err, ErrDuplicateValue) {
+		t.Errorf("Put(2, bob) error = %v, want %v", err, ErrDuplicateValue)
+	}
+
+	if v, ok := m.Get(1000); !ok || v != "bob" {
+		t.Errorf("Get(1000) = %q, %t, want bob, true", v, ok)
+	}
+	if k, ok := m.GetKey("root"); !ok || k != 1 {
+		t.Errorf("GetKey(root) = %d, %t, want 1, true", k, ok)
+	}
+
+	m.DeleteValue("bob")
+	if _, ok := m.Get(1000); ok || m.Len() != 1 {
+		t.Errorf("DeleteValue(bob) left pair, len %d", m.Len())
+	}
+	m.Delete(1)
+	if _, ok := m.GetKey("root"); ok || m.Len() != 0 {
+		t.Errorf("Delete(1) left pair, len %d", m.Len())
+	}
+}
@@ -0,0 +1,70 @@
+package gobag
+
+import (
+	"iter"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+// TruncateTo returns t rounded down to a multiple of d on the wall
+// clock of t's location. Unlike time.Time.Truncate, which counts from
+// the zero time in UTC, hourly or daily buckets of local times start
+// on the local hour or at local midnight. Durations longer than a day
+// are rounded down to whole days, and count days from 1970-01-01. If
+// d is not positive, t is returned unchanged, without its monotonic
+// clock reading.
+func TruncateTo(t time.Time, d time.Duration) time.Time {
+	if d <= 0 {
+		return t.Round(0)
+	}
+
+	year, month, mday := t.Date()
+	if d >= day {
+		days := int64(d / day)
+		n := time.Date(year, month, mday, 0, 0, 0, 0, time.UTC).Unix() / int64(day/time.Second)
+		rem := n % days
+		if rem < 0 {
+			rem += days
+		}
+		return time.Date(year, month, mday-int(rem), 0, 0, 0, 0, t.Location())
+	}
+
+	hour, minute, sec := t.Clock()
+	wall := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute +
+		time.Duration(sec)*time.Second + time.Duration(t.Nanosecond())
+	wall -= wall % d
+	return time.Date(year, month, mday, 0, 0, 0, int(wall), t.Location())
+}
+
+// BucketKey returns a sortable key identifying the bucket of duration
+// d holding t, as aligned by TruncateTo. The key is the start of the
+// bucket formatted with the precision d needs: a date for buckets of
+// whole days, and a time with its zone offset otherwise.
+func BucketKey(t time.Time, d time.Duration) string {
+	start := TruncateTo(t, d)
+	switch {
+	case d >= day && d%day == 0:
+		return start.Format(time.DateOnly)
+	case d%time.Minute == 0:
+		return start.Format("2006-01-02T15:04Z07:00")
+	case d%time.Second == 0:
+		return start.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return start.Format("2006-01-02T15:04:05.000000000Z07:00")
+}
+
+// RangeTimes yields start and the times following it every step, up to
+// but not including end. Nothing is yielded if step is not positive.
+func RangeTimes(start, end time.Time, step time.Duration) iter.Seq[time.Time] {
+	return func(yield func(time.Time) bool) {
+		if step <= 0 {
+			return
+		}
+		for t := start; t.Before(end); t = t.Add(step) {
+			if !yield(t) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package gobag
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTruncateTo(t *testing.T) {
+	oslo, err := time.LoadLocation("Europe/Oslo")
+	if err != nil {
+		t.Skip("time zone data not available")
+	}
+	india := time.FixedZone("IST", 5*60*60+30*60)
+
+	tests := []struct {
+		input    time.Time
+		d        time.Duration
+		expected time.Time
+	}{
+		{time.Date(2024, 5, 1, 12, 34, 56, 789, time.UTC), time.Minute, time.Date(2024, 5, 1, 12, 34, 0, 0, time.UTC)},
+		{time.Date(2024, 5, 1, 12, 34, 56, 0, time.UTC), 15 * time.Minute, time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)},
+		// time.Time.Truncate would give 12:30 in IST.
+		{time.Date(2024, 5, 1, 12, 34, 0, 0, india), time.Hour, time.Date(2024, 5, 1, 12, 0, 0, 0, india)},
+		{time.Date(2024, 5, 1, 12, 34, 0, 0, india), day, time.Date(2024, 5, 1, 0, 0, 0, 0, india)},
+		{time.Date(2024, 5, 1, 1, 0, 0, 0, oslo), day, time.Date(2024, 5, 1, 0, 0, 0, 0, oslo)},
+		{time.Date(2024, 5, 3, 9, 0, 0, 0, time.UTC), 7 * day, time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)},
+		{time.Date(1969, 12, 31, 9, 0, 0, 0, time.UTC), 7 * day, time.Date(1969, 12, 25, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 5, 1, 12, 34, 56, 0, time.UTC), 0, time.Date(2024, 5, 1, 12, 34, 56, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := TruncateTo(tt.input, tt.d); !got.Equal(tt.expected) {
+			t.Errorf("TruncateTo(%v, %v) = %v, want %v", tt.input, tt.d, got, tt.expected)
+		}
+	}
+}
+
+func TestBucketKey(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 12, 34, 56, 789, time.UTC)
+	tests := []struct {
+		d        time.Duration
+		expected string
+	}{
+		{day, "2024-05-01"},
+		{time.Hour, "2024-05-01T12:00Z"},
+		{5 * time.Minute, "2024-05-01T12:30Z"},
+		{10 * time.Second, "2024-05-01T12:34:50Z"},
+		{time.Millisecond, "2024-05-01T12:34:56.000000000Z"},
+	}
+	for _, tt := range tests {
+		if got := BucketKey(ts, tt.d); got != tt.expected {
+			t.Errorf("BucketKey(%v) = %q, want %q", tt.d, got, tt.expected)
+		}
+	}
+}
+
+func TestRangeTimes(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	var got []time.Time
+	for ts := range RangeTimes(start, start.Add(time.Hour), 20*time.Minute) {
+		got = append(got, ts)
+	}
+	if len(got) != 3 || !got[2].Equal(start.Add(40*time.Minute)) {
+		t.Errorf("RangeTimes() = %v", got)
+	}
+
+	for range RangeTimes(start, start.Add(time.Hour), 0) {
+		t.Fatal("RangeTimes() with zero step yielded a time")
+	}
+}
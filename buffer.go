@@ -0,0 +1,193 @@
+package gobag
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrBufferClosed is returned by BoundedBuffer when putting into a
+// closed buffer, or getting from a closed buffer that has been
+// drained.
+var ErrBufferClosed = errors.New("buffer closed")
+
+// OverflowPolicy selects what a BoundedBuffer does when a value is put
+// into it while it is full.
+type OverflowPolicy int
+
+// Overflow policies.
+const (
+	// BufferBlock makes Put wait until there is room.
+	BufferBlock OverflowPolicy = iota
+	// BufferDropOldest discards the oldest value to make room, so a
+	// slow consumer sees the most recent values.
+	BufferDropOldest
+)
+
+// BoundedBuffer is a first-in, first-out queue of fixed capacity
+// connecting producers and consumers running at different speeds. It
+// is safe for concurrent use.
+type BoundedBuffer[T any] struct {
+	mu      sync.Mutex
+	items   []T // Ring buffer of capacity items.
+	head    int // Index of the oldest item.
+	n       int
+	policy  OverflowPolicy
+	closed  bool
+	dropped uint64
+	changed chan struct{} // Closed and replaced on every change.
+}
+
+// NewBoundedBuffer returns an empty buffer holding at most capacity
+// values. It panics if capacity is less than 1.
+func NewBoundedBuffer[T any](capacity int, policy OverflowPolicy) *BoundedBuffer[T] {
+	if capacity < 1 {
+		panic("gobag: BoundedBuffer capacity must be at least 1")
+	}
+	return &BoundedBuffer[T]{
+		items:   make([]T, capacity),
+		policy:  policy,
+		changed: make(chan struct{}),
+	}
+}
+
+// notify wakes up the goroutines waiting for a change. It must be
+// called with the lock held.
+func (b *BoundedBuffer[T]) notify() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// wait waits for the next change after the lock is released. It must
+// be called with the lock held, and returns with it held.
+func (b *BoundedBuffer[T]) wait(ctx context.Context) error {
+	changed := b.changed
+	b.mu.Unlock()
+	defer b.mu.Lock()
+
+	select {
+	case <-changed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Put adds v to the buffer. If the buffer is full, it waits for room
+// or drops the oldest value, depending on the overflow policy. Returns
+// ErrBufferClosed if the buffer is closed, or ctx.Err() if the context
+// ends while waiting.
+func (b *BoundedBuffer[T]) Put(ctx context.Context, v T) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for !b.closed && b.n == len(b.items) && b.policy == BufferBlock {
+		if err := b.wait(ctx); err != nil {
+			return err
+		}
+	}
+	if b.closed {
+		return ErrBufferClosed
+	}
+	b.put(v)
+	return nil
+}
+
+// TryPut is like Put, but does not wait. It reports whether v was
+// added.
+func (b *BoundedBuffer[T]) TryPut(v T) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed || b.n == len(b.items) && b.policy == BufferBlock {
+		return false
+	}
+	b.put(v)
+	return true
+}
+
+func (b *BoundedBuffer[T]) put(v T) {
+	if b.n == len(b.items) {
+		b.pop()
+		b.dropped++
+	}
+	b.items[(b.head+b.n)%len(b.items)] = v
+	b.n++
+	b.notify()
+}
+
+// Get removes and returns the oldest value in the buffer, waiting for
+// one if the buffer is empty. After Close, the remaining values are
+// still returned, and then ErrBufferClosed. Returns ctx.Err() if the
+// context ends while waiting.
+func (b *BoundedBuffer[T]) Get(ctx context.Context) (T, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for !b.closed && b.n == 0 {
+		if err := b.wait(ctx); err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+	if b.n == 0 {
+		var zero T
+		return zero, ErrBufferClosed
+	}
+	v := b.pop()
+	b.notify()
+	return v, nil
+}
+
+// TryGet is like Get, but does not wait. It reports whether a value
+// was returned.
+func (b *BoundedBuffer[T]) TryGet() (T, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.n == 0 {
+		var zero T
+		return zero, false
+	}
+	v := b.pop()
+	b.notify()
+	return v, true
+}
+
+func (b *BoundedBuffer[T]) pop() T {
+	var zero T
+	v := b.items[b.head]
+	b.items[b.head] = zero
+	b.head = (b.head + 1) % len(b.items)
+	b.n--
+	return v
+}
+
+// Close closes the buffer for Put, waking up waiting producers and
+// consumers. Close may be called more than once.
+func (b *BoundedBuffer[T]) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.closed {
+		b.closed = true
+		b.notify()
+	}
+}
+
+// Len returns the number of values in the buffer.
+func (b *BoundedBuffer[T]) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.n
+}
+
+// Dropped returns the number of values discarded by the
+// BufferDropOldest policy.
+func (b *BoundedBuffer[T]) Dropped() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.dropped
+}
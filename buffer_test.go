@@ -0,0 +1,110 @@
+package gobag
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBoundedBufferBlock(t *testing.T) {
+	ctx := context.Background()
+	b := NewBoundedBuffer[int](2, BufferBlock)
+	b.Put(ctx, 1)
+	b.Put(ctx, 2)
+	if b.TryPut(3) {
+		t.Error("TryPut() on full buffer succeeded")
+	}
+
+	put := make(chan error)
+	go func() { put <- b.Put(ctx, 3) }()
+	select {
+	case <-put:
+		t.Fatal("Put() on full buffer did not block")
+	case <-time.After(20 * time.Millisecond):
+	}
+	if v, err := b.Get(ctx); v != 1 || err != nil {
+		t.Errorf("Get() = %d, %v, want 1, nil", v, err)
+	}
+	if err := <-put; err != nil {
+		t.Errorf("Put() error = %v", err)
+	}
+
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := b.Put(timeout, 4); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Put() with expiring context error = %v", err)
+	}
+
+	b.Close()
+	b.Close()
+	if err := b.Put(ctx, 5); !errors.Is(err, ErrBufferClosed) {
+		t.Errorf("Put() after Close error = %v", err)
+	}
+	var got []int
+	for {
+		v, err := b.Get(ctx)
+		if err != nil {
+			if !errors.Is(err, ErrBufferClosed) {
+				t.Errorf("Get() error = %v", err)
+			}
+			break
+		}
+		got = append(got, v)
+	}
+	if !slices.Equal(got, []int{2, 3}) {
+		t.Errorf("drained %v, want [2 3]", got)
+	}
+}
+
+func TestBoundedBufferDropOldest(t *testing.T) {
+	b := NewBoundedBuffer[int](3, BufferDropOldest)
+	for i := range 5 {
+		if !b.TryPut(i) {
+			t.Errorf("TryPut(%d) failed", i)
+		}
+	}
+	if b.Len() != 3 || b.Dropped() != 2 {
+		t.Errorf("Len() = %d, Dropped() = %d, want 3, 2", b.Len(), b.Dropped())
+	}
+	var got []int
+	for v, ok := b.TryGet(); ok; v, ok = b.TryGet() {
+		got = append(got, v)
+	}
+	if !slices.Equal(got, []int{2, 3, 4}) {
+		t.Errorf("values = %v, want [2 3 4]", got)
+	}
+}
+
+func TestBoundedBufferConcurrent(t *testing.T) {
+	ctx := context.Background()
+	b := NewBoundedBuffer[int](4, BufferBlock)
+	var wg sync.WaitGroup
+	for p := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 250 {
+				b.Put(ctx, p*250+i)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		b.Close()
+	}()
+
+	seen := make([]bool, 1000)
+	for {
+		v, err := b.Get(ctx)
+		if err != nil {
+			break
+		}
+		seen[v] = true
+	}
+	if i := slices.Index(seen, false); i >= 0 {
+		t.Errorf("value %d not received", i)
+	}
+}
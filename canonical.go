@@ -0,0 +1,49 @@
+package gobag
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// CanonicalKey returns a key for s that is equal for strings made of
+// the same runes in any order, such as anagrams. Each rune is first
+// passed through normalizer, which may drop it by returning a negative
+// value, as with strings.Map. A nil normalizer folds letters to lower
+// case and drops everything but letters and digits, so "Foo-Bar",
+// "bar_foo" and "OOF BRA" share a key.
+func CanonicalKey(s string, normalizer func(rune) rune) string {
+	if normalizer == nil {
+		normalizer = canonicalRune
+	}
+	runes := []rune(strings.Map(normalizer, s))
+	slices.Sort(runes)
+	return string(runes)
+}
+
+// canonicalRune is the default normalizer of CanonicalKey.
+func canonicalRune(r rune) rune {
+	if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+		return -1
+	}
+	return unicode.ToLower(r)
+}
+
+// GroupByCanonical groups the tokens sharing a CanonicalKey, using
+// normalizer as CanonicalKey does. The groups are returned in order of
+// their first token, and the tokens of a group in input order.
+func GroupByCanonical(tokens []string, normalizer func(rune) rune) [][]string {
+	index := make(map[string]int, len(tokens))
+	groups := make([][]string, 0)
+	for _, token := range tokens {
+		key := CanonicalKey(token, normalizer)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], token)
+	}
+	return groups
+}
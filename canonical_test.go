@@ -0,0 +1,48 @@
+package gobag
+
+import (
+	"reflect"
+	"testing"
+	"unicode"
+)
+
+func TestCanonicalKey(t *testing.T) {
+	tests := []struct {
+		input      string
+		normalizer func(rune) rune
+		expected   string
+	}{
+		{"Foo-Bar", nil, "abfoor"},
+		{"bar_foo", nil, "abfoor"},
+		{"OOF BRA", nil, "abfoor"},
+		{"listen", nil, "eilnst"},
+		{"", nil, ""},
+		{"--", nil, ""},
+		{"b-A", unicode.ToUpper, "-AB"},
+	}
+	for _, tt := range tests {
+		if got := CanonicalKey(tt.input, tt.normalizer); got != tt.expected {
+			t.Errorf("CanonicalKey(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestGroupByCanonical(t *testing.T) {
+	tokens := []string{"max-size", "listen", "SizeMax", "port", "silent", "max_size", "Port"}
+	expected := [][]string{
+		{"max-size", "SizeMax", "max_size"},
+		{"listen", "silent"},
+		{"port", "Port"},
+	}
+	if got := GroupByCanonical(tokens, nil); !reflect.DeepEqual(got, expected) {
+		t.Errorf("GroupByCanonical() = %q, want %q", got, expected)
+	}
+
+	expected = [][]string{{"port"}, {"Port"}}
+	if got := GroupByCanonical([]string{"port", "Port"}, func(r rune) rune { return r }); !reflect.DeepEqual(got, expected) {
+		t.Errorf("GroupByCanonical() with identity = %q, want %q", got, expected)
+	}
+	if got := GroupByCanonical(nil, nil); len(got) != 0 {
+		t.Errorf("GroupByCanonical(nil) = %q, want none", got)
+	}
+}
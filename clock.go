@@ -0,0 +1,193 @@
+package gobag
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Clock tells the time and creates timers. Helpers that depend on time
+// accept a Clock, so tests can use a FakeClock instead of waiting for
+// real time to pass.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After waits for the duration d to elapse and then sends the
+	// current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+	// NewTimer returns a Timer sending the current time on its channel
+	// after at least the duration d.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a timer created by a Clock, like time.Timer.
+type Timer interface {
+	// C returns the channel on which the time is delivered.
+	C() <-chan time.Time
+	// Stop prevents the timer from firing. It reports whether the
+	// call stopped the timer.
+	Stop() bool
+	// Reset changes the timer to expire after the duration d. It
+	// reports whether the timer had been active.
+	Reset(d time.Duration) bool
+}
+
+// RealClock is the Clock of the time package. The zero value is ready
+// to use.
+type RealClock struct{}
+
+// Now returns time.Now().
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// After returns time.After(d).
+func (RealClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// NewTimer returns a Timer backed by time.NewTimer(d).
+func (RealClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+// FakeClock is a Clock for tests, whose time only changes when Advance
+// or Set is called. Timers fire when the time is moved past their
+// expiry. It is safe for concurrent use.
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// NewFakeClock returns a FakeClock set to now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the time of the clock.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// After returns the channel of a new timer, see NewTimer.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+// NewTimer returns a Timer firing when the clock is moved to d past
+// its current time. A timer with d of zero or less fires at once.
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	t := &fakeTimer{clock: c, c: make(chan time.Time, 1)}
+	t.Reset(d)
+	return t
+}
+
+// Advance moves the time of the clock forward by d, firing the timers
+// that expire in order.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.Set(c.Now().Add(d))
+}
+
+// Set sets the time of the clock, firing the timers that expire in
+// order. Timers do not fire when the time is set backwards.
+func (c *FakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = now
+	c.fire()
+}
+
+// Timers returns the number of active timers, so tests can wait for
+// the code under test to start waiting before advancing the clock.
+func (c *FakeClock) Timers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.timers)
+}
+
+// fire fires the expired timers. It must be called with the lock held.
+func (c *FakeClock) fire() {
+	slices.SortStableFunc(c.timers, func(a, b *fakeTimer) int {
+		return a.deadline.Compare(b.deadline)
+	})
+	for len(c.timers) > 0 && !c.timers[0].deadline.After(c.now) {
+		t := c.timers[0]
+		c.timers = c.timers[1:]
+		select {
+		case t.c <- c.now:
+		default:
+		}
+	}
+}
+
+type fakeTimer struct {
+	clock    *FakeClock
+	c        chan time.Time
+	deadline time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	return t.remove()
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	active := t.remove()
+	t.deadline = c.now.Add(d)
+	c.timers = append(c.timers, t)
+	c.fire()
+	return active
+}
+
+// remove removes the timer from the active ones, and reports whether
+// it was active. It must be called with the lock held.
+func (t *fakeTimer) remove() bool {
+	i := slices.Index(t.clock.timers, t)
+	if i < 0 {
+		return false
+	}
+	t.clock.timers = slices.Delete(t.clock.timers, i, i+1)
+	return true
+}
+
+// sleepClock is SleepCtx using the timers of clock.
+func sleepClock(ctx context.Context, clock Clock, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := clock.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C():
+		return nil
+	}
+}
@@ -0,0 +1,104 @@
+package gobag
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(start)
+
+	late := c.NewTimer(2 * time.Second)
+	early := c.After(time.Second)
+	stopped := c.NewTimer(time.Second)
+	if !stopped.Stop() || stopped.Stop() {
+		t.Error("Stop() did not report the timer active exactly once")
+	}
+	if c.Timers() != 2 {
+		t.Errorf("Timers() = %d, want 2", c.Timers())
+	}
+
+	c.Advance(1500 * time.Millisecond)
+	select {
+	case now := <-early:
+		if !now.Equal(start.Add(1500 * time.Millisecond)) {
+			t.Errorf("timer fired with %v", now)
+		}
+	default:
+		t.Error("timer did not fire")
+	}
+	select {
+	case <-late.C():
+		t.Error("timer fired early")
+	case <-stopped.C():
+		t.Error("stopped timer fired")
+	default:
+	}
+
+	if late.Reset(time.Second) != true {
+		t.Error("Reset() of active timer reported it inactive")
+	}
+	c.Advance(600 * time.Millisecond)
+	select {
+	case <-late.C():
+		t.Error("reset timer fired at its old expiry")
+	default:
+	}
+	c.Set(start.Add(time.Hour))
+	select {
+	case <-late.C():
+	default:
+		t.Error("reset timer did not fire")
+	}
+
+	select {
+	case <-c.After(0):
+	default:
+		t.Error("After(0) did not fire at once")
+	}
+	if got := c.Now(); !got.Equal(start.Add(time.Hour)) {
+		t.Errorf("Now() = %v", got)
+	}
+}
+
+func TestSleepClock(t *testing.T) {
+	c := NewFakeClock(time.Time{})
+	done := make(chan error)
+	go func() { done <- sleepClock(context.Background(), c, time.Minute) }()
+
+	for c.Timers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	c.Advance(time.Minute)
+	if err := <-done; err != nil {
+		t.Errorf("sleepClock() = %v", err)
+	}
+}
+
+func TestClockHelpers(t *testing.T) {
+	c := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	sw := NewStopwatchClock(c)
+	c.Advance(2 * time.Second)
+	sw.Lap("read")
+	c.Advance(time.Second)
+	sw.Lap("parse")
+	if got := sw.String(); got != "read=2s parse=1s total=3s" {
+		t.Errorf("Stopwatch = %q", got)
+	}
+
+	var last ProgressInfo
+	p := NewProgressClock(100, time.Minute, func(info ProgressInfo) { last = info }, c)
+	c.Advance(30 * time.Second)
+	p.Add(25)
+	if last.Done != 0 {
+		t.Errorf("Progress reported before the interval: %+v", last)
+	}
+	c.Advance(30 * time.Second)
+	p.Add(25)
+	if last.Done != 50 || last.Elapsed != time.Minute || last.ETA != time.Minute {
+		t.Errorf("Progress reported %+v", last)
+	}
+}
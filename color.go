@@ -0,0 +1,170 @@
+package gobag
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Style is a set of terminal text attributes and colors, rendered as
+// an ANSI SGR escape sequence. Attributes are combined with |, as in
+// Bold|FgRed; at most one foreground and one background color may be
+// set.
+type Style uint32
+
+// Text attributes.
+const (
+	Bold Style = 1 << iota
+	Dim
+	Italic
+	Underline
+	Reverse
+)
+
+// Foreground colors.
+const (
+	FgBlack Style = (30 + iota) << 16
+	FgRed
+	FgGreen
+	FgYellow
+	FgBlue
+	FgMagenta
+	FgCyan
+	FgWhite
+)
+
+// Bright foreground colors.
+const (
+	FgHiBlack Style = (90 + iota) << 16
+	FgHiRed
+	FgHiGreen
+	FgHiYellow
+	FgHiBlue
+	FgHiMagenta
+	FgHiCyan
+	FgHiWhite
+)
+
+// Background colors.
+const (
+	BgBlack Style = (40 + iota) << 24
+	BgRed
+	BgGreen
+	BgYellow
+	BgBlue
+	BgMagenta
+	BgCyan
+	BgWhite
+)
+
+// styleAttributes maps attribute bits to their SGR codes.
+var styleAttributes = []struct {
+	style Style
+	code  int
+}{
+	{Bold, 1}, {Dim, 2}, {Italic, 3}, {Underline, 4}, {Reverse, 7},
+}
+
+// Sequence returns the escape sequence turning the style on, or an
+// empty string for the zero Style.
+func (s Style) Sequence() string {
+	codes := make([]string, 0, 4)
+	for _, a := range styleAttributes {
+		if s&a.style != 0 {
+			codes = append(codes, strconv.Itoa(a.code))
+		}
+	}
+	if fg := s >> 16 & 0xff; fg != 0 {
+		codes = append(codes, strconv.Itoa(int(fg)))
+	}
+	if bg := s >> 24; bg != 0 {
+		codes = append(codes, strconv.Itoa(int(bg)))
+	}
+	if len(codes) == 0 {
+		return ""
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m"
+}
+
+// Render returns text wrapped in the style's escape sequences, or text
+// unchanged if color is disabled. See ColorEnabled.
+func (s Style) Render(text string) string {
+	if !ColorEnabled() {
+		return text
+	}
+	return s.render(text)
+}
+
+func (s Style) render(text string) string {
+	seq := s.Sequence()
+	if seq == "" || text == "" {
+		return text
+	}
+	return seq + text + "\x1b[0m"
+}
+
+// Sprint formats its operands like fmt.Sprint and renders the result
+// in the style.
+func (s Style) Sprint(a ...any) string {
+	return s.Render(fmt.Sprint(a...))
+}
+
+// Sprintf formats according to a format specifier like fmt.Sprintf
+// and renders the result in the style.
+func (s Style) Sprintf(format string, a ...any) string {
+	return s.Render(fmt.Sprintf(format, a...))
+}
+
+// Fprintf formats like fmt.Fprintf and writes the result to w,
+// rendered in the style only if ColorSupported reports that w accepts
+// color.
+func (s Style) Fprintf(w io.Writer, format string, a ...any) (int, error) {
+	text := fmt.Sprintf(format, a...)
+	if ColorSupported(w) {
+		text = s.render(text)
+	}
+	return io.WriteString(w, text)
+}
+
+var (
+	colorOverride atomic.Int32 // 0 detect, 1 enabled, 2 disabled
+	colorStdout   = sync.OnceValue(func() bool { return ColorSupported(os.Stdout) })
+)
+
+// ColorEnabled reports whether Style.Render, Sprint and Sprintf emit
+// escape sequences. Unless set with SetColorEnabled, color is enabled
+// when ColorSupported reports that standard output accepts it.
+func ColorEnabled() bool {
+	switch colorOverride.Load() {
+	case 1:
+		return true
+	case 2:
+		return false
+	}
+	return colorStdout()
+}
+
+// SetColorEnabled overrides the detection of ColorEnabled, e.g. for a
+// --color command line flag.
+func SetColorEnabled(enabled bool) {
+	colorOverride.Store(Ternary[int32](enabled, 1, 2))
+}
+
+// ColorSupported reports whether w is a terminal that accepts color.
+// Following https://no-color.org, a non-empty NO_COLOR environment
+// variable disables color, as does TERM=dumb.
+func ColorSupported(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
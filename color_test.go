@@ -0,0 +1,66 @@
+package gobag
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestStyleSequence(t *testing.T) {
+	tests := []struct {
+		style    Style
+		expected string
+	}{
+		{0, ""},
+		{Bold, "\x1b[1m"},
+		{FgRed, "\x1b[31m"},
+		{Bold | Underline | FgHiCyan, "\x1b[1;4;96m"},
+		{FgWhite | BgBlue, "\x1b[37;44m"},
+	}
+	for _, tt := range tests {
+		if got := tt.style.Sequence(); got != tt.expected {
+			t.Errorf("Style(%#x).Sequence() = %q, want %q", uint32(tt.style), got, tt.expected)
+		}
+	}
+}
+
+func TestStyleRender(t *testing.T) {
+	defer colorOverride.Store(0)
+
+	SetColorEnabled(true)
+	if got := (Bold | FgRed).Sprintf("%d errors", 3); got != "\x1b[1;31m3 errors\x1b[0m" {
+		t.Errorf("Sprintf() = %q", got)
+	}
+	if got := FgGreen.Sprint(""); got != "" {
+		t.Errorf("Sprint(\"\") = %q, want empty", got)
+	}
+
+	SetColorEnabled(false)
+	if got := (Bold | FgRed).Sprintf("%d errors", 3); got != "3 errors" {
+		t.Errorf("Sprintf() with color disabled = %q", got)
+	}
+}
+
+func TestColorSupported(t *testing.T) {
+	var buf bytes.Buffer
+	if ColorSupported(&buf) {
+		t.Error("ColorSupported(buffer) = true, want false")
+	}
+	if _, err := FgRed.Fprintf(&buf, "x=%d", 1); err != nil || buf.String() != "x=1" {
+		t.Errorf("Fprintf() wrote %q, %v, want %q", buf.String(), err, "x=1")
+	}
+
+	f, err := os.CreateTemp(t.TempDir(), "color")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if ColorSupported(f) {
+		t.Error("ColorSupported(regular file) = true, want false")
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if ColorSupported(os.Stdout) {
+		t.Error("ColorSupported() = true with NO_COLOR set")
+	}
+}
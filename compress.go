@@ -0,0 +1,66 @@
+package gobag
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// OpenMaybeCompressed opens the named file for reading, transparently
+// decompressing it if it is gzip or bzip2 compressed. The compression
+// is detected from the content rather than the file name, so plain
+// files named .gz and compressed files without an extension are both
+// read correctly.
+func OpenMaybeCompressed(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := NewMaybeCompressedReader(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &compressedFile{Reader: r, f: f}, nil
+}
+
+// NewMaybeCompressedReader returns a reader decompressing r if it is
+// gzip or bzip2 compressed, and reading it as is otherwise.
+func NewMaybeCompressedReader(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(3)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return zr, nil
+	case bytes.Equal(magic, []byte("BZh")):
+		return bzip2.NewReader(br), nil
+	}
+	return br, nil
+}
+
+type compressedFile struct {
+	io.Reader
+	f *os.File
+}
+
+func (c *compressedFile) Close() error {
+	var err error
+	if zr, ok := c.Reader.(*gzip.Reader); ok {
+		err = zr.Close()
+	}
+	return errors.Join(err, c.f.Close())
+}
@@ -0,0 +1,79 @@
+package gobag
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// bzip2Hello is "hello, world\n" compressed with bzip2, which has no
+// encoder in the standard library.
+const bzip2Hello = "QlpoOTFBWSZTWVSkl4QAAALRgAAQQAQGRJCAIAAxADAgaGIASdSyHz8XckU4UJBUpJeE"
+
+func TestOpenMaybeCompressed(t *testing.T) {
+	dir := t.TempDir()
+	const content = "hello, world\n"
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte(content))
+	zw.Close()
+
+	bz, err := base64.StdEncoding.DecodeString(bzip2Hello)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string][]byte{
+		"plain.txt":    []byte(content),
+		"data.gz":      gz.Bytes(),
+		"noext":        gz.Bytes(),
+		"data.bz2":     bz,
+		"empty":        nil,
+		"short":        []byte("h"),
+		"notreally.gz": []byte(content),
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		rc, err := OpenMaybeCompressed(path)
+		if err != nil {
+			t.Fatalf("OpenMaybeCompressed(%s) error = %v", name, err)
+		}
+		got, err := io.ReadAll(rc)
+		if err != nil {
+			t.Fatalf("reading %s: %v", name, err)
+		}
+		if err := rc.Close(); err != nil {
+			t.Fatalf("Close(%s) error = %v", name, err)
+		}
+
+		want := content
+		switch name {
+		case "empty":
+			want = ""
+		case "short":
+			want = "h"
+		}
+		if string(got) != want {
+			t.Errorf("OpenMaybeCompressed(%s) read %q, want %q", name, got, want)
+		}
+	}
+
+	if _, err := OpenMaybeCompressed(filepath.Join(dir, "missing")); !os.IsNotExist(err) {
+		t.Errorf("OpenMaybeCompressed(missing) error = %v, want not exist", err)
+	}
+
+	bad := filepath.Join(dir, "bad.gz")
+	os.WriteFile(bad, []byte{0x1f, 0x8b, 0}, 0o644)
+	if _, err := OpenMaybeCompressed(bad); err == nil {
+		t.Error("OpenMaybeCompressed(bad.gz) succeeded, want error")
+	}
+}
@@ -0,0 +1,140 @@
+package gobag
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// SleepCtx pauses the current goroutine for at least the duration d,
+// or until ctx is done, whichever happens first. It returns ctx.Err()
+// if the context ended the sleep early, otherwise nil.
+func SleepCtx(ctx context.Context, d time.Duration) error {
+	return sleepClock(ctx, RealClock{}, d)
+}
+
+// AfterFuncCtx waits for the duration d to elapse and then calls f in
+// its own goroutine, unless ctx is done first. The returned stop
+// function cancels the call, and reports whether it prevented f from
+// being run.
+func AfterFuncCtx(ctx context.Context, d time.Duration, f func()) (stop func() bool) {
+	var unregister func() bool
+	ready := make(chan struct{})
+	timer := time.AfterFunc(d, func() {
+		<-ready
+		unregister()
+		if ctx.Err() == nil {
+			f()
+		}
+	})
+	unregister = context.AfterFunc(ctx, func() {
+		timer.Stop()
+	})
+	close(ready)
+
+	return func() bool {
+		unregister()
+		return timer.Stop()
+	}
+}
+
+// Ticker delivers ticks at intervals like time.Ticker, but stops by
+// itself when its context is done. Unlike time.Ticker the channel C is
+// closed when the ticker stops, so it is safe to range over.
+type Ticker struct {
+	C <-chan time.Time
+
+	ticker *time.Ticker
+	cancel context.CancelFunc
+}
+
+// NewTicker returns a new Ticker sending the current time on its
+// channel every period d until ctx is done or Stop is called. It
+// panics if d is not positive.
+func NewTicker(ctx context.Context, d time.Duration) *Ticker {
+	ctx, cancel := context.WithCancel(ctx)
+	c := make(chan time.Time, 1)
+	t := &Ticker{
+		C:      c,
+		ticker: time.NewTicker(d),
+		cancel: cancel,
+	}
+
+	go func() {
+		defer close(c)
+		defer t.ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case tick := <-t.ticker.C:
+				// Drop ticks for slow receivers, as time.Ticker does.
+				select {
+				case c <- tick:
+				default:
+				}
+			}
+		}
+	}()
+
+	return t
+}
+
+// Reset stops the ticker and resets its period to the duration d.
+func (t *Ticker) Reset(d time.Duration) {
+	t.ticker.Reset(d)
+}
+
+// Stop turns off the ticker and closes its channel. Stop may be
+// called more than once.
+func (t *Ticker) Stop() {
+	t.cancel()
+}
+
+// ErrTimeout is returned by RunWithTimeout and RunWithTimeoutCtx when
+// the function did not complete within the allotted time.
+var ErrTimeout = errors.New("operation timed out")
+
+// RunWithTimeout runs fn in a new goroutine and waits for it to return,
+// for at most the duration d or until ctx is done. It returns
+// ErrTimeout if d is exceeded, or ctx.Err() if the context ends first.
+//
+// Go offers no way to stop a running goroutine, so when RunWithTimeout
+// gives up, fn keeps running in the background until it returns on its
+// own and its result is discarded. Functions that may run unbounded
+// should use RunWithTimeoutCtx and watch the context instead.
+func RunWithTimeout[T any](ctx context.Context, d time.Duration, fn func() (T, error)) (T, error) {
+	return RunWithTimeoutCtx(ctx, d, func(context.Context) (T, error) {
+		return fn()
+	})
+}
+
+// RunWithTimeoutCtx is like RunWithTimeout, but passes fn a context
+// that is canceled when the timeout is exceeded or ctx is done, so fn
+// can stop cooperatively and release its goroutine.
+func RunWithTimeoutCtx[T any](ctx context.Context, d time.Duration, fn func(ctx context.Context) (T, error)) (T, error) {
+	ctx, cancel := context.WithTimeoutCause(ctx, d, ErrTimeout)
+	defer cancel()
+
+	type result struct {
+		value T
+		err   error
+	}
+	// Buffered, so the goroutine can always deliver and exit.
+	done := make(chan result, 1)
+	go func() {
+		v, err := fn(ctx)
+		done <- result{v, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero T
+		if cause := context.Cause(ctx); errors.Is(cause, ErrTimeout) {
+			return zero, ErrTimeout
+		}
+		return zero, ctx.Err()
+	}
+}
@@ -0,0 +1,106 @@
+package gobag
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSleepCtx(t *testing.T) {
+	if err := SleepCtx(context.Background(), time.Millisecond); err != nil {
+		t.Fatalf("SleepCtx() error = %v, want nil", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	if err := SleepCtx(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Fatalf("SleepCtx() error = %v, want %v", err, context.Canceled)
+	}
+	if time.Since(start) > time.Second {
+		t.Fatalf("SleepCtx() did not return when context was canceled")
+	}
+}
+
+func TestAfterFuncCtx(t *testing.T) {
+	var called atomic.Bool
+	done := make(chan struct{})
+	AfterFuncCtx(context.Background(), time.Millisecond, func() {
+		called.Store(true)
+		close(done)
+	})
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("AfterFuncCtx() did not call f")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	called.Store(false)
+	AfterFuncCtx(ctx, 20*time.Millisecond, func() { called.Store(true) })
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	if called.Load() {
+		t.Fatal("AfterFuncCtx() called f after context was canceled")
+	}
+
+	stop := AfterFuncCtx(context.Background(), time.Hour, func() {})
+	if !stop() {
+		t.Fatal("stop() = false, want true")
+	}
+}
+
+func TestTicker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ticker := NewTicker(ctx, time.Millisecond)
+
+	var ticks int
+	for range ticker.C {
+		ticks++
+		if ticks == 3 {
+			cancel()
+		}
+	}
+	if ticks < 3 {
+		t.Fatalf("got %d ticks, want at least 3", ticks)
+	}
+
+	ticker = NewTicker(context.Background(), time.Hour)
+	ticker.Stop()
+	ticker.Stop()
+	if _, ok := <-ticker.C; ok {
+		t.Fatal("ticker channel not closed after Stop")
+	}
+}
+
+func TestRunWithTimeout(t *testing.T) {
+	v, err := RunWithTimeout(context.Background(), time.Second, func() (int, error) {
+		return 42, nil
+	})
+	if err != nil || v != 42 {
+		t.Fatalf("RunWithTimeout() = %d, %v, want 42, nil", v, err)
+	}
+
+	block := make(chan struct{})
+	defer close(block)
+	_, err = RunWithTimeout(context.Background(), time.Millisecond, func() (int, error) {
+		<-block
+		return 0, nil
+	})
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("RunWithTimeout() error = %v, want %v", err, ErrTimeout)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = RunWithTimeoutCtx(ctx, time.Hour, func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("RunWithTimeoutCtx() error = %v, want %v", err, context.Canceled)
+	}
+}
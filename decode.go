@@ -0,0 +1,217 @@
+package gobag
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encoding is a text encoding recognized by DecodeReader.
+type Encoding int
+
+// Encodings recognized by DecodeReader.
+const (
+	EncodingAuto Encoding = iota // Detect the encoding.
+	EncodingUTF8
+	EncodingUTF16LE
+	EncodingUTF16BE
+	EncodingLatin1 // ISO 8859-1.
+)
+
+// String returns the name of the encoding.
+func (e Encoding) String() string {
+	switch e {
+	case EncodingAuto:
+		return "auto"
+	case EncodingUTF8:
+		return "UTF-8"
+	case EncodingUTF16LE:
+		return "UTF-16LE"
+	case EncodingUTF16BE:
+		return "UTF-16BE"
+	case EncodingLatin1:
+		return "ISO-8859-1"
+	}
+	return "unknown"
+}
+
+// DecodeOptions holds optional settings for NewDecodeReader.
+type DecodeOptions struct {
+	// Encoding is the encoding of the input. If it is EncodingAuto,
+	// the encoding is detected.
+	Encoding Encoding
+}
+
+// sniffSize is the number of bytes inspected to detect the encoding.
+const sniffSize = 4096
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// DecodeReader converts text to UTF-8 before it reaches parsers that
+// expect it, such as Fields, which would otherwise silently replace
+// invalid bytes.
+type DecodeReader struct {
+	r        io.Reader
+	encoding Encoding
+}
+
+// NewDecodeReader returns a DecodeReader reading from r. A byte order
+// mark at the start of the input is removed, and selects the encoding
+// when detecting it. Without a byte order mark, input with many zero
+// bytes in alternating positions is taken to be UTF-16, input that is
+// valid UTF-8 to be UTF-8, and anything else to be Latin-1. Detection
+// looks at the first 4096 bytes only.
+func NewDecodeReader(r io.Reader, opts DecodeOptions) (*DecodeReader, error) {
+	br := bufio.NewReaderSize(r, sniffSize)
+	sample, err := br.Peek(sniffSize)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, err
+	}
+
+	encoding := opts.Encoding
+	var bom []byte
+	switch {
+	case bytes.HasPrefix(sample, bomUTF8):
+		bom = bomUTF8
+		if encoding == EncodingAuto {
+			encoding = EncodingUTF8
+		}
+	case bytes.HasPrefix(sample, bomUTF16LE):
+		bom = bomUTF16LE
+		if encoding == EncodingAuto {
+			encoding = EncodingUTF16LE
+		}
+	case bytes.HasPrefix(sample, bomUTF16BE):
+		bom = bomUTF16BE
+		if encoding == EncodingAuto {
+			encoding = EncodingUTF16BE
+		}
+	}
+	if encoding == EncodingAuto {
+		encoding = sniffEncoding(sample)
+	}
+	// Only strip a byte order mark of the encoding in use.
+	if (encoding == EncodingUTF8 && bytes.Equal(bom, bomUTF8)) ||
+		(encoding == EncodingUTF16LE && bytes.Equal(bom, bomUTF16LE)) ||
+		(encoding == EncodingUTF16BE && bytes.Equal(bom, bomUTF16BE)) {
+		if _, err := br.Discard(len(bom)); err != nil {
+			return nil, err
+		}
+	}
+
+	d := &DecodeReader{encoding: encoding}
+	switch encoding {
+	case EncodingUTF16LE, EncodingUTF16BE:
+		d.r = &runeDecodeReader{src: br, next: utf16Decoder(encoding == EncodingUTF16BE)}
+	case EncodingLatin1:
+		d.r = &runeDecodeReader{src: br, next: func(src *bufio.Reader) (rune, error) {
+			b, err := src.ReadByte()
+			return rune(b), err
+		}}
+	default:
+		d.r = br
+	}
+
+	return d, nil
+}
+
+// Read implements io.Reader, returning UTF-8.
+func (d *DecodeReader) Read(p []byte) (int, error) {
+	return d.r.Read(p)
+}
+
+// Encoding returns the encoding of the input.
+func (d *DecodeReader) Encoding() Encoding {
+	return d.encoding
+}
+
+func sniffEncoding(sample []byte) Encoding {
+	if len(sample) >= 2 {
+		var evenZeros, oddZeros int
+		for i, b := range sample {
+			if b == 0 {
+				if i%2 == 0 {
+					evenZeros++
+				} else {
+					oddZeros++
+				}
+			}
+		}
+		half := len(sample) / 2
+		switch {
+		case oddZeros > half/2 && evenZeros < oddZeros/4:
+			return EncodingUTF16LE
+		case evenZeros > half/2 && oddZeros < evenZeros/4:
+			return EncodingUTF16BE
+		}
+	}
+
+	// Allow a rune cut off at the end of the sample.
+	for cut := 0; cut < utf8.UTFMax && cut <= len(sample); cut++ {
+		if utf8.Valid(sample[:len(sample)-cut]) {
+			return EncodingUTF8
+		}
+	}
+	return EncodingLatin1
+}
+
+func utf16Decoder(bigEndian bool) func(*bufio.Reader) (rune, error) {
+	unit := func(src *bufio.Reader) (rune, error) {
+		var b [2]byte
+		n, err := io.ReadFull(src, b[:])
+		if n == 1 {
+			return utf8.RuneError, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		if bigEndian {
+			return rune(b[0])<<8 | rune(b[1]), nil
+		}
+		return rune(b[1])<<8 | rune(b[0]), nil
+	}
+
+	return func(src *bufio.Reader) (rune, error) {
+		r1, err := unit(src)
+		if err != nil || !utf16.IsSurrogate(r1) {
+			return r1, err
+		}
+		r2, err := unit(src)
+		if err != nil {
+			return utf8.RuneError, nil
+		}
+		return utf16.DecodeRune(r1, r2), nil
+	}
+}
+
+// runeDecodeReader is an io.Reader returning the runes decoded by next
+// as UTF-8.
+type runeDecodeReader struct {
+	src  *bufio.Reader
+	next func(*bufio.Reader) (rune, error)
+	buf  []byte
+}
+
+func (r *runeDecodeReader) Read(p []byte) (int, error) {
+	for len(r.buf) < len(p) {
+		c, err := r.next(r.src)
+		if err != nil {
+			if len(r.buf) == 0 {
+				return 0, err
+			}
+			break
+		}
+		r.buf = utf8.AppendRune(r.buf, c)
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[:copy(r.buf, r.buf[n:])]
+	return n, nil
+}
@@ -0,0 +1,74 @@
+package gobag
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+func encodeUTF16(s string, bigEndian bool) []byte {
+	var b []byte
+	for _, u := range utf16.Encode([]rune(s)) {
+		if bigEndian {
+			b = append(b, byte(u>>8), byte(u))
+		} else {
+			b = append(b, byte(u), byte(u>>8))
+		}
+	}
+	return b
+}
+
+func TestDecodeReader(t *testing.T) {
+	const text = "key = \"ærø 😀\"\nother = x\n"
+
+	tests := []struct {
+		name     string
+		input    []byte
+		encoding Encoding
+	}{
+		{"utf-8", []byte(text), EncodingUTF8},
+		{"utf-8 bom", append([]byte{0xEF, 0xBB, 0xBF}, text...), EncodingUTF8},
+		{"utf-16le bom", append([]byte{0xFF, 0xFE}, encodeUTF16(text, false)...), EncodingUTF16LE},
+		{"utf-16be bom", append([]byte{0xFE, 0xFF}, encodeUTF16(text, true)...), EncodingUTF16BE},
+		{"utf-16le sniffed", encodeUTF16(text, false), EncodingUTF16LE},
+		{"utf-16be sniffed", encodeUTF16(text, true), EncodingUTF16BE},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := NewDecodeReader(bytes.NewReader(tt.input), DecodeOptions{})
+			if err != nil {
+				t.Fatalf("NewDecodeReader() error = %v", err)
+			}
+			got, err := io.ReadAll(d)
+			if err != nil || string(got) != text {
+				t.Errorf("ReadAll() = %q, %v, want %q", got, err, text)
+			}
+			if d.Encoding() != tt.encoding {
+				t.Errorf("Encoding() = %v, want %v", d.Encoding(), tt.encoding)
+			}
+		})
+	}
+
+	latin1 := []byte("navn = \"K\xf8benhavn\"\n")
+	d, err := NewDecodeReader(bytes.NewReader(latin1), DecodeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(d)
+	if string(got) != "navn = \"København\"\n" || d.Encoding() != EncodingLatin1 {
+		t.Errorf("Latin-1 ReadAll() = %q (%v)", got, d.Encoding())
+	}
+
+	d, _ = NewDecodeReader(strings.NewReader("\xe6"), DecodeOptions{Encoding: EncodingUTF8})
+	if got, _ := io.ReadAll(d); string(got) != "\xe6" {
+		t.Errorf("forced UTF-8 ReadAll() = %q, want input unchanged", got)
+	}
+
+	long := strings.Repeat("å", 3000)
+	d, _ = NewDecodeReader(strings.NewReader(long), DecodeOptions{})
+	if got, _ := io.ReadAll(d); string(got) != long || d.Encoding() != EncodingUTF8 {
+		t.Errorf("long UTF-8 input detected as %v", d.Encoding())
+	}
+}
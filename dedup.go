@@ -0,0 +1,134 @@
+package gobag
+
+import (
+	"errors"
+	"iter"
+	"maps"
+	"slices"
+)
+
+// dedupEntryOverhead approximates the memory used by a buffered value
+// in addition to its bytes.
+const dedupEntryOverhead = 48
+
+// DedupOptions holds optional settings for NewDedupWriter.
+type DedupOptions struct {
+	// MaxMemory is the approximate number of bytes of values buffered
+	// in memory before they are spilled to a temporary file. The
+	// default is 64 MiB.
+	MaxMemory int
+
+	// TempDir is the directory of the temporary files. The default is
+	// os.TempDir().
+	TempDir string
+}
+
+// DedupWriter deduplicates more values than fit in memory. Values are
+// collected in memory until the memory limit is reached, and then
+// written as a sorted run to a temporary file. The results are the
+// unique values of all runs merged, in sorted order.
+//
+// A DedupWriter is not safe for concurrent use. Close must be called
+// to remove its temporary files.
+type DedupWriter struct {
+	opts DedupOptions
+	buf  map[string]struct{}
+	size int
+	runs []*sortedRun
+	err  error
+}
+
+// NewDedupWriter returns a new DedupWriter.
+func NewDedupWriter(opts DedupOptions) *DedupWriter {
+	if opts.MaxMemory <= 0 {
+		opts.MaxMemory = 64 << 20
+	}
+	return &DedupWriter{
+		opts: opts,
+		buf:  make(map[string]struct{}),
+	}
+}
+
+// Write adds the value s. Errors are sticky: after a failed spill to
+// disk, Write and all later calls return the error.
+func (d *DedupWriter) Write(s string) error {
+	if d.err != nil {
+		return d.err
+	}
+	if _, ok := d.buf[s]; ok {
+		return nil
+	}
+	d.buf[s] = struct{}{}
+	d.size += len(s) + dedupEntryOverhead
+	if d.size >= d.opts.MaxMemory {
+		return d.Flush()
+	}
+	return nil
+}
+
+// Flush writes the values buffered in memory to a temporary file,
+// freeing the memory.
+func (d *DedupWriter) Flush() error {
+	if d.err != nil || len(d.buf) == 0 {
+		return d.err
+	}
+
+	run, err := writeRun(d.opts.TempDir, slices.Values(slices.Sorted(maps.Keys(d.buf))))
+	if err != nil {
+		d.err = err
+		return err
+	}
+	d.runs = append(d.runs, run)
+	clear(d.buf)
+	d.size = 0
+
+	if len(d.runs) >= maxRuns {
+		d.err = d.compact()
+	}
+	return d.err
+}
+
+// compact merges all runs into a single one.
+func (d *DedupWriter) compact() error {
+	merged, err := compactRuns(d.opts.TempDir, d.runs, uniqueSorted(mergeRuns(d.runs, lessString)))
+	if merged != nil {
+		d.runs = []*sortedRun{merged}
+	}
+	return err
+}
+
+// Results returns the unique values written, in sorted order. The
+// sequence yields a non-nil error as its last element if the values
+// could not be read back from disk.
+func (d *DedupWriter) Results() iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		if err := d.Flush(); err != nil {
+			yield("", err)
+			return
+		}
+		for s := range uniqueSorted(mergeRuns(d.runs, lessString)) {
+			if !yield(s, nil) {
+				return
+			}
+		}
+		if err := runsErr(d.runs); err != nil {
+			yield("", err)
+		}
+	}
+}
+
+// Close removes the temporary files and discards all values.
+func (d *DedupWriter) Close() error {
+	err := removeRuns(d.runs)
+	d.runs = nil
+	clear(d.buf)
+	d.size = 0
+	if d.err == nil {
+		d.err = errors.New("dedup writer is closed")
+	}
+	return err
+}
+
+func lessString(a, b string) bool {
+	return a < b
+}
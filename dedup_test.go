@@ -0,0 +1,72 @@
+package gobag
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestDedupWriter(t *testing.T) {
+	dir := t.TempDir()
+	// A tiny memory limit spills every few values, and enough values
+	// force the runs to be compacted.
+	d := NewDedupWriter(DedupOptions{MaxMemory: 200, TempDir: dir})
+
+	want := make([]string, 0)
+	for i := range 500 {
+		want = append(want, fmt.Sprintf("value-%03d", i))
+	}
+	for pass := range 3 {
+		for i := range want {
+			v := want[(i*7+pass)%len(want)]
+			if err := d.Write(v); err != nil {
+				t.Fatalf("Write(%q) error = %v", v, err)
+			}
+		}
+	}
+	if err := d.Write(""); err != nil {
+		t.Fatalf("Write(\"\") error = %v", err)
+	}
+	want = append([]string{""}, want...)
+
+	got := make([]string, 0)
+	for v, err := range d.Results() {
+		if err != nil {
+			t.Fatalf("Results() error = %v", err)
+		}
+		got = append(got, v)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Results() returned %d values, want %d", len(got), len(want))
+	}
+
+	if err := d.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Close() left %d temporary files", len(entries))
+	}
+	if err := d.Write("x"); err == nil {
+		t.Error("Write() after Close() succeeded")
+	}
+}
+
+func TestDedupWriterInMemory(t *testing.T) {
+	d := NewDedupWriter(DedupOptions{TempDir: t.TempDir()})
+	defer d.Close()
+	for _, v := range []string{"b", "a", "b", "c", "a"} {
+		d.Write(v)
+	}
+
+	got := make([]string, 0)
+	for v, err := range d.Results() {
+		if err != nil {
+			t.Fatalf("Results() error = %v", err)
+		}
+		got = append(got, v)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Results() = %q, want %q", got, want)
+	}
+}
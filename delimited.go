@@ -0,0 +1,220 @@
+package gobag
+
+import (
+	"bufio"
+	"encoding"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errStop is returned by callbacks to end a read early without error.
+var errStop = errors.New("stop")
+
+// ReadDelimited reads delimited records from r, such as CSV or TSV
+// data, using the first record as a header naming the columns. It
+// yields every following record as a map from column names to values.
+// Records are split by ScanRecords and fields by sep following the
+// quoting and escaping rules of Fields, and values are trimmed and
+// unquoted. Blank lines are skipped.
+//
+// Errors, such as a record with a different number of fields than the
+// header, are yielded with the line number and end the sequence.
+func ReadDelimited(r io.Reader, sep rune) iter.Seq2[map[string]string, error] {
+	return func(yield func(map[string]string, error) bool) {
+		err := readDelimited(r, sep, func(_ int, header, values []string) error {
+			row := make(map[string]string, len(header))
+			for i, name := range header {
+				row[name] = values[i]
+			}
+			if !yield(row, nil) {
+				return errStop
+			}
+			return nil
+		})
+		if err != nil && err != errStop {
+			yield(nil, err)
+		}
+	}
+}
+
+// ReadDelimitedInto is like ReadDelimited, but decodes every record
+// into a struct of type T. A column is stored in the exported field
+// tagged with its name, as in `gobag:"name"`, or else in the field
+// whose name matches the column name case-insensitively. Fields tagged
+// "-" are skipped, as are columns without a field.
+//
+// Fields may be strings, bools, integers, floats, time.Duration,
+// time.Time in any of the built-in layouts of ParseTimeAny, or
+// implement encoding.TextUnmarshaler. Bools are parsed as by
+// DSN.GetBool, and empty values leave fields at their zero value.
+func ReadDelimitedInto[T any](r io.Reader, sep rune) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		typ := reflect.TypeFor[T]()
+		if typ.Kind() != reflect.Struct {
+			yield(zero, fmt.Errorf("cannot decode records into %s, expected a struct", typ))
+			return
+		}
+
+		var fields []int // Field index of each column, or -1.
+		err := readDelimited(r, sep, func(lineno int, header, values []string) error {
+			if fields == nil {
+				fields = structColumns(typ, header)
+			}
+
+			var v T
+			rv := reflect.ValueOf(&v).Elem()
+			for i, f := range fields {
+				if f < 0 || values[i] == "" {
+					continue
+				}
+				if err := setFieldValue(rv.Field(f), values[i]); err != nil {
+					return fmt.Errorf("line %d: column '%s': %w", lineno, header[i], err)
+				}
+			}
+			if !yield(v, nil) {
+				return errStop
+			}
+			return nil
+		})
+		if err != nil && err != errStop {
+			yield(zero, err)
+		}
+	}
+}
+
+// readDelimited reads the header and calls fn with the line number and
+// values of every following record.
+func readDelimited(r io.Reader, sep rune, fn func(lineno int, header, values []string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<24)
+	scanner.Split(NewRecordSplitFunc())
+
+	var header []string
+	var lineno int
+	for scanner.Scan() {
+		record := scanner.Text()
+		lineno++
+		start := lineno
+		lineno += strings.Count(record, "\n")
+		if strings.TrimSpace(record) == "" {
+			continue
+		}
+
+		fields, err := splitRaw(record, sep)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", start, err)
+		}
+		for i, f := range fields {
+			fields[i] = decodeValue(f)
+		}
+
+		if header == nil {
+			seen := make(map[string]bool, len(fields))
+			for _, name := range fields {
+				if name == "" || seen[name] {
+					return fmt.Errorf("line %d: invalid or repeated column name %q", start, name)
+				}
+				seen[name] = true
+			}
+			header = fields
+			continue
+		}
+		if len(fields) != len(header) {
+			return fmt.Errorf("line %d: expected %d fields, got %d", start, len(header), len(fields))
+		}
+		if err := fn(start, header, fields); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("line %d: %w", lineno+1, err)
+	}
+
+	return nil
+}
+
+// structColumns returns the index of the field of the struct type typ
+// storing each column, or -1 for columns without a field.
+func structColumns(typ reflect.Type, header []string) []int {
+	fields := make([]int, len(header))
+	for i, name := range header {
+		fields[i] = -1
+		for j := range typ.NumField() {
+			f := typ.Field(j)
+			if !f.IsExported() {
+				continue
+			}
+			tag, _, _ := ParseTagOptions(f.Tag.Get("gobag"))
+			if tag == name || (tag == "" && strings.EqualFold(f.Name, name)) {
+				fields[i] = j
+				break
+			}
+		}
+	}
+	return fields
+}
+
+var (
+	durationType        = reflect.TypeFor[time.Duration]()
+	timeType            = reflect.TypeFor[time.Time]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// setFieldValue parses s into the field v. Durations and times are
+// checked before encoding.TextUnmarshaler, which *time.Time implements
+// with RFC 3339 only.
+func setFieldValue(v reflect.Value, s string) error {
+	invalid := fmt.Errorf("invalid %s %q", v.Type(), s)
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return invalid
+		}
+		v.SetInt(int64(d))
+	case v.Type() == timeType:
+		t, err := ParseTimeAny(s)
+		if err != nil {
+			return invalid
+		}
+		v.Set(reflect.ValueOf(t))
+	case v.Addr().Type().Implements(textUnmarshalerType):
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	case v.Kind() == reflect.String:
+		v.SetString(s)
+	case v.Kind() == reflect.Bool:
+		b, ok := parseBool(s)
+		if !ok {
+			return invalid
+		}
+		v.SetBool(b)
+	case v.CanInt():
+		n, err := strconv.ParseInt(s, 0, v.Type().Bits())
+		if err != nil {
+			return invalid
+		}
+		v.SetInt(n)
+	case v.CanUint():
+		n, err := strconv.ParseUint(s, 0, v.Type().Bits())
+		if err != nil {
+			return invalid
+		}
+		v.SetUint(n)
+	case v.CanFloat():
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return invalid
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
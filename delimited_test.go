@@ -0,0 +1,182 @@
+package gobag
+
+import (
+	"log/slog"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testDelimited = `name, port, addr, enabled, timeout
+web, 80, 10.0.0.1, yes, 5s
+
+"db, primary", 5432, 10.0.0.2, no, "1m"
+"multi
+line", 0x10, ::1, true, 0s
+`
+
+func TestReadDelimited(t *testing.T) {
+	var rows []map[string]string
+	for row, err := range ReadDelimited(strings.NewReader(testDelimited), ',') {
+		if err != nil {
+			t.Fatalf("ReadDelimited() error = %v", err)
+		}
+		rows = append(rows, row)
+	}
+
+	if len(rows) != 3 {
+		t.Fatalf("ReadDelimited() returned %d rows, want 3", len(rows))
+	}
+	want := map[string]string{"name": "db, primary", "port": "5432", "addr": "10.0.0.2", "enabled": "no", "timeout": "1m"}
+	if !reflect.DeepEqual(rows[1], want) {
+		t.Errorf("row 2 = %v, want %v", rows[1], want)
+	}
+	if rows[2]["name"] != "multi\nline" {
+		t.Errorf("row 3 name = %q", rows[2]["name"])
+	}
+
+	tsv := "a\tb\n1\t2\n"
+	for row, err := range ReadDelimited(strings.NewReader(tsv), '\t') {
+		if err != nil || row["a"] != "1" || row["b"] != "2" {
+			t.Errorf("ReadDelimited(tsv) = %v, %v", row, err)
+		}
+	}
+}
+
+func TestReadDelimitedErrors(t *testing.T) {
+	tests := map[string]string{
+		"a,b\n1,2\n\n3\n": "line 4: expected 2 fields, got 1",
+		"a,a\n1,2\n":      `line 1: invalid or repeated column name "a"`,
+		"a,b\n1,\"2\n":    "line 2: unbalanced double quote in string",
+	}
+	for input, want := range tests {
+		var got error
+		for _, err := range ReadDelimited(strings.NewReader(input), ',') {
+			got = err
+		}
+		if got == nil || got.Error() != want {
+			t.Errorf("ReadDelimited(%q) error = %v, want %q", input, got, want)
+		}
+	}
+}
+
+func TestReadDelimitedIntoTypes(t *testing.T) {
+	type record struct {
+		When    time.Time
+		Every   time.Duration
+		Count   int8
+		Ratio   float64
+		Level   slog.Level
+		Enabled bool
+	}
+
+	input := "when,every,count,ratio,level,enabled\n" +
+		"2024-01-02T03:04:05Z,1m30s,-7,0.25,WARN,yes\n" +
+		",,,,,\n"
+	var got []record
+	for r, err := range ReadDelimitedInto[record](strings.NewReader(input), ',') {
+		if err != nil {
+			t.Fatalf("ReadDelimitedInto() error = %v", err)
+		}
+		got = append(got, r)
+	}
+
+	want := []record{
+		{
+			When:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Every:   90 * time.Second,
+			Count:   -7,
+			Ratio:   0.25,
+			Level:   slog.LevelWarn,
+			Enabled: true,
+		},
+		{},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ReadDelimitedInto() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].When.Equal(want[i].When) {
+			t.Errorf("record %d: When = %v, want %v", i, got[i].When, want[i].When)
+		}
+		got[i].When = want[i].When
+		if got[i] != want[i] {
+			t.Errorf("record %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// Times are parsed with ParseTimeAny, not only as RFC 3339.
+	for _, tc := range []struct {
+		input string
+		want  time.Time
+	}{
+		{"2024-01-02", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"2024/01/02 15:04:05", time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{"1700000000", time.Unix(1700000000, 0)},
+	} {
+		for r, err := range ReadDelimitedInto[record](strings.NewReader("when\n"+tc.input+"\n"), ',') {
+			if err != nil {
+				t.Errorf("ReadDelimitedInto(when=%q) error = %v", tc.input, err)
+			} else if !r.When.Equal(tc.want) {
+				t.Errorf("ReadDelimitedInto(when=%q) = %v, want %v", tc.input, r.When, tc.want)
+			}
+		}
+	}
+
+	for _, tc := range []struct{ input, err string }{
+		{"when\nnot a time\n", `line 2: column 'when': invalid time.Time "not a time"`},
+		{"every\n5 parsecs\n", `line 2: column 'every': invalid time.Duration "5 parsecs"`},
+		{"count\n200\n", `line 2: column 'count': invalid int8 "200"`},
+	} {
+		var err error
+		for _, err = range ReadDelimitedInto[record](strings.NewReader(tc.input), ',') {
+		}
+		if err == nil || err.Error() != tc.err {
+			t.Errorf("ReadDelimitedInto(%q) error = %v, want %s", tc.input, err, tc.err)
+		}
+	}
+}
+
+func TestReadDelimitedInto(t *testing.T) {
+	type server struct {
+		Name    string
+		Port    uint16
+		Address netip.Addr `gobag:"addr"`
+		Enabled bool
+		Timeout time.Duration
+		Ignored string `gobag:"-"`
+		hidden  string
+	}
+
+	var got []server
+	for s, err := range ReadDelimitedInto[server](strings.NewReader(testDelimited), ',') {
+		if err != nil {
+			t.Fatalf("ReadDelimitedInto() error = %v", err)
+		}
+		got = append(got, s)
+	}
+
+	want := []server{
+		{Name: "web", Port: 80, Address: netip.MustParseAddr("10.0.0.1"), Enabled: true, Timeout: 5 * time.Second},
+		{Name: "db, primary", Port: 5432, Address: netip.MustParseAddr("10.0.0.2"), Timeout: time.Minute},
+		{Name: "multi\nline", Port: 16, Address: netip.MustParseAddr("::1"), Enabled: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadDelimitedInto() =\n%v\nwant\n%v", got, want)
+	}
+
+	var err error
+	for _, err = range ReadDelimitedInto[server](strings.NewReader("name,port\nx,70000\n"), ',') {
+	}
+	if err == nil || err.Error() != `line 2: column 'port': invalid uint16 "70000"` {
+		t.Errorf("ReadDelimitedInto() error = %v", err)
+	}
+
+	for _, err = range ReadDelimitedInto[int](strings.NewReader("a\n1\n"), ',') {
+	}
+	if err == nil {
+		t.Error("ReadDelimitedInto[int]() succeeded")
+	}
+}
@@ -0,0 +1,238 @@
+package gobag
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ChangeKind identifies how a field differs between two lines.
+type ChangeKind int
+
+// Kinds of field changes reported by DiffLine.
+const (
+	FieldAdded ChangeKind = iota
+	FieldRemoved
+	FieldChanged
+)
+
+// String returns the name of the change kind.
+func (k ChangeKind) String() string {
+	switch k {
+	case FieldAdded:
+		return "added"
+	case FieldRemoved:
+		return "removed"
+	case FieldChanged:
+		return "changed"
+	}
+	return "unknown"
+}
+
+// FieldChange describes a single difference found by DiffLine.
+type FieldChange struct {
+	Kind ChangeKind
+
+	// Key is the key of a key=value field. It is empty for
+	// positional fields.
+	Key string
+
+	// Index is the position of a positional field among the
+	// positional fields of its line. It is -1 for key=value fields.
+	Index int
+
+	// Old and New hold the normalized value before and after the
+	// change. Old is empty for added fields, New for removed ones.
+	Old, New string
+}
+
+// DiffLine compares two lines of fields separated by sep and reports
+// which fields were added, removed or changed. Fields of the form
+// key=value are matched by key, all other fields by their position.
+// Fields are compared in their normalized form, see Normalize, so
+// differences in spacing or quoting are not reported. Positional
+// changes are reported first, followed by keyed changes in the order
+// the keys appear in a, then b.
+func DiffLine(a, b string, sep rune) ([]FieldChange, error) {
+	posA, keysA, valsA, err := diffFields(a, sep)
+	if err != nil {
+		return nil, err
+	}
+	posB, keysB, valsB, err := diffFields(b, sep)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]FieldChange, 0)
+	for i := range max(len(posA), len(posB)) {
+		switch {
+		case i >= len(posA):
+			changes = append(changes, FieldChange{Kind: FieldAdded, Index: i, New: posB[i]})
+		case i >= len(posB):
+			changes = append(changes, FieldChange{Kind: FieldRemoved, Index: i, Old: posA[i]})
+		case posA[i] != posB[i]:
+			changes = append(changes, FieldChange{Kind: FieldChanged, Index: i, Old: posA[i], New: posB[i]})
+		}
+	}
+
+	for _, k := range keysA {
+		newValue, ok := valsB[k]
+		switch {
+		case !ok:
+			changes = append(changes, FieldChange{Kind: FieldRemoved, Key: k, Index: -1, Old: valsA[k]})
+		case newValue != valsA[k]:
+			changes = append(changes, FieldChange{Kind: FieldChanged, Key: k, Index: -1, Old: valsA[k], New: newValue})
+		}
+	}
+	for _, k := range keysB {
+		if _, ok := valsA[k]; !ok {
+			changes = append(changes, FieldChange{Kind: FieldAdded, Key: k, Index: -1, New: valsB[k]})
+		}
+	}
+
+	return changes, nil
+}
+
+// diffFields splits a line into normalized positional fields and
+// key=value pairs, returning the keys in order of first appearance.
+// For repeated keys the last value wins.
+func diffFields(s string, sep rune) (positional, keys []string, values map[string]string, err error) {
+	fields, err := normalizedFields(s, sep)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	values = make(map[string]string)
+	for _, f := range fields {
+		k, v, ok := cutUnquoted(f, '=')
+		if !ok {
+			positional = append(positional, f)
+			continue
+		}
+		if _, seen := values[k]; !seen {
+			keys = append(keys, k)
+		}
+		values[k] = v
+	}
+
+	return positional, keys, values, nil
+}
+
+// diffContext is the number of unchanged lines RenderDiff shows around
+// each change.
+const diffContext = 3
+
+// RenderDiff returns the differences between the lines a and b in the
+// style of a unified diff: hunks headed by "@@ -l,n +l,n @@" list
+// removed lines prefixed by "-", added lines by "+" and up to three
+// unchanged lines of context prefixed by a space. The changes are
+// based on a longest common subsequence of the lines. It returns an
+// empty string if a and b are equal.
+func RenderDiff(a, b []string) string {
+	ops := diffLines(a, b)
+
+	var sb strings.Builder
+	for start := 0; start < len(ops); {
+		// Find the next change, and extend the hunk for as long as
+		// changes are separated by little enough context.
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+		last := first
+		for i := first; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				if i-last-1 > 2*diffContext {
+					break
+				}
+				last = i
+			}
+		}
+
+		lo := max(first-diffContext, start)
+		hi := min(last+diffContext+1, len(ops))
+		hunk := ops[lo:hi]
+
+		var oldLen, newLen int
+		for _, op := range hunk {
+			if op.kind != '+' {
+				oldLen++
+			}
+			if op.kind != '-' {
+				newLen++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n",
+			hunkRange(hunk[0].aIndex, oldLen), hunkRange(hunk[0].bIndex, newLen))
+		for _, op := range hunk {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.line)
+			sb.WriteByte('\n')
+		}
+		start = hi
+	}
+
+	return sb.String()
+}
+
+// diffOp is a single line of a line diff. kind is ' ' for an unchanged
+// line, '-' for a removed and '+' for an added line. aIndex and bIndex
+// are the number of lines of a and b preceding the line.
+type diffOp struct {
+	kind           byte
+	line           string
+	aIndex, bIndex int
+}
+
+// diffLines returns the edit script turning a into b, based on the
+// longest common subsequence of the two.
+func diffLines(a, b []string) []diffOp {
+	// lcs[i][j] is the length of the LCS of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, max(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i], i, j})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{'-', a[i], i, j})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j], i, j})
+			j++
+		}
+	}
+
+	return ops
+}
+
+// hunkRange formats the line range of a hunk header, which starts at
+// line index+1, or at line index if the range is empty.
+func hunkRange(index, n int) string {
+	if n == 0 {
+		return fmt.Sprintf("%d,0", index)
+	}
+	if n == 1 {
+		return strconv.Itoa(index + 1)
+	}
+	return fmt.Sprintf("%d,%d", index+1, n)
+}
@@ -0,0 +1,89 @@
+package gobag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffLine(t *testing.T) {
+	changes, err := DiffLine(
+		`eth0, up, mtu=1500, addr="10.0.0.1", gw = 10.0.0.254`,
+		`eth0,down,mtu=9000,addr=10.0.0.1,dns=1.1.1.1,extra`,
+		',')
+	if err != nil {
+		t.Fatalf("DiffLine() error = %v", err)
+	}
+
+	want := []FieldChange{
+		{Kind: FieldChanged, Index: 1, Old: "up", New: "down"},
+		{Kind: FieldAdded, Index: 2, New: "extra"},
+		{Kind: FieldChanged, Key: "mtu", Index: -1, Old: "1500", New: "9000"},
+		{Kind: FieldRemoved, Key: "gw", Index: -1, Old: "10.0.0.254"},
+		{Kind: FieldAdded, Key: "dns", Index: -1, New: "1.1.1.1"},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("DiffLine() = %+v, want %+v", changes, want)
+	}
+
+	changes, err = DiffLine(`a b  "c d"`, `a   b 'c d'`, ' ')
+	if err != nil || len(changes) != 0 {
+		t.Fatalf("DiffLine() = %+v, %v, want no changes", changes, err)
+	}
+
+	// A quoted positional field containing '=' stays positional.
+	changes, err = DiffLine(`"a=b",x=1`, `a\=b,x=1`, ',')
+	if err != nil || len(changes) != 0 {
+		t.Fatalf("DiffLine() = %+v, %v, want no changes", changes, err)
+	}
+	changes, err = DiffLine(`"a=b"`, `"a=c"`, ',')
+	if err != nil || len(changes) != 1 || changes[0].Key != "" || changes[0].New != `"a=c"` {
+		t.Fatalf("DiffLine() = %+v, %v, want a changed positional field", changes, err)
+	}
+
+	if _, err := DiffLine(`a,"b`, `a`, ','); err == nil {
+		t.Fatal("DiffLine() with unbalanced quote: expected error")
+	}
+}
+
+func TestRenderDiff(t *testing.T) {
+	if got := RenderDiff([]string{"a", "b"}, []string{"a", "b"}); got != "" {
+		t.Errorf("RenderDiff() of equal input = %q, want empty", got)
+	}
+
+	tests := []struct {
+		a, b     []string
+		expected string
+	}{
+		{
+			[]string{"a", "b", "c"},
+			[]string{"a", "x", "c"},
+			"@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n",
+		},
+		{
+			nil,
+			[]string{"a", "b"},
+			"@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			[]string{"a"},
+			nil,
+			"@@ -1 +0,0 @@\n-a\n",
+		},
+		{
+			[]string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"},
+			[]string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "eleven", "12"},
+			"@@ -1,3 +1,4 @@\n+0\n 1\n 2\n 3\n" +
+				"@@ -8,5 +9,5 @@\n 8\n 9\n 10\n-11\n+eleven\n 12\n",
+		},
+		{
+			[]string{"1", "2", "3", "4", "5", "6", "7", "8"},
+			[]string{"x", "2", "3", "4", "5", "6", "7", "y"},
+			"@@ -1,8 +1,8 @@\n-1\n+x\n 2\n 3\n 4\n 5\n 6\n 7\n-8\n+y\n",
+		},
+	}
+	for _, tt := range tests {
+		if got := RenderDiff(tt.a, tt.b); got != tt.expected {
+			t.Errorf("RenderDiff(%q, %q) =\n%s\nwant\n%s", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package gobag
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// docComment holds the characters starting a comment in a Document.
+const docComment = "#;"
+
+// Document is an editable model of a configuration file of key = value
+// settings, optionally grouped in INI style [sections]. Comments,
+// blank lines and the formatting of untouched settings are retained,
+// so a Document that is not modified is written back byte for byte as
+// it was read, and edits produce minimal changes.
+//
+// Settings before the first section header belong to the section
+// named "". A Document is not safe for concurrent use.
+type Document struct {
+	lines []docLine
+	eol   string // Line ending used for new lines.
+}
+
+type docLine struct {
+	raw     string // Physical lines, including the line ending.
+	text    string // Logical line; empty for blank and comment lines.
+	section string // Section the line belongs to.
+	header  bool   // Whether the line is a section header.
+	key     string // Empty unless the line is a setting.
+	value   string
+}
+
+// LoadDocument reads and parses the file at path. See ParseDocument.
+func LoadDocument(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := ParseDocument(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return doc, nil
+}
+
+// ParseDocument parses a configuration file into a Document. The
+// syntax is that of ParseINIOrdered: section headers are written as
+// [name] and settings as key = value, comments start with '#' or ';'
+// at the start of a line or after whitespace, and keys and values may
+// be quoted following the quoting rules of Fields. Returns an error
+// with the line number for malformed lines.
+func ParseDocument(r io.Reader) (*Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{eol: "\n"}
+	if i := bytes.IndexByte(data, '\n'); i > 0 && data[i-1] == '\r' {
+		doc.eol = "\r\n"
+	}
+
+	var section string
+	err = readLogicalLines(bytes.NewReader(data), docComment, func(lineno int, text, raw string) error {
+		line := docLine{raw: raw + "\n", section: section}
+		if err := parseDocLine(lineno, text, &line); err != nil {
+			return err
+		}
+		section = line.section
+		doc.lines = append(doc.lines, line)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if n := len(doc.lines); n > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+		doc.lines[n-1].raw = strings.TrimSuffix(doc.lines[n-1].raw, "\n")
+	}
+
+	return doc, nil
+}
+
+// parseDocLine parses the logical line text into line, whose section
+// is the current one and is updated if text is a section header.
+func parseDocLine(lineno int, text string, line *docLine) error {
+	body := strings.TrimSpace(stripComment(text, docComment))
+	switch {
+	case body == "":
+	case strings.HasPrefix(body, "["):
+		if !strings.HasSuffix(body, "]") {
+			return fmt.Errorf("line %d: unterminated section header", lineno)
+		}
+		line.section = decodeValue(body[1 : len(body)-1])
+		line.header = true
+	default:
+		key, value, ok := cutUnquoted(body, '=')
+		if !ok {
+			return fmt.Errorf("line %d: expected key = value", lineno)
+		}
+		if line.key = decodeValue(key); line.key == "" {
+			return fmt.Errorf("line %d: missing key", lineno)
+		}
+		line.text = text
+		line.value = decodeValue(value)
+	}
+	return nil
+}
+
+func (d *Document) find(section, key string) int {
+	for i := len(d.lines) - 1; i >= 0; i-- {
+		if l := d.lines[i]; l.key == key && l.section == section {
+			return i
+		}
+	}
+	return -1
+}
+
+// Get returns the value of key in section, and whether it is set. If
+// a key is set more than once, the last value wins.
+func (d *Document) Get(section, key string) (string, bool) {
+	if i := d.find(section, key); i >= 0 {
+		return d.lines[i].value, true
+	}
+	return "", false
+}
+
+// Set sets key in section to value. An existing setting is updated in
+// place, keeping its spacing, quote style and comment. A new setting
+// is added after the last setting of its section, and a missing
+// section is appended to the document.
+func (d *Document) Set(section, key, value string) {
+	if i := d.find(section, key); i >= 0 {
+		l := &d.lines[i]
+		if l.value == value {
+			return
+		}
+		body := stripComment(l.text, docComment)
+		k, v, _ := cutUnquoted(body, '=')
+		l.text = k + "=" + spliceValue(v, value, quoteDocValue(value)) + l.text[len(body):]
+		l.raw = l.text + l.raw[len(strings.TrimRight(l.raw, "\r\n")):]
+		l.value = value
+		return
+	}
+
+	text := quoteDocValue(key) + " = " + quoteDocValue(value)
+	line := docLine{raw: text + d.eol, text: text, section: section, key: key, value: value}
+
+	at := -1
+	for i, l := range d.lines {
+		if l.section == section && (l.key != "" || l.header) {
+			at = i + 1
+		}
+	}
+	switch {
+	case at >= 0:
+		d.insert(at, line)
+	case section == "":
+		d.insert(0, line)
+	default:
+		if n := len(d.lines); n > 0 && strings.TrimSpace(d.lines[n-1].raw) != "" {
+			d.insert(n, docLine{raw: d.eol, section: section})
+		}
+		header := "[" + quoteDocValue(section) + "]"
+		d.insert(len(d.lines), docLine{raw: header + d.eol, section: section, header: true})
+		d.insert(len(d.lines), line)
+	}
+}
+
+// insert inserts line at index i. A line appended after a last line
+// without a line ending takes over its lack of one.
+func (d *Document) insert(i int, line docLine) {
+	if n := len(d.lines); i == n && n > 0 && !strings.HasSuffix(d.lines[n-1].raw, "\n") {
+		d.lines[n-1].raw += d.eol
+		line.raw = strings.TrimSuffix(line.raw, d.eol)
+	}
+	d.lines = slices.Insert(d.lines, i, line)
+}
+
+// Delete removes all settings of key in section.
+func (d *Document) Delete(section, key string) {
+	d.lines = slices.DeleteFunc(d.lines, func(l docLine) bool {
+		return l.key == key && l.section == section
+	})
+}
+
+// Sections returns the names of the sections in order of appearance,
+// starting with "" if there are settings before the first header.
+func (d *Document) Sections() []string {
+	sections := make([]string, 0)
+	for _, l := range d.lines {
+		if l.header || l.key != "" {
+			sections = append(sections, l.section)
+		}
+	}
+	return Deduplicate(sections)
+}
+
+// Keys returns the keys set in section, in order of first appearance.
+func (d *Document) Keys(section string) []string {
+	keys := make([]string, 0)
+	for _, l := range d.lines {
+		if l.key != "" && l.section == section {
+			keys = append(keys, l.key)
+		}
+	}
+	return Deduplicate(keys)
+}
+
+// WriteTo writes the document to w. Unchanged lines are written
+// exactly as they were read.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	for _, l := range d.lines {
+		buf.WriteString(l.raw)
+	}
+	return buf.WriteTo(w)
+}
+
+// String returns the document as text.
+func (d *Document) String() string {
+	var sb strings.Builder
+	d.WriteTo(&sb)
+	return sb.String()
+}
+
+// quoteDocValue returns v quoted if it would otherwise not be read
+// back as is by ParseDocument.
+func quoteDocValue(v string) string {
+	if strings.ContainsAny(v, docComment+"=[]") {
+		return QuoteString(v)
+	}
+	return quoteIfNeeded(v, '=')
+}
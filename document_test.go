@@ -0,0 +1,164 @@
+package gobag
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testDocument = `# Global settings
+name = "my app"   # display name
+debug=false
+
+[database]
+; connection
+host = db1
+port = 5432
+password = "multi
+line"
+
+[cache]
+size=64 \
+  MB
+`
+
+func TestDocumentRoundTrip(t *testing.T) {
+	inputs := []string{
+		testDocument,
+		"",
+		"a=1",
+		"a = 1\r\nb = 2\r\n",
+		"\n\n# only comments\n",
+	}
+	for _, input := range inputs {
+		doc, err := ParseDocument(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("ParseDocument(%q) error = %v", input, err)
+		}
+		if got := doc.String(); got != input {
+			t.Errorf("round trip of %q = %q", input, got)
+		}
+	}
+}
+
+func TestDocumentGet(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(testDocument))
+	if err != nil {
+		t.Fatalf("ParseDocument() error = %v", err)
+	}
+
+	tests := []struct {
+		section, key, expected string
+	}{
+		{"", "name", "my app"},
+		{"", "debug", "false"},
+		{"database", "port", "5432"},
+		{"database", "password", "multi\nline"},
+		{"cache", "size", "64 MB"},
+	}
+	for _, tt := range tests {
+		if got, ok := doc.Get(tt.section, tt.key); !ok || got != tt.expected {
+			t.Errorf("Get(%q, %q) = %q, %v, want %q", tt.section, tt.key, got, ok, tt.expected)
+		}
+	}
+	if _, ok := doc.Get("", "host"); ok {
+		t.Error("Get(\"\", \"host\") found a key of another section")
+	}
+
+	if got, want := doc.Sections(), []string{"", "database", "cache"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Sections() = %q, want %q", got, want)
+	}
+	if got, want := doc.Keys("database"), []string{"host", "port", "password"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Keys() = %q, want %q", got, want)
+	}
+}
+
+func TestDocumentEdit(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(testDocument))
+	if err != nil {
+		t.Fatalf("ParseDocument() error = %v", err)
+	}
+
+	doc.Set("", "name", "other app")
+	doc.Set("", "debug", "false")
+	doc.Set("database", "port", "6432")
+	doc.Set("database", "user", "app; admin")
+	doc.Set("", "level", "info")
+	doc.Set("logging", "file", "/var/log/app.log")
+	doc.Delete("database", "password")
+	doc.Delete("cache", "size")
+
+	want := `# Global settings
+name = "other app"   # display name
+debug=false
+level = info
+
+[database]
+; connection
+host = db1
+port = 6432
+user = "app; admin"
+
+[cache]
+
+[logging]
+file = /var/log/app.log
+`
+	if got := doc.String(); got != want {
+		t.Errorf("edited document =\n%s\nwant\n%s", got, want)
+	}
+
+	reparsed, err := ParseDocument(strings.NewReader(doc.String()))
+	if err != nil {
+		t.Fatalf("ParseDocument() of edited document error = %v", err)
+	}
+	if got, _ := reparsed.Get("database", "user"); got != "app; admin" {
+		t.Errorf("reparsed user = %q", got)
+	}
+
+	// A single quoted value is requoted if the new value needs escapes.
+	doc, _ = ParseDocument(strings.NewReader("dir = 'old'\n"))
+	doc.Set("", "dir", `C:\`)
+	reparsed, err = ParseDocument(strings.NewReader(doc.String()))
+	if err != nil {
+		t.Fatalf("ParseDocument(%q) error = %v", doc.String(), err)
+	}
+	if got, _ := reparsed.Get("", "dir"); got != `C:\` {
+		t.Errorf("reparsed dir = %q, want %q", got, `C:\`)
+	}
+}
+
+func TestDocumentCRLF(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader("a = 1\r\nb = 2"))
+	if err != nil {
+		t.Fatalf("ParseDocument() error = %v", err)
+	}
+	doc.Set("", "a", "3")
+	doc.Set("", "c", "4")
+	if got, want := doc.String(), "a = 3\r\nb = 2\r\nc = 4"; got != want {
+		t.Errorf("edited document = %q, want %q", got, want)
+	}
+}
+
+func TestParseDocumentErrors(t *testing.T) {
+	tests := map[string]string{
+		"[section":     "line 1: unterminated section header",
+		"a=1\nnovalue": "line 2: expected key = value",
+		" = 1":         "line 1: missing key",
+		"a = \"open":   "line 1: unbalanced double quote in string",
+	}
+	for input, want := range tests {
+		if _, err := ParseDocument(strings.NewReader(input)); err == nil || err.Error() != want {
+			t.Errorf("ParseDocument(%q) error = %v, want %q", input, err, want)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "app.conf")
+	os.WriteFile(path, []byte(testDocument), 0o644)
+	doc, err := LoadDocument(path)
+	if err != nil || doc.String() != testDocument {
+		t.Errorf("LoadDocument() = %v, want unchanged document", err)
+	}
+}
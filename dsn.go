@@ -0,0 +1,216 @@
+package gobag
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DSN is a database connection string of space separated key=value
+// pairs, in the style of libpq: "host=db port=5432 user='app user'".
+// Keys are unique and keep their original order.
+type DSN struct {
+	params Params
+}
+
+// ParseDSN parses a connection string. Values may be quoted with
+// single or double quotes, and backslash escapes quotes and
+// backslashes. As in libpq, there may be spaces around the equals
+// sign, as in "host = db". If a key is repeated, the last value wins.
+// Returns an error if a key is empty, or if quotes or parentheses are
+// unbalanced.
+func ParseDSN(s string) (*DSN, error) {
+	raw, err := splitRaw(s, ' ')
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]string, 0, len(raw))
+	for _, f := range raw {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+
+	d := &DSN{}
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		key, value, found := cutUnquoted(f, '=')
+		if !found && i+1 < len(fields) && strings.HasPrefix(fields[i+1], "=") {
+			// "key =value" or "key = value".
+			i++
+			f += " " + fields[i]
+			value, found = fields[i][1:], true
+		}
+		if found && value == "" && i+1 < len(fields) {
+			// "key= value".
+			i++
+			f += " " + fields[i]
+			value = fields[i]
+		}
+		if key = decodeValue(key); key == "" {
+			return nil, fmt.Errorf("missing key in parameter %q", f)
+		}
+		d.Set(key, decodeDSNValue(value))
+	}
+
+	return d, nil
+}
+
+// decodeDSNValue decodes a value, allowing escapes within single
+// quotes as libpq does.
+func decodeDSNValue(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return unescape(s[1 : len(s)-1])
+	}
+	return decodeValue(s)
+}
+
+// Get returns the value of key, or an empty string if key is not
+// present.
+func (d *DSN) Get(key string) string {
+	return d.params.Get(key)
+}
+
+// Lookup returns the value of key, and whether key is present.
+func (d *DSN) Lookup(key string) (string, bool) {
+	return d.params.Lookup(key)
+}
+
+// Set sets key to value, keeping the position of an existing key.
+func (d *DSN) Set(key, value string) {
+	for i := range d.params {
+		if d.params[i].Key == key {
+			d.params[i].Value = value
+			return
+		}
+	}
+	d.params = append(d.params, Param{Key: key, Value: value})
+}
+
+// Delete removes key.
+func (d *DSN) Delete(key string) {
+	for i := range d.params {
+		if d.params[i].Key == key {
+			d.params = append(d.params[:i], d.params[i+1:]...)
+			return
+		}
+	}
+}
+
+// Keys returns the keys in order.
+func (d *DSN) Keys() []string {
+	return d.params.Keys()
+}
+
+// GetInt returns the value of key as an int. Returns an error if key
+// is not present or not an integer.
+func (d *DSN) GetInt(key string) (int, error) {
+	v, err := d.require(key)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("parameter %q: invalid integer %q", key, v)
+	}
+	return n, nil
+}
+
+// GetBool returns the value of key as a bool, accepting the values of
+// strconv.ParseBool as well as yes, no, on and off. Returns an error if
+// key is not present or not a boolean.
+func (d *DSN) GetBool(key string) (bool, error) {
+	v, err := d.require(key)
+	if err != nil {
+		return false, err
+	}
+	b, ok := parseBool(v)
+	if !ok {
+		return false, fmt.Errorf("parameter %q: invalid boolean %q", key, v)
+	}
+	return b, nil
+}
+
+// parseBool parses the values of strconv.ParseBool as well as yes, no,
+// on and off, in any case.
+func parseBool(v string) (bool, bool) {
+	switch strings.ToLower(v) {
+	case "yes", "on":
+		return true, true
+	case "no", "off":
+		return false, true
+	}
+	b, err := strconv.ParseBool(v)
+	return b, err == nil
+}
+
+// GetDuration returns the value of key as a duration, either in the
+// form accepted by time.ParseDuration or as a plain number of seconds,
+// as libpq uses for connect_timeout. Returns an error if key is not
+// present or not a duration.
+func (d *DSN) GetDuration(key string) (time.Duration, error) {
+	v, err := d.require(key)
+	if err != nil {
+		return 0, err
+	}
+	seconds := func(s string) (time.Duration, error) {
+		n, err := strconv.ParseInt(s, 10, 64)
+		return time.Duration(n) * time.Second, err
+	}
+	dur, err := FirstParse(v, seconds, time.ParseDuration)
+	if err != nil {
+		return 0, fmt.Errorf("parameter %q: invalid duration %q", key, v)
+	}
+	return dur, nil
+}
+
+func (d *DSN) require(key string) (string, error) {
+	v, ok := d.Lookup(key)
+	if !ok {
+		return "", fmt.Errorf("missing parameter %q", key)
+	}
+	return v, nil
+}
+
+// Encode returns the connection string, including any password.
+// Values are single quoted when needed.
+func (d *DSN) Encode() string {
+	return d.encode(false)
+}
+
+// String returns the connection string with the password replaced by
+// Mask, making it safe for logging.
+func (d *DSN) String() string {
+	return d.encode(true)
+}
+
+func (d *DSN) encode(redact bool) string {
+	parts := make([]string, 0, len(d.params))
+	for _, p := range d.params {
+		value := p.Value
+		if redact && p.Key == "password" {
+			value = Mask
+		}
+		parts = append(parts, quoteDSNValue(p.Key)+"="+quoteDSNValue(value))
+	}
+	return strings.Join(parts, " ")
+}
+
+func quoteDSNValue(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\n\r'\"\\=()") {
+		return s
+	}
+	var sb strings.Builder
+	sb.WriteByte('\'')
+	for _, r := range s {
+		if r == '\'' || r == '\\' {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	sb.WriteByte('\'')
+
+	return sb.String()
+}
@@ -0,0 +1,82 @@
+package gobag
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDSN(t *testing.T) {
+	d, err := ParseDSN(`host=db port=5432 user='app user' password='it\'s secret' sslmode=on connect_timeout=10 port=5433`)
+	if err != nil {
+		t.Fatalf("ParseDSN() error = %v", err)
+	}
+
+	if got := d.Keys(); !reflect.DeepEqual(got, []string{"host", "port", "user", "password", "sslmode", "connect_timeout"}) {
+		t.Errorf("Keys() = %q", got)
+	}
+	if got := d.Get("user"); got != "app user" {
+		t.Errorf(`Get("user") = %q`, got)
+	}
+	if got := d.Get("password"); got != "it's secret" {
+		t.Errorf(`Get("password") = %q`, got)
+	}
+	if n, err := d.GetInt("port"); err != nil || n != 5433 {
+		t.Errorf(`GetInt("port") = %d, %v, want 5433`, n, err)
+	}
+	if b, err := d.GetBool("sslmode"); err != nil || !b {
+		t.Errorf(`GetBool("sslmode") = %t, %v, want true`, b, err)
+	}
+	if dur, err := d.GetDuration("connect_timeout"); err != nil || dur != 10*time.Second {
+		t.Errorf(`GetDuration("connect_timeout") = %v, %v, want 10s`, dur, err)
+	}
+	if _, err := d.GetInt("host"); err == nil {
+		t.Error(`GetInt("host"): expected error`)
+	}
+	if _, err := d.GetInt("missing"); err == nil {
+		t.Error(`GetInt("missing"): expected error`)
+	}
+
+	want := `host=db port=5433 user='app user' password=*** sslmode=on connect_timeout=10`
+	if got := d.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	d.Delete("sslmode")
+	d.Set("dbname", "")
+	encoded := d.Encode()
+	want = `host=db port=5433 user='app user' password='it\'s secret' connect_timeout=10 dbname=''`
+	if encoded != want {
+		t.Errorf("Encode() = %q, want %q", encoded, want)
+	}
+	again, err := ParseDSN(encoded)
+	if err != nil || !reflect.DeepEqual(again, d) {
+		t.Errorf("ParseDSN(Encode()) = %v, %v, want round trip", again, err)
+	}
+}
+
+func TestParseDSNSpacing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		err      string
+	}{
+		{"host = db port=5432", "host=db port=5432", ""},
+		{"host =db  port= 5432 user = 'app user'", "host=db port=5432 user='app user'", ""},
+		{"  host=db  port  =  5432  ", "host=db port=5432", ""},
+		{"dbname='' host=db", "dbname='' host=db", ""},
+		{"= db", "", `missing key in parameter "= db"`},
+	}
+	for _, tt := range tests {
+		d, err := ParseDSN(tt.input)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("ParseDSN(%q) error = %v, want %q", tt.input, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || d.Encode() != tt.expected {
+			t.Errorf("ParseDSN(%q) = %v, %v, want %q", tt.input, d, err, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package gobag
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ReplaceFieldInLine returns line with the field at index, counted from
+// zero among the fields separated by sep, replaced by value. A negative
+// index counts from the end. The whitespace around the field and its
+// quote style are kept, and value is quoted if needed to survive
+// splitting by Fields; the rest of the line is left untouched.
+// Returns an error if the line cannot be split or index is out of
+// range.
+func ReplaceFieldInLine(line string, sep rune, index int, value string) (string, error) {
+	fields, err := splitRaw(line, sep)
+	if err != nil {
+		return "", err
+	}
+	i := index
+	if i < 0 {
+		i += len(fields)
+	}
+	if i < 0 || i >= len(fields) {
+		return "", fmt.Errorf("field index %d out of range", index)
+	}
+
+	fields[i] = spliceValue(fields[i], value, quoteIfNeeded(value, sep))
+	return strings.Join(fields, string(sep)), nil
+}
+
+// UpsertKeyValue returns line with the value of the key=value field
+// whose key is key replaced by value, keeping the whitespace around
+// the value and its quote style. If there is no such field, a new one
+// is appended, after the same separator and spacing as used between
+// the existing fields, and before any trailing comment starting with
+// '#'. Everything else in the line is left untouched. Returns an error
+// if the line cannot be split.
+func UpsertKeyValue(line string, sep rune, key, value string) (string, error) {
+	content := stripComment(line, "#")
+	comment := line[len(content):]
+
+	fields, err := splitRaw(content, sep)
+	if err != nil {
+		return "", err
+	}
+	for i, f := range fields {
+		k, v, ok := cutUnquoted(f, '=')
+		if ok && decodeValue(k) == key {
+			fields[i] = k + "=" + spliceValue(v, value, quoteUpsertValue(value, sep))
+			return strings.Join(fields, string(sep)) + comment, nil
+		}
+	}
+
+	field := quoteUpsertValue(key, sep) + "=" + quoteUpsertValue(value, sep)
+	trimmed := strings.TrimRightFunc(content, unicode.IsSpace)
+	trail := content[len(trimmed):]
+	if strings.TrimSpace(trimmed) == "" {
+		return trimmed + field + Ternary(comment != "" && trail == "", " ", trail) + comment, nil
+	}
+
+	delim := string(sep)
+	if len(fields) > 1 {
+		next := fields[1]
+		delim += next[:len(next)-len(strings.TrimLeftFunc(next, unicode.IsSpace))]
+	} else if sep != ' ' && sep != '\t' {
+		delim += " "
+	}
+	return trimmed + delim + field + trail + comment, nil
+}
+
+// quoteUpsertValue is like quoteIfNeeded, but also quotes v if it
+// contains '#', which UpsertKeyValue would otherwise take for the
+// start of a comment.
+func quoteUpsertValue(v string, sep rune) string {
+	if strings.ContainsRune(v, '#') {
+		return QuoteString(v)
+	}
+	return quoteIfNeeded(v, sep)
+}
+
+// spliceValue returns the raw field or value text raw with its content
+// replaced by value. The whitespace around the content is kept. If the
+// old content was quoted, value is quoted in the same style where
+// possible, falling back to QuoteString for single quoted values
+// containing quotes or backslashes, and otherwise plain, the value as
+// it should be written unquoted, is used.
+func spliceValue(raw, value, plain string) string {
+	trimmed := strings.TrimLeftFunc(raw, unicode.IsSpace)
+	lead := raw[:len(raw)-len(trimmed)]
+	trimmed = strings.TrimRightFunc(trimmed, unicode.IsSpace)
+	trail := raw[len(lead)+len(trimmed):]
+
+	encoded := plain
+	switch {
+	case strings.HasPrefix(trimmed, "'") && !strings.ContainsAny(value, `'\`):
+		encoded = "'" + value + "'"
+	case strings.HasPrefix(trimmed, "'") || strings.HasPrefix(trimmed, `"`):
+		encoded = QuoteString(value)
+	}
+	return lead + encoded + trail
+}
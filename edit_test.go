@@ -0,0 +1,87 @@
+package gobag
+
+import "testing"
+
+func TestReplaceFieldInLine(t *testing.T) {
+	tests := []struct {
+		line     string
+		sep      rune
+		index    int
+		value    string
+		expected string
+		err      string
+	}{
+		{"eth0,  up , 1500", ',', 1, "down", "eth0,  down , 1500", ""},
+		{`a, "b c", d`, ',', 1, "x", `a, "x", d`, ""},
+		{`a, 'b', d`, ',', 1, "it's", `a, "it's", d`, ""},
+		{`a, 'b', d`, ',', 1, `C:\`, `a, "C:\\", d`, ""},
+		{"a b c", ' ', -1, "two words", `a b "two words"`, ""},
+		{"a,(b,c),d", ',', 2, "x,y", `a,(b,c),"x,y"`, ""},
+		{"a,b", ',', 2, "x", "", "field index 2 out of range"},
+		{`a,"b`, ',', 0, "x", "", "unbalanced double quote in string"},
+	}
+	for _, tt := range tests {
+		got, err := ReplaceFieldInLine(tt.line, tt.sep, tt.index, tt.value)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("ReplaceFieldInLine(%q) error = %v, want %q", tt.line, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.expected {
+			t.Errorf("ReplaceFieldInLine(%q, %d, %q) = %q, %v, want %q", tt.line, tt.index, tt.value, got, err, tt.expected)
+		}
+		if fields, err := splitRaw(got, tt.sep); err != nil {
+			t.Errorf("ReplaceFieldInLine(%q, %d, %q) = %q, which does not split: %v", tt.line, tt.index, tt.value, got, err)
+		} else if i := Ternary(tt.index < 0, len(fields)-1, tt.index); decodeValue(fields[i]) != tt.value {
+			t.Errorf("ReplaceFieldInLine(%q, %d, %q) = %q, which reads back as %q", tt.line, tt.index, tt.value, got, decodeValue(fields[i]))
+		}
+	}
+}
+
+func TestUpsertKeyValue(t *testing.T) {
+	tests := []struct {
+		line     string
+		sep      rune
+		key      string
+		value    string
+		expected string
+	}{
+		{"host = db1,  port =  5432 , user=app", ',', "port", "6432", "host = db1,  port =  6432 , user=app"},
+		{`name="old name", id=1`, ',', "name", "new", `name="new", id=1`},
+		{`name='old', id=1`, ',', "name", `new\`, `name="new\\", id=1`},
+		{`name='old', id=1`, ',', "name", "new", `name='new', id=1`},
+		{"a=1, b=2", ',', "c", "3", "a=1, b=2, c=3"},
+		{"a=1,b=2", ',', "c", "x y", `a=1,b=2,c=x y`},
+		{"a=1 b=2", ' ', "c", "x y", `a=1 b=2 c="x y"`},
+		{"a=1", ',', "b", "2", "a=1, b=2"},
+		{"a=1, b=2  # comment", ',', "c", "3", "a=1, b=2, c=3  # comment"},
+		{"a=1  # keep a=2", ',', "a", "9", "a=9  # keep a=2"},
+		{"", ',', "a", "1", "a=1"},
+		{"# only comment", ',', "a", "1", "a=1 # only comment"},
+	}
+	for _, tt := range tests {
+		got, err := UpsertKeyValue(tt.line, tt.sep, tt.key, tt.value)
+		if err != nil || got != tt.expected {
+			t.Errorf("UpsertKeyValue(%q, %q, %q) = %q, %v, want %q", tt.line, tt.key, tt.value, got, err, tt.expected)
+		}
+	}
+
+	// A value containing '#' survives a later upsert.
+	line, err := UpsertKeyValue("a=1,b=2", ',', "b", "x #y")
+	if err != nil || line != `a=1,b="x #y"` {
+		t.Fatalf("UpsertKeyValue() = %q, %v, want %q", line, err, `a=1,b="x #y"`)
+	}
+	line, err = UpsertKeyValue(line, ',', "c", "#3")
+	if err != nil || line != `a=1,b="x #y",c="#3"` {
+		t.Fatalf("UpsertKeyValue() = %q, %v, want %q", line, err, `a=1,b="x #y",c="#3"`)
+	}
+	line, err = UpsertKeyValue(line, ',', "a", "0")
+	if err != nil || line != `a=0,b="x #y",c="#3"` {
+		t.Errorf("UpsertKeyValue() = %q, %v, want %q", line, err, `a=0,b="x #y",c="#3"`)
+	}
+
+	if _, err := UpsertKeyValue("a=(1", ',', "a", "2"); err == nil {
+		t.Error("UpsertKeyValue() with unbalanced parentheses succeeded")
+	}
+}
@@ -0,0 +1,111 @@
+package gobag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Enum maps names to values of an enumerated type, e.g. to parse
+// constants from config files:
+//
+//	var levels = gobag.NewEnum[Level]().
+//		Add("debug", Debug).
+//		Add("info", Info).
+//		IgnoreCase()
+//
+//	level, err := levels.Parse("INFO")
+type Enum[T comparable] struct {
+	names      []string
+	values     []T
+	byName     map[string]T
+	byValue    map[T]string
+	ignoreCase bool
+}
+
+// NewEnum returns an empty Enum.
+func NewEnum[T comparable]() *Enum[T] {
+	return &Enum[T]{
+		byName:  make(map[string]T),
+		byValue: make(map[T]string),
+	}
+}
+
+// Add registers name for value and returns the Enum, to allow
+// chaining. It panics if the name or value is already registered, as
+// enums are meant to be built once at initialization.
+func (e *Enum[T]) Add(name string, value T) *Enum[T] {
+	if _, ok := e.byName[e.key(name)]; ok {
+		panic(fmt.Sprintf("gobag: duplicate enum name %q", name))
+	}
+	if _, ok := e.byValue[value]; ok {
+		panic(fmt.Sprintf("gobag: duplicate enum value %v", value))
+	}
+
+	e.names = append(e.names, name)
+	e.values = append(e.values, value)
+	e.byName[e.key(name)] = value
+	e.byValue[value] = name
+
+	return e
+}
+
+// IgnoreCase makes Parse match names case-insensitively, and returns
+// the Enum to allow chaining.
+func (e *Enum[T]) IgnoreCase() *Enum[T] {
+	e.ignoreCase = true
+	clear(e.byName)
+	for i, name := range e.names {
+		if _, ok := e.byName[e.key(name)]; ok {
+			panic(fmt.Sprintf("gobag: duplicate enum name %q", name))
+		}
+		e.byName[e.key(name)] = e.values[i]
+	}
+
+	return e
+}
+
+func (e *Enum[T]) key(name string) string {
+	if e.ignoreCase {
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+// Parse returns the value registered for name. Returns an error
+// listing the valid names if name is not registered.
+func (e *Enum[T]) Parse(name string) (T, error) {
+	if v, ok := e.byName[e.key(name)]; ok {
+		return v, nil
+	}
+
+	var zero T
+	return zero, fmt.Errorf("invalid value %q, expected one of: %s", name, strings.Join(e.names, ", "))
+}
+
+// MustParse is like Parse, but panics if name is not registered.
+func (e *Enum[T]) MustParse(name string) T {
+	v, err := e.Parse(name)
+	if err != nil {
+		panic("gobag: " + err.Error())
+	}
+	return v
+}
+
+// String returns the name registered for value, or its default
+// formatting if it is not registered.
+func (e *Enum[T]) String(value T) string {
+	if name, ok := e.byValue[value]; ok {
+		return name
+	}
+	return fmt.Sprint(value)
+}
+
+// Names returns the registered names in registration order.
+func (e *Enum[T]) Names() []string {
+	return append([]string(nil), e.names...)
+}
+
+// Values returns the registered values in registration order.
+func (e *Enum[T]) Values() []T {
+	return append([]T(nil), e.values...)
+}
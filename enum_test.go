@@ -0,0 +1,41 @@
+package gobag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEnum(t *testing.T) {
+	type level int
+	levels := NewEnum[level]().Add("debug", 0).Add("info", 1).Add("warn", 2)
+
+	if v, err := levels.Parse("info"); err != nil || v != 1 {
+		t.Errorf(`Parse("info") = %d, %v, want 1, nil`, v, err)
+	}
+	_, err := levels.Parse("INFO")
+	if err == nil || err.Error() != `invalid value "INFO", expected one of: debug, info, warn` {
+		t.Errorf(`Parse("INFO") error = %v`, err)
+	}
+
+	levels.IgnoreCase()
+	if v := levels.MustParse("WARN"); v != 2 {
+		t.Errorf(`MustParse("WARN") = %d, want 2`, v)
+	}
+
+	if s := levels.String(0); s != "debug" {
+		t.Errorf("String(0) = %q, want debug", s)
+	}
+	if s := levels.String(7); s != "7" {
+		t.Errorf("String(7) = %q, want 7", s)
+	}
+	if got := levels.Values(); !reflect.DeepEqual(got, []level{0, 1, 2}) {
+		t.Errorf("Values() = %v", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Add() of duplicate value did not panic")
+		}
+	}()
+	levels.Add("error", 1)
+}
@@ -0,0 +1,219 @@
+package gobag
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// EnvFile is an editable model of a .env file. Comments, blank lines
+// and the formatting of untouched variables are preserved when the
+// file is written back.
+type EnvFile struct {
+	lines []envLine
+}
+
+type envLine struct {
+	raw    string // Original text; used if the line is unchanged.
+	key    string // Empty for comments and blank lines.
+	value  string
+	export bool
+	suffix string // Trailing whitespace and comment.
+	dirty  bool
+}
+
+// LoadEnvFile reads and parses the .env file at path. See ParseEnv for
+// the syntax.
+func LoadEnvFile(path string) (*EnvFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	env, err := ParseEnv(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return env, nil
+}
+
+// ParseEnv parses a .env file of KEY=value lines, each optionally
+// prefixed by "export". Lines starting with '#' and text after a '#'
+// preceded by whitespace are comments. Values may be quoted following
+// the quoting rules of Fields, and quoted values may span several
+// lines. As in shells, \$ and \` within double quotes stand for $ and
+// `. Returns an error with the line number for malformed lines.
+func ParseEnv(r io.Reader) (*EnvFile, error) {
+	env := &EnvFile{}
+	err := readLogicalLines(r, "#", func(lineno int, text, raw string) error {
+		if text == "" {
+			env.lines = append(env.lines, envLine{raw: raw})
+			return nil
+		}
+
+		line := envLine{raw: raw}
+		body := stripComment(text, "#")
+		line.suffix = text[len(strings.TrimRightFunc(body, unicode.IsSpace)):]
+		body = strings.TrimSpace(body)
+		if rest, ok := strings.CutPrefix(body, "export"); ok && rest != "" && unicode.IsSpace(rune(rest[0])) {
+			line.export = true
+			body = strings.TrimSpace(rest)
+		}
+
+		key, value, ok := cutUnquoted(body, '=')
+		if !ok {
+			return fmt.Errorf("line %d: expected KEY=value", lineno)
+		}
+		if line.key = strings.TrimSpace(key); line.key == "" || strings.ContainsFunc(line.key, unicode.IsSpace) {
+			return fmt.Errorf("line %d: invalid variable name %q", lineno, line.key)
+		}
+		line.value = decodeEnvValue(value)
+		env.lines = append(env.lines, line)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// decodeEnvValue decodes a value, removing the backslash of \$ and \`
+// within double quotes as shells do.
+func decodeEnvValue(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' || !strings.ContainsAny(s, "$`") {
+		return decodeValue(s)
+	}
+
+	var sb strings.Builder
+	escaped := false
+	for _, r := range s {
+		if escaped && r != '$' && r != '`' {
+			sb.WriteByte('\\')
+		}
+		if r == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		escaped = false
+		sb.WriteRune(r)
+	}
+	return decodeValue(sb.String())
+}
+
+func (e *EnvFile) find(key string) int {
+	for i := len(e.lines) - 1; i >= 0; i-- {
+		if e.lines[i].key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// Get returns the value of the variable key, and whether it is set.
+// If a variable is set more than once, the last value wins.
+func (e *EnvFile) Get(key string) (string, bool) {
+	if i := e.find(key); i >= 0 {
+		return e.lines[i].value, true
+	}
+	return "", false
+}
+
+// Set sets the variable key to value. An existing variable is updated
+// in place, keeping its export prefix and comment, while a new one is
+// appended.
+func (e *EnvFile) Set(key, value string) {
+	if i := e.find(key); i >= 0 {
+		if e.lines[i].value != value {
+			e.lines[i].value = value
+			e.lines[i].dirty = true
+		}
+		return
+	}
+	e.lines = append(e.lines, envLine{key: key, value: value, dirty: true})
+}
+
+// Delete removes all assignments of the variable key.
+func (e *EnvFile) Delete(key string) {
+	e.lines = slices.DeleteFunc(e.lines, func(l envLine) bool {
+		return l.key == key
+	})
+}
+
+// Keys returns the names of the variables in order of first
+// appearance.
+func (e *EnvFile) Keys() []string {
+	keys := make([]string, 0, len(e.lines))
+	for _, l := range e.lines {
+		if l.key != "" {
+			keys = append(keys, l.key)
+		}
+	}
+	return Deduplicate(keys)
+}
+
+// Map returns the variables as a map.
+func (e *EnvFile) Map() map[string]string {
+	m := make(map[string]string, len(e.lines))
+	for _, l := range e.lines {
+		if l.key != "" {
+			m[l.key] = l.value
+		}
+	}
+	return m
+}
+
+// WriteTo writes the file to w. Unchanged lines are written exactly as
+// they were read.
+func (e *EnvFile) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	for _, l := range e.lines {
+		if !l.dirty {
+			buf.WriteString(l.raw)
+		} else {
+			if l.export {
+				buf.WriteString("export ")
+			}
+			buf.WriteString(l.key + "=" + quoteEnvValue(l.value) + l.suffix)
+		}
+		buf.WriteByte('\n')
+	}
+
+	return buf.WriteTo(w)
+}
+
+// WriteEnvFile atomically writes env to the file at path with
+// permissions perm, see WriteFileAtomic.
+func WriteEnvFile(path string, env *EnvFile, perm os.FileMode) error {
+	var buf bytes.Buffer
+	if _, err := env.WriteTo(&buf); err != nil {
+		return err
+	}
+	return WriteFileAtomic(path, buf.Bytes(), perm)
+}
+
+// quoteEnvValue returns s quoted if needed. Shells and most .env
+// loaders expand '$' and '`' within double quotes, so values holding
+// them are single quoted, or have them escaped if single quotes are
+// not possible.
+func quoteEnvValue(s string) string {
+	if !strings.ContainsFunc(s, unicode.IsSpace) && !strings.ContainsAny(s, "\"'`\\#()$") {
+		return s
+	}
+	if !strings.ContainsAny(s, "$`") {
+		return QuoteString(s)
+	}
+	if !strings.ContainsAny(s, `'\`) {
+		return "'" + s + "'"
+	}
+	return envExpansionEscaper.Replace(QuoteString(s))
+}
+
+var envExpansionEscaper = strings.NewReplacer("$", `\$`, "`", "\\`")
@@ -0,0 +1,124 @@
+package gobag
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testEnv = `# Database settings
+export DB_HOST=localhost
+DB_PASS="it's \"secret\""   # keep me
+
+GREETING='hello
+world'
+PATH_EXTRA=/opt/bin
+`
+
+func TestParseEnv(t *testing.T) {
+	env, err := ParseEnv(bytes.NewBufferString(testEnv))
+	if err != nil {
+		t.Fatalf("ParseEnv() error = %v", err)
+	}
+
+	want := map[string]string{
+		"DB_HOST":    "localhost",
+		"DB_PASS":    `it's "secret"`,
+		"GREETING":   "hello\nworld",
+		"PATH_EXTRA": "/opt/bin",
+	}
+	if got := env.Map(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Map() = %q, want %q", got, want)
+	}
+	if got := env.Keys(); !reflect.DeepEqual(got, []string{"DB_HOST", "DB_PASS", "GREETING", "PATH_EXTRA"}) {
+		t.Errorf("Keys() = %q", got)
+	}
+
+	var buf bytes.Buffer
+	if _, err := env.WriteTo(&buf); err != nil || buf.String() != testEnv {
+		t.Errorf("WriteTo() unchanged = %q, %v, want original", buf.String(), err)
+	}
+
+	env.Set("DB_HOST", "db.example.com")
+	env.Set("DB_PASS", "new secret")
+	env.Set("NEW", "1")
+	env.Delete("PATH_EXTRA")
+	buf.Reset()
+	if _, err := env.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	wantText := `# Database settings
+export DB_HOST=db.example.com
+DB_PASS="new secret"   # keep me
+
+GREETING='hello
+world'
+NEW=1
+`
+	if buf.String() != wantText {
+		t.Errorf("WriteTo() = %q, want %q", buf.String(), wantText)
+	}
+
+	for _, bad := range []string{"JUNK\n", "=1\n", "A B=1\n", "A=\"open\n"} {
+		if _, err := ParseEnv(bytes.NewBufferString(bad)); err == nil {
+			t.Errorf("ParseEnv(%q): expected error", bad)
+		}
+	}
+}
+
+func TestEnvFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte(testEnv), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := LoadEnvFile(path)
+	if err != nil {
+		t.Fatalf("LoadEnvFile() error = %v", err)
+	}
+	env.Set("DB_HOST", "x y")
+	if err := WriteEnvFile(path, env, 0o600); err != nil {
+		t.Fatalf("WriteEnvFile() error = %v", err)
+	}
+
+	again, err := LoadEnvFile(path)
+	if err != nil {
+		t.Fatalf("LoadEnvFile() error = %v", err)
+	}
+	if v, _ := again.Get("DB_HOST"); v != "x y" {
+		t.Errorf(`Get("DB_HOST") = %q, want "x y"`, v)
+	}
+}
+
+func TestQuoteEnvValue(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"plain", "plain"},
+		{"x y", `"x y"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{"$HOME/bin", `'$HOME/bin'`},
+		{"a `cmd`", "'a `cmd`'"},
+		{`it's $5`, `"it's \$5"`},
+		{`C:\$x`, `"C:\\\$x"`},
+	}
+	for _, tt := range tests {
+		got := quoteEnvValue(tt.value)
+		if got != tt.expected {
+			t.Errorf("quoteEnvValue(%q) = %s, want %s", tt.value, got, tt.expected)
+		}
+
+		env, err := ParseEnv(strings.NewReader("KEY=" + got + "\n"))
+		if err != nil {
+			t.Errorf("ParseEnv(KEY=%s) error = %v", got, err)
+			continue
+		}
+		if v, _ := env.Get("KEY"); v != tt.value {
+			t.Errorf("ParseEnv(KEY=%s) = %q, want %q", got, v, tt.value)
+		}
+	}
+}
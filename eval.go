@@ -0,0 +1,621 @@
+package gobag
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ExprFunc is a function callable from an expression.
+type ExprFunc func(args ...any) (any, error)
+
+// ExprFuncs are the functions available to every expression:
+//
+//	len(s)             length of a string in runes, or of a list
+//	lower(s), upper(s) case conversion
+//	contains(s, sub)   substring test
+//	hasprefix(s, p)    prefix test
+//	hassuffix(s, p)    suffix test
+var ExprFuncs = map[string]ExprFunc{
+	"len": func(args ...any) (any, error) {
+		if len(args) != 1 {
+			return nil, errors.New("len expects 1 argument")
+		}
+		switch v := args[0].(type) {
+		case string:
+			return float64(utf8.RuneCountInString(v)), nil
+		case []any:
+			return float64(len(v)), nil
+		case []string:
+			return float64(len(v)), nil
+		}
+		return nil, fmt.Errorf("len of %T", args[0])
+	},
+	"lower":     stringFunc("lower", strings.ToLower),
+	"upper":     stringFunc("upper", strings.ToUpper),
+	"contains":  stringPredicate("contains", strings.Contains),
+	"hasprefix": stringPredicate("hasprefix", strings.HasPrefix),
+	"hassuffix": stringPredicate("hassuffix", strings.HasSuffix),
+}
+
+func stringFunc(name string, fn func(string) string) ExprFunc {
+	return func(args ...any) (any, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%s expects 1 argument", name)
+		}
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s of %T", name, args[0])
+		}
+		return fn(s), nil
+	}
+}
+
+func stringPredicate(name string, fn func(string, string) bool) ExprFunc {
+	return func(args ...any) (any, error) {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("%s expects 2 arguments", name)
+		}
+		s, ok1 := args[0].(string)
+		t, ok2 := args[1].(string)
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("%s of %T and %T", name, args[0], args[1])
+		}
+		return fn(s, t), nil
+	}
+}
+
+// Expr is a parsed filter expression, ready to be evaluated any number
+// of times. It is safe for concurrent use.
+type Expr struct {
+	root exprNode
+}
+
+// ParseExpr parses a filter expression such as
+//
+//	status == "ok" && (port > 1024 || name =~ "web.*")
+//
+// Expressions consist of variables, which may be dotted to reach into
+// nested maps, string literals in double or single quotes, numbers,
+// true, false and null, function calls, and the following operators,
+// from lowest to highest precedence:
+//
+//	||
+//	&&
+//	== != < <= > >= =~ !~
+//	+ -
+//	* / %
+//	! - (unary)
+//
+// The operators =~ and !~ match a string against a regular
+// expression. Returns an error with the offset of the first invalid
+// input.
+func ParseExpr(s string) (*Expr, error) {
+	p := &exprParser{lex: exprLexer{s: s}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	root, err := p.parse(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %q", p.tok.text)
+	}
+
+	return &Expr{root: root}, nil
+}
+
+// Eval evaluates the expression with the given variables, using the
+// functions in ExprFuncs. Numbers evaluate to float64.
+func (e *Expr) Eval(vars map[string]any) (any, error) {
+	return e.EvalWith(vars, nil)
+}
+
+// EvalWith is like Eval, but makes funcs available in addition to
+// ExprFuncs, overriding functions of the same name.
+func (e *Expr) EvalWith(vars map[string]any, funcs map[string]ExprFunc) (any, error) {
+	return e.root.eval(&exprEnv{vars: vars, funcs: funcs})
+}
+
+// Match evaluates the expression like Eval, and returns an error if
+// the result is not a boolean.
+func (e *Expr) Match(vars map[string]any) (bool, error) {
+	v, err := e.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression yields %T, not bool", v)
+	}
+	return b, nil
+}
+
+// Lexer.
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOp
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value any
+	pos   int
+}
+
+type exprLexer struct {
+	s   string
+	pos int
+}
+
+// exprOperators are sorted so that longer operators match first.
+var exprOperators = []string{
+	"==", "!=", "<=", ">=", "=~", "!~", "&&", "||",
+	"<", ">", "!", "+", "-", "*", "/", "%", "(", ")", ",",
+}
+
+func (l *exprLexer) next() (token, error) {
+	l.pos += len(l.s[l.pos:]) - len(strings.TrimLeftFunc(l.s[l.pos:], unicode.IsSpace))
+	if l.pos >= len(l.s) {
+		return token{kind: tokEOF, pos: l.pos}, nil
+	}
+
+	start := l.pos
+	r, _ := utf8.DecodeRuneInString(l.s[l.pos:])
+	switch {
+	case r == '"' || r == '\'':
+		p := &literalParser{s: l.s, pos: l.pos}
+		v, err := p.quoted(byte(r))
+		if err != nil {
+			return token{}, err
+		}
+		l.pos = p.pos
+		return token{kind: tokString, text: l.s[start:l.pos], value: v, pos: start}, nil
+	case r >= '0' && r <= '9' || r == '.':
+		for l.pos < len(l.s) && (isIdentRune(rune(l.s[l.pos])) || l.s[l.pos] == '.' ||
+			((l.s[l.pos] == '+' || l.s[l.pos] == '-') && (l.s[l.pos-1] == 'e' || l.s[l.pos-1] == 'E'))) {
+			l.pos++
+		}
+		text := l.s[start:l.pos]
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			n, ierr := strconv.ParseInt(text, 0, 64)
+			if ierr != nil {
+				return token{}, fmt.Errorf("offset %d: invalid number %q", start, text)
+			}
+			f = float64(n)
+		}
+		return token{kind: tokNumber, text: text, value: f, pos: start}, nil
+	case isIdentRune(r):
+		for l.pos < len(l.s) {
+			r, size := utf8.DecodeRuneInString(l.s[l.pos:])
+			if !isIdentRune(r) && r != '.' {
+				break
+			}
+			l.pos += size
+		}
+		return token{kind: tokIdent, text: l.s[start:l.pos], pos: start}, nil
+	}
+
+	for _, op := range exprOperators {
+		if strings.HasPrefix(l.s[l.pos:], op) {
+			l.pos += len(op)
+			return token{kind: tokOp, text: op, pos: start}, nil
+		}
+	}
+
+	return token{}, fmt.Errorf("offset %d: unexpected character %q", start, r)
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Parser.
+
+type exprParser struct {
+	lex exprLexer
+	tok token
+}
+
+func (p *exprParser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *exprParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("offset %d: %s", p.tok.pos, fmt.Sprintf(format, args...))
+}
+
+var exprPrecedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3, "<": 3, "<=": 3, ">": 3, ">=": 3, "=~": 3, "!~": 3,
+	"+": 4, "-": 4,
+	"*": 5, "/": 5, "%": 5,
+}
+
+const prefixPrecedence = 6
+
+// parse parses an expression whose binary operators all bind tighter
+// than minPrec.
+func (p *exprParser) parse(minPrec int) (exprNode, error) {
+	left, err := p.prefix()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.tok.kind == tokOp {
+		op := p.tok.text
+		prec, ok := exprPrecedence[op]
+		if !ok || prec <= minPrec {
+			break
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parse(prec)
+		if err != nil {
+			return nil, err
+		}
+
+		if op == "=~" || op == "!~" {
+			if lit, ok := right.(literalNode); ok {
+				s, ok := lit.value.(string)
+				if !ok {
+					return nil, fmt.Errorf("pattern of %s is %T, not string", op, lit.value)
+				}
+				re, err := regexp.Compile(s)
+				if err != nil {
+					return nil, err
+				}
+				right = regexpNode{re}
+			}
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *exprParser) prefix() (exprNode, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokEOF:
+		return nil, p.errorf("unexpected end of expression")
+	case tokNumber, tokString:
+		return literalNode{tok.value}, p.advance()
+	case tokIdent:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		switch tok.text {
+		case "true":
+			return literalNode{true}, nil
+		case "false":
+			return literalNode{false}, nil
+		case "null":
+			return literalNode{nil}, nil
+		}
+		if p.tok.kind == tokOp && p.tok.text == "(" {
+			return p.call(tok.text)
+		}
+		return varNode{path: strings.Split(tok.text, ".")}, nil
+	}
+
+	switch tok.text {
+	case "(":
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		n, err := p.parse(0)
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.text != ")" || p.tok.kind != tokOp {
+			return nil, p.errorf("expected ')'")
+		}
+		return n, p.advance()
+	case "!", "-":
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		operand, err := p.parse(prefixPrecedence)
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: tok.text, operand: operand}, nil
+	}
+
+	return nil, p.errorf("unexpected %q", tok.text)
+}
+
+func (p *exprParser) call(name string) (exprNode, error) {
+	n := callNode{name: name}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	for !(p.tok.kind == tokOp && p.tok.text == ")") {
+		if len(n.args) > 0 {
+			if p.tok.kind != tokOp || p.tok.text != "," {
+				return nil, p.errorf("expected ',' or ')'")
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.parse(0)
+		if err != nil {
+			return nil, err
+		}
+		n.args = append(n.args, arg)
+	}
+
+	return n, p.advance()
+}
+
+// Evaluation.
+
+type exprEnv struct {
+	vars  map[string]any
+	funcs map[string]ExprFunc
+}
+
+type exprNode interface {
+	eval(env *exprEnv) (any, error)
+}
+
+type literalNode struct{ value any }
+
+func (n literalNode) eval(*exprEnv) (any, error) { return n.value, nil }
+
+type regexpNode struct{ re *regexp.Regexp }
+
+func (n regexpNode) eval(*exprEnv) (any, error) { return n.re, nil }
+
+type varNode struct{ path []string }
+
+func (n varNode) eval(env *exprEnv) (any, error) {
+	var v any = env.vars
+	for _, name := range n.path {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+		v = m[name]
+	}
+	return normalizeExprValue(v), nil
+}
+
+// normalizeExprValue converts numeric values to float64.
+func normalizeExprValue(v any) any {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int8:
+		return float64(n)
+	case int16:
+		return float64(n)
+	case int32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case uint:
+		return float64(n)
+	case uint8:
+		return float64(n)
+	case uint16:
+		return float64(n)
+	case uint32:
+		return float64(n)
+	case uint64:
+		return float64(n)
+	case float32:
+		return float64(n)
+	}
+	return v
+}
+
+type callNode struct {
+	name string
+	args []exprNode
+}
+
+func (n callNode) eval(env *exprEnv) (any, error) {
+	fn, ok := env.funcs[n.name]
+	if !ok {
+		fn, ok = ExprFuncs[n.name]
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q", n.name)
+	}
+
+	args := make([]any, 0, len(n.args))
+	for _, a := range n.args {
+		v, err := a.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+	}
+	v, err := fn(args...)
+	return normalizeExprValue(v), err
+}
+
+type unaryNode struct {
+	op      string
+	operand exprNode
+}
+
+func (n unaryNode) eval(env *exprEnv) (any, error) {
+	v, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "!":
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("operand of ! is %T, not bool", v)
+		}
+		return !b, nil
+	default:
+		f, ok := v.(float64)
+		if !ok {
+			return nil, fmt.Errorf("operand of - is %T, not number", v)
+		}
+		return -f, nil
+	}
+}
+
+type binaryNode struct {
+	op          string
+	left, right exprNode
+}
+
+func (n binaryNode) eval(env *exprEnv) (any, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.op == "&&" || n.op == "||" {
+		l, ok := left.(bool)
+		if !ok {
+			return nil, fmt.Errorf("left operand of %s is %T, not bool", n.op, left)
+		}
+		if l == (n.op == "||") {
+			return l, nil
+		}
+		right, err := n.right.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		r, ok := right.(bool)
+		if !ok {
+			return nil, fmt.Errorf("right operand of %s is %T, not bool", n.op, right)
+		}
+		return r, nil
+	}
+
+	right, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return exprEqual(left, right), nil
+	case "!=":
+		return !exprEqual(left, right), nil
+	case "=~", "!~":
+		s, ok := left.(string)
+		if !ok {
+			return nil, fmt.Errorf("left operand of %s is %T, not string", n.op, left)
+		}
+		re, ok := right.(*regexp.Regexp)
+		if !ok {
+			pattern, ok := right.(string)
+			if !ok {
+				return nil, fmt.Errorf("pattern of %s is %T, not string", n.op, right)
+			}
+			if re, err = regexp.Compile(pattern); err != nil {
+				return nil, err
+			}
+		}
+		return re.MatchString(s) == (n.op == "=~"), nil
+	case "<", "<=", ">", ">=":
+		c, err := compareExprValues(left, right, n.op)
+		if err != nil {
+			return nil, err
+		}
+		switch n.op {
+		case "<":
+			return c < 0, nil
+		case "<=":
+			return c <= 0, nil
+		case ">":
+			return c > 0, nil
+		}
+		return c >= 0, nil
+	case "+":
+		if l, ok := left.(string); ok {
+			if r, ok := right.(string); ok {
+				return l + r, nil
+			}
+		}
+	}
+
+	l, ok1 := left.(float64)
+	r, ok2 := right.(float64)
+	if !ok1 || !ok2 {
+		return nil, fmt.Errorf("operands of %s are %T and %T, not numbers", n.op, left, right)
+	}
+	switch n.op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		if r == 0 {
+			return nil, errors.New("division by zero")
+		}
+		return l / r, nil
+	}
+	if r == 0 {
+		return nil, errors.New("division by zero")
+	}
+	return math.Mod(l, r), nil
+}
+
+// exprEqual reports whether a and b are equal. Lists and maps, which
+// cannot be compared with ==, are compared element by element.
+func exprEqual(a, b any) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if reflect.TypeOf(a).Comparable() && reflect.TypeOf(b).Comparable() {
+		return a == b
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func compareExprValues(left, right any, op string) (int, error) {
+	switch l := left.(type) {
+	case float64:
+		if r, ok := right.(float64); ok {
+			switch {
+			case l < r:
+				return -1, nil
+			case l > r:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	case string:
+		if r, ok := right.(string); ok {
+			return strings.Compare(l, r), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot compare %T and %T with %s", left, right, op)
+}
@@ -0,0 +1,128 @@
+package gobag
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExpr(t *testing.T) {
+	vars := map[string]any{
+		"status": "ok",
+		"port":   8080,
+		"name":   "web-01",
+		"tags":   []any{"a", "b"},
+		"meta":   map[string]any{"region": "eu", "weight": 0.5},
+		"same":   []any{"a", "b"},
+		"other":  []any{"b", "a"},
+		"copy":   map[string]any{"region": "eu", "weight": 0.5},
+	}
+
+	tests := []struct {
+		expr     string
+		expected any
+	}{
+		{`status == "ok" && (port > 1024 || name =~ "web.*")`, true},
+		{`status != 'ok' || port < 1024`, false},
+		{`name =~ "^db"`, false},
+		{`name !~ "^db"`, true},
+		{`meta.region == "eu" && meta.weight >= 0.5`, true},
+		{`missing == null`, true},
+		{`meta.region.x == null`, true},
+		{`!(port == 8080)`, false},
+		{`1 + 2 * 3 - -4`, 11.0},
+		{`(1 + 2) * 3 % 4`, 1.0},
+		{`"a" + 'b'`, "ab"},
+		{`len(tags) == 2 && len(name) == 6`, true},
+		{`upper(meta.region) == "EU" && hasprefix(name, "web")`, true},
+		{`"abc" < "abd"`, true},
+		{`false && undefined(1)`, false},
+		{`0x10 == 16`, true},
+		{`tags == same`, true},
+		{`tags != same`, false},
+		{`tags == other`, false},
+		{`tags != other`, true},
+		{`meta == meta`, true},
+		{`meta != copy`, false},
+		{`meta == other`, false},
+		{`tags == "a"`, false},
+	}
+	for _, tt := range tests {
+		e, err := ParseExpr(tt.expr)
+		if err != nil {
+			t.Errorf("ParseExpr(%q) error = %v", tt.expr, err)
+			continue
+		}
+		got, err := e.Eval(vars)
+		if err != nil || got != tt.expected {
+			t.Errorf("Eval(%q) = %v, %v, want %v", tt.expr, got, err, tt.expected)
+		}
+	}
+}
+
+func TestExprFuncs(t *testing.T) {
+	e, err := ParseExpr(`double(port) == 16160 && len(name) == 1`)
+	if err != nil {
+		t.Fatalf("ParseExpr() error = %v", err)
+	}
+	funcs := map[string]ExprFunc{
+		"double": func(args ...any) (any, error) { return args[0].(float64) * 2, nil },
+		"len":    func(args ...any) (any, error) { return 1, nil },
+	}
+	ok, err := e.EvalWith(map[string]any{"port": 8080, "name": "abc"}, funcs)
+	if err != nil || ok != true {
+		t.Errorf("EvalWith() = %v, %v, want true", ok, err)
+	}
+
+	boom := errors.New("boom")
+	e, _ = ParseExpr(`fail()`)
+	if _, err := e.EvalWith(nil, map[string]ExprFunc{"fail": func(...any) (any, error) { return nil, boom }}); !errors.Is(err, boom) {
+		t.Errorf("EvalWith() error = %v, want %v", err, boom)
+	}
+}
+
+func TestExprErrors(t *testing.T) {
+	parseErrors := []struct {
+		expr string
+		err  string
+	}{
+		{``, "offset 0: unexpected end of expression"},
+		{`a ==`, "offset 4: unexpected end of expression"},
+		{`(a == 1`, "offset 7: expected ')'"},
+		{`a == 1)`, `offset 6: unexpected ")"`},
+		{`a @ 1`, `offset 2: unexpected character '@'`},
+		{`a =~ "("`, "error parsing regexp"},
+		{`f(a b)`, "offset 4: expected ',' or ')'"},
+		{`"abc`, "offset 0: unterminated string"},
+	}
+	for _, tt := range parseErrors {
+		_, err := ParseExpr(tt.expr)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("ParseExpr(%q) error = %v, want %q", tt.expr, err, tt.err)
+		}
+	}
+
+	evalErrors := []string{
+		`a && true`,
+		`1 < "a"`,
+		`unknown()`,
+		`1 / 0`,
+		`-"a"`,
+		`1 =~ "x"`,
+	}
+	for _, s := range evalErrors {
+		e, err := ParseExpr(s)
+		if err != nil {
+			t.Errorf("ParseExpr(%q) error = %v", s, err)
+			continue
+		}
+		if _, err := e.Eval(map[string]any{"a": 1}); err == nil {
+			t.Errorf("Eval(%q): expected error", s)
+		}
+	}
+
+	e, _ := ParseExpr(`1 + 1`)
+	if _, err := e.Match(nil); err == nil {
+		t.Error("Match() of non-boolean: expected error")
+	}
+}
@@ -0,0 +1,247 @@
+package gobag
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"iter"
+	"os"
+	"slices"
+	"strings"
+)
+
+// maxRuns is the number of sorted runs kept on disk before they are
+// merged into one, bounding the number of open files.
+const maxRuns = 64
+
+// sortedRun is a temporary file holding sorted records, each written
+// as its length as a uvarint followed by its bytes.
+type sortedRun struct {
+	f   *os.File
+	err error
+}
+
+// writeRun writes the sorted records to a new temporary file in dir.
+func writeRun(dir string, records iter.Seq[string]) (*sortedRun, error) {
+	f, err := os.CreateTemp(dir, "gobag-run-*")
+	if err != nil {
+		return nil, err
+	}
+	run := &sortedRun{f: f}
+
+	w := bufio.NewWriter(f)
+	var lenBuf [binary.MaxVarintLen64]byte
+	for s := range records {
+		n := binary.PutUvarint(lenBuf[:], uint64(len(s)))
+		w.Write(lenBuf[:n])
+		w.WriteString(s)
+	}
+	if err := w.Flush(); err != nil {
+		run.remove()
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// records returns the records of the run. Read errors end the
+// sequence and are recorded in r.err.
+func (r *sortedRun) records() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		if _, err := r.f.Seek(0, io.SeekStart); err != nil {
+			r.err = err
+			return
+		}
+		br := bufio.NewReader(r.f)
+		var buf []byte
+		for {
+			n, err := binary.ReadUvarint(br)
+			if err == io.EOF {
+				return
+			}
+			if err == nil {
+				buf = slices.Grow(buf[:0], int(n))[:n]
+				_, err = io.ReadFull(br, buf)
+			}
+			if err != nil {
+				r.err = errors.Join(errors.New("corrupt temporary run file"), err)
+				return
+			}
+			if !yield(string(buf)) {
+				return
+			}
+		}
+	}
+}
+
+// remove closes and deletes the run's file.
+func (r *sortedRun) remove() error {
+	return errors.Join(r.f.Close(), os.Remove(r.f.Name()))
+}
+
+// mergeRuns returns the records of the runs merged in sorted order.
+// Errors reading any run are recorded in that run's err.
+func mergeRuns(runs []*sortedRun, less func(a, b string) bool) iter.Seq[string] {
+	seqs := make([]iter.Seq[string], len(runs))
+	for i, r := range runs {
+		seqs[i] = r.records()
+	}
+	return MergeSortedSeq(less, seqs...)
+}
+
+// compactRuns writes the merged records of runs, as returned by
+// mergeRuns, to a new run and removes the old ones. If the merge fails
+// the old runs are kept and the returned run is nil.
+func compactRuns(dir string, runs []*sortedRun, merged iter.Seq[string]) (*sortedRun, error) {
+	run, err := writeRun(dir, merged)
+	if err == nil {
+		err = runsErr(runs)
+	}
+	if err != nil {
+		if run != nil {
+			run.remove()
+		}
+		return nil, err
+	}
+
+	return run, removeRuns(runs)
+}
+
+// runsErr returns the read errors recorded in runs.
+func runsErr(runs []*sortedRun) error {
+	errs := make([]error, 0)
+	for _, r := range runs {
+		errs = append(errs, r.err)
+	}
+	return errors.Join(errs...)
+}
+
+// removeRuns removes the files of all runs.
+func removeRuns(runs []*sortedRun) error {
+	errs := make([]error, 0)
+	for _, r := range runs {
+		errs = append(errs, r.remove())
+	}
+	return errors.Join(errs...)
+}
+
+// uniqueSorted returns seq with adjacent duplicates removed.
+func uniqueSorted(seq iter.Seq[string]) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		var prev string
+		first := true
+		for s := range seq {
+			if !first && s == prev {
+				continue
+			}
+			if !yield(s) {
+				return
+			}
+			prev, first = s, false
+		}
+	}
+}
+
+// SortOptions holds optional settings for SortLargeFile.
+type SortOptions struct {
+	// MaxMemory is the approximate number of bytes of records sorted
+	// in memory at a time. The default is 64 MiB.
+	MaxMemory int
+
+	// TempDir is the directory of the temporary files. The default is
+	// os.TempDir().
+	TempDir string
+}
+
+// SortLargeFile sorts the lines read from in according to less and
+// writes them to out, each terminated by a newline. Inputs larger than
+// the memory limit are sorted in chunks written to temporary files,
+// which are then merged. The sort is stable, so equal lines keep their
+// input order.
+func SortLargeFile(in io.Reader, out io.Writer, less func(a, b string) bool, opts SortOptions) (err error) {
+	if opts.MaxMemory <= 0 {
+		opts.MaxMemory = 64 << 20
+	}
+	cmp := func(a, b string) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		}
+		return 0
+	}
+
+	var runs []*sortedRun
+	defer func() {
+		err = errors.Join(err, removeRuns(runs))
+	}()
+
+	chunk := make([]string, 0)
+	var size int
+	spill := func() error {
+		slices.SortStableFunc(chunk, cmp)
+		run, err := writeRun(opts.TempDir, slices.Values(chunk))
+		if err != nil {
+			return err
+		}
+		runs = append(runs, run)
+		chunk, size = chunk[:0], 0
+
+		if len(runs) >= maxRuns {
+			run, err := compactRuns(opts.TempDir, runs, mergeRuns(runs, less))
+			if err != nil {
+				return err
+			}
+			runs = []*sortedRun{run}
+		}
+		return nil
+	}
+
+	r := bufio.NewReader(in)
+	for {
+		line, err := r.ReadString('\n')
+		if len(line) > 0 {
+			line = strings.TrimSuffix(line, "\n")
+			chunk = append(chunk, line)
+			size += len(line) + 16
+			if size >= opts.MaxMemory {
+				if err := spill(); err != nil {
+					return err
+				}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	var sorted iter.Seq[string]
+	if len(runs) == 0 {
+		slices.SortStableFunc(chunk, cmp)
+		sorted = slices.Values(chunk)
+	} else {
+		if len(chunk) > 0 {
+			if err := spill(); err != nil {
+				return err
+			}
+		}
+		sorted = mergeRuns(runs, less)
+	}
+
+	w := bufio.NewWriter(out)
+	for line := range sorted {
+		w.WriteString(line)
+		if err := w.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	if err := runsErr(runs); err != nil {
+		return err
+	}
+	return w.Flush()
+}
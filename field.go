@@ -0,0 +1,98 @@
+package gobag
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Field is a field of a string as split by FieldsLazy. It holds the
+// field verbatim, and only builds its value when asked for it.
+type Field struct {
+	raw     string
+	quoted  bool
+	escaped bool
+	invalid bool
+}
+
+// FieldsLazy splits s like Fields, but returns the fields as Field
+// views of s instead of strings. Removing escape characters and
+// replacing invalid UTF-8 is deferred to Field.Value, so callers that
+// only look at some of the fields don't pay for the others. Returns
+// the same errors as Fields.
+func FieldsLazy(s string, sep rune) ([]Field, error) {
+	var state quoteState
+	fields := make([]Field, 0, strings.Count(s, string(sep))+1)
+	var f Field
+	start := 0
+	for i, r := range s {
+		switch {
+		case r == utf8.RuneError:
+			if _, size := utf8.DecodeRuneInString(s[i:]); size == 1 {
+				f.invalid = true
+			}
+		case r == '\\' && !state.escaped:
+			f.escaped = true
+		case (r == '"' || r == '\'') && !state.escaped:
+			f.quoted = true
+		}
+		if state.next(r) && r == sep {
+			f.raw = s[start:i]
+			fields = append(fields, f)
+			f = Field{}
+			start = i + utf8.RuneLen(r)
+		}
+	}
+	if err := state.err(); err != nil {
+		return nil, err
+	}
+
+	if start < len(s) {
+		f.raw = s[start:]
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// Raw returns the field as it appears in the input, with escape
+// characters intact.
+func (f Field) Raw() string {
+	return f.raw
+}
+
+// Quoted reports whether the field contains quotes.
+func (f Field) Quoted() bool {
+	return f.quoted
+}
+
+// Escaped reports whether the field contains escape characters.
+func (f Field) Escaped() bool {
+	return f.escaped
+}
+
+// Value returns the field as Fields would return it: escape characters
+// are removed and invalid UTF-8 is replaced by U+FFFD, while quotes are
+// kept. Fields without escapes or invalid UTF-8 are returned without
+// copying.
+func (f Field) Value() string {
+	if !f.escaped && !f.invalid {
+		return f.raw
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(f.raw))
+	escaped := false
+	for _, r := range f.raw {
+		if r == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		escaped = false
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// String returns the value of the field, see Value.
+func (f Field) String() string {
+	return f.Value()
+}
@@ -0,0 +1,57 @@
+package gobag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFieldsLazy(t *testing.T) {
+	inputs := []string{
+		"", "a,b,c", ",,a,,", `a\,b,c`, `"a,b",'c,d',(e,f)`, `x="say \"hi\""`,
+		"Ærlig,Åse", "a\xff,b\\\xfe", `"unterminated`, `a\`, `(a))`,
+	}
+	for _, s := range inputs {
+		want, wantErr := Fields(s, ',')
+		fields, err := FieldsLazy(s, ',')
+		if (err == nil) != (wantErr == nil) || err != nil && err.Error() != wantErr.Error() {
+			t.Errorf("FieldsLazy(%q) error = %v, want %v", s, err, wantErr)
+			continue
+		}
+		var got []string
+		if fields != nil {
+			got = make([]string, 0, len(fields))
+		}
+		for _, f := range fields {
+			got = append(got, f.Value())
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("FieldsLazy(%q) values = %q, want %q", s, got, want)
+		}
+	}
+}
+
+func TestField(t *testing.T) {
+	fields, err := FieldsLazy(`plain,"quoted",esc\,aped,'both\''`, ',')
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		raw, value      string
+		quoted, escaped bool
+	}{
+		{"plain", "plain", false, false},
+		{`"quoted"`, `"quoted"`, true, false},
+		{`esc\,aped`, "esc,aped", false, true},
+		{`'both\''`, `'both''`, true, true},
+	}
+	if len(fields) != len(tests) {
+		t.Fatalf("FieldsLazy() returned %d fields, want %d", len(fields), len(tests))
+	}
+	for i, tt := range tests {
+		f := fields[i]
+		if f.Raw() != tt.raw || f.Value() != tt.value || f.Quoted() != tt.quoted || f.Escaped() != tt.escaped {
+			t.Errorf("field %d = %q, %q, %v, %v, want %q, %q, %v, %v", i,
+				f.Raw(), f.Value(), f.Quoted(), f.Escaped(), tt.raw, tt.value, tt.quoted, tt.escaped)
+		}
+	}
+}
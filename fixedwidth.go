@@ -0,0 +1,103 @@
+package gobag
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseFixedWidth splits a line of fixed width columns into fields,
+// with widths giving the width of each column in runes. Fields are
+// trimmed of surrounding spaces. A line too short for all columns
+// yields empty fields for the missing ones, and text beyond the last
+// column is ignored, unless the last width is negative, in which case
+// the last field holds the rest of the line.
+func ParseFixedWidth(line string, widths []int) []string {
+	fields := make([]string, len(widths))
+	for i, w := range widths {
+		end := len(line)
+		if w >= 0 {
+			end = runeOffset(line, w)
+		}
+		fields[i] = strings.Trim(line[:end], " ")
+		line = line[end:]
+	}
+
+	return fields
+}
+
+// runeOffset returns the byte offset of the rune at index n of s, or
+// len(s) if s has fewer runes.
+func runeOffset(s string, n int) int {
+	for i := range s {
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+	return len(s)
+}
+
+// Alignment is the alignment of a value within a fixed width column.
+type Alignment int
+
+// Alignments for FormatFixedWidth.
+const (
+	AlignLeft Alignment = iota
+	AlignRight
+)
+
+// FixedWidthOptions holds optional settings for FormatFixedWidth.
+type FixedWidthOptions struct {
+	// Align holds the alignment of each column. Columns without an
+	// alignment are left aligned.
+	Align []Alignment
+
+	// Pad is the rune filling columns. The default is a space.
+	Pad rune
+
+	// Truncate cuts values too long for their column. By default such
+	// values are an error.
+	Truncate bool
+}
+
+// FormatFixedWidth formats fields as a line of fixed width columns,
+// the inverse of ParseFixedWidth, with widths giving the width of
+// each column in runes. A negative last width, the rest of the line
+// for ParseFixedWidth, writes the value without padding. Returns an
+// error if the number of fields and widths differ, or a value is too
+// long for its column and Truncate is not set.
+func FormatFixedWidth(fields []string, widths []int, opts FixedWidthOptions) (string, error) {
+	if len(fields) != len(widths) {
+		return "", fmt.Errorf("got %d fields for %d columns", len(fields), len(widths))
+	}
+	pad := opts.Pad
+	if pad == 0 {
+		pad = ' '
+	}
+
+	var sb strings.Builder
+	for i, f := range fields {
+		w := widths[i]
+		if w < 0 {
+			sb.WriteString(f)
+			continue
+		}
+		n := utf8.RuneCountInString(f)
+		if n > w {
+			if !opts.Truncate {
+				return "", fmt.Errorf("field %d: value %q exceeds column width %d", i, f, w)
+			}
+			f, n = f[:runeOffset(f, w)], w
+		}
+
+		fill := strings.Repeat(string(pad), w-n)
+		if i < len(opts.Align) && opts.Align[i] == AlignRight {
+			sb.WriteString(fill + f)
+		} else {
+			sb.WriteString(f + fill)
+		}
+	}
+
+	return sb.String(), nil
+}
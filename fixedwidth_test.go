@@ -0,0 +1,71 @@
+package gobag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFixedWidth(t *testing.T) {
+	tests := []struct {
+		line     string
+		widths   []int
+		expected []string
+	}{
+		{"ACME  Oslo      00042", []int{6, 10, 5}, []string{"ACME", "Oslo", "00042"}},
+		{"Ærø   Tromsø    00001", []int{6, 10, 5}, []string{"Ærø", "Tromsø", "00001"}},
+		{"ACME  Os", []int{6, 10, 5}, []string{"ACME", "Os", ""}},
+		{"ACME  Oslo      00042trailing", []int{6, 10, 5}, []string{"ACME", "Oslo", "00042"}},
+		{"ID01  free text here", []int{6, -1}, []string{"ID01", "free text here"}},
+		{"", []int{3}, []string{""}},
+	}
+	for _, tt := range tests {
+		if got := ParseFixedWidth(tt.line, tt.widths); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("ParseFixedWidth(%q, %v) = %q, want %q", tt.line, tt.widths, got, tt.expected)
+		}
+	}
+}
+
+func TestFormatFixedWidth(t *testing.T) {
+	widths := []int{6, 10, 5}
+	tests := []struct {
+		fields   []string
+		opts     FixedWidthOptions
+		expected string
+		err      string
+	}{
+		{[]string{"ACME", "Oslo", "42"}, FixedWidthOptions{}, "ACME  Oslo      42   ", ""},
+		{[]string{"Ærø", "Tromsø", "42"}, FixedWidthOptions{Align: []Alignment{AlignLeft, AlignLeft, AlignRight}},
+			"Ærø   Tromsø       42", ""},
+		{[]string{"A", "B", "42"}, FixedWidthOptions{Align: []Alignment{2: AlignRight}, Pad: '0'},
+			"A00000B00000000000042", ""},
+		{[]string{"ACME Corp", "Oslo", "1"}, FixedWidthOptions{Truncate: true}, "ACME COslo      1    ", ""},
+		{[]string{"ACME Corp", "Oslo", "1"}, FixedWidthOptions{}, "", `field 0: value "ACME Corp" exceeds column width 6`},
+		{[]string{"a"}, FixedWidthOptions{}, "", "got 1 fields for 3 columns"},
+	}
+	for _, tt := range tests {
+		got, err := FormatFixedWidth(tt.fields, widths, tt.opts)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("FormatFixedWidth(%q) error = %v, want %q", tt.fields, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.expected {
+			t.Errorf("FormatFixedWidth(%q) = %q, %v, want %q", tt.fields, got, err, tt.expected)
+		}
+	}
+
+	for _, widths := range [][]int{widths, {6, 10, -1}} {
+		fields := []string{"Ærø", "Tromsø", "1"}
+		if widths[2] < 0 {
+			fields[2] = "the rest of the line"
+		}
+		line, err := FormatFixedWidth(fields, widths, FixedWidthOptions{})
+		if err != nil {
+			t.Fatalf("FormatFixedWidth(%q, %v) error = %v", fields, widths, err)
+		}
+		if got := ParseFixedWidth(line, widths); !reflect.DeepEqual(got, fields) {
+			t.Errorf("round trip with widths %v = %q, want %q", widths, got, fields)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package gobag
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Integer is a constraint that permits any integer type.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// HasFlag reports whether all bits of flag are set in v.
+func HasFlag[T Integer](v, flag T) bool {
+	return v&flag == flag
+}
+
+// SetFlag returns v with the bits of flag set.
+func SetFlag[T Integer](v, flag T) T {
+	return v | flag
+}
+
+// ClearFlag returns v with the bits of flag cleared.
+func ClearFlag[T Integer](v, flag T) T {
+	return v &^ flag
+}
+
+// ToggleFlag returns v with the bits of flag flipped.
+func ToggleFlag[T Integer](v, flag T) T {
+	return v ^ flag
+}
+
+// ParseFlags parses a set of flags written as names separated by '|',
+// such as "read|write", into a bitmask using table to look up the value
+// of each name. Names may be surrounded by whitespace and quoted. An
+// empty string yields 0. Returns an error for unknown names, or if
+// quotes or parentheses are unbalanced.
+func ParseFlags[T Integer](s string, table map[string]T) (T, error) {
+	var v T
+	if strings.TrimSpace(s) == "" {
+		return v, nil
+	}
+
+	names, err := Fields(s, '|')
+	if err != nil {
+		return 0, err
+	}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if unquoted, err := UnquoteStringWith(name, UnquoteOptions{Strict: true, SingleQuote: true}); err == nil {
+			name = unquoted
+		}
+		flag, ok := table[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown flag %q", name)
+		}
+		v |= flag
+	}
+
+	return v, nil
+}
+
+// FormatFlags is the inverse of ParseFlags. It returns the sorted
+// names in table whose bits are all set in v, separated by '|'.
+func FormatFlags[T Integer](v T, table map[string]T) string {
+	names := make([]string, 0)
+	for name, flag := range table {
+		if flag != 0 && HasFlag(v, flag) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	return strings.Join(names, "|")
+}
@@ -0,0 +1,47 @@
+package gobag
+
+import "testing"
+
+func TestFlags(t *testing.T) {
+	type perm uint8
+	const (
+		read perm = 1 << iota
+		write
+		exec
+	)
+	table := map[string]perm{"read": read, "write": write, "exec": exec}
+
+	v := SetFlag(read, exec)
+	if !HasFlag(v, read|exec) || HasFlag(v, write) {
+		t.Errorf("SetFlag() = %b", v)
+	}
+	if v = ClearFlag(v, read); v != exec {
+		t.Errorf("ClearFlag() = %b, want %b", v, exec)
+	}
+	if v = ToggleFlag(v, exec|write); v != write {
+		t.Errorf("ToggleFlag() = %b, want %b", v, write)
+	}
+
+	tests := []struct {
+		input    string
+		expected perm
+		fail     bool
+	}{
+		{"", 0, false},
+		{"read", read, false},
+		{"read | write", read | write, false},
+		{`"read"|'exec'`, read | exec, false},
+		{"read|delete", 0, true},
+		{`read|"write`, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFlags(tt.input, table)
+		if (err != nil) != tt.fail || got != tt.expected {
+			t.Errorf("ParseFlags(%q) = %b, %v, want %b", tt.input, got, err, tt.expected)
+		}
+	}
+
+	if s := FormatFlags(read|write, table); s != "read|write" {
+		t.Errorf("FormatFlags() = %q, want read|write", s)
+	}
+}
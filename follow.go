@@ -0,0 +1,178 @@
+package gobag
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"iter"
+	"os"
+	"time"
+)
+
+// FollowOptions holds optional settings for FollowWith.
+type FollowOptions struct {
+	// FromStart yields the lines already in the file before following
+	// it. By default following starts at the end of the file.
+	FromStart bool
+
+	// PollInterval is how often the file is checked for new data. The
+	// default is 250ms.
+	PollInterval time.Duration
+
+	// Clock times the polling. The default is RealClock.
+	Clock Clock
+}
+
+// Follow yields the lines appended to the named file, like tail -F,
+// until ctx is done. Lines are yielded only once complete, without
+// their line ending. Follow keeps following the path when the file is
+// truncated, or rotated by renaming or removing it and creating a new
+// one, and waits for the file to appear if it does not exist. Other
+// errors are yielded and end the sequence.
+func Follow(ctx context.Context, path string) iter.Seq2[string, error] {
+	return FollowWith(ctx, path, FollowOptions{})
+}
+
+// FollowWith is like Follow, but takes options altering its behavior.
+func FollowWith(ctx context.Context, path string, opts FollowOptions) iter.Seq2[string, error] {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 250 * time.Millisecond
+	}
+	if opts.Clock == nil {
+		opts.Clock = RealClock{}
+	}
+
+	return func(yield func(string, error) bool) {
+		fw := &follower{path: path, yield: yield}
+		defer fw.close()
+
+		if err := fw.open(opts.FromStart); err != nil {
+			yield("", err)
+			return
+		}
+		for {
+			ok, err := fw.poll()
+			if err != nil {
+				yield("", err)
+				return
+			}
+			if !ok || sleepClock(ctx, opts.Clock, opts.PollInterval) != nil {
+				return
+			}
+		}
+	}
+}
+
+type follower struct {
+	path    string
+	f       *os.File
+	offset  int64
+	partial []byte
+	buf     []byte
+	yield   func(string, error) bool
+}
+
+// open opens the file, positioned at its start or end. A missing file
+// is not an error; it is opened when it appears.
+func (fw *follower) open(fromStart bool) error {
+	f, err := os.Open(fw.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	fw.offset = 0
+	if !fromStart {
+		if fw.offset, err = f.Seek(0, io.SeekEnd); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	fw.f = f
+	fw.partial = fw.partial[:0]
+	return nil
+}
+
+func (fw *follower) close() {
+	if fw.f != nil {
+		fw.f.Close()
+		fw.f = nil
+	}
+}
+
+// poll yields the lines appended since the last call, and handles
+// truncation and rotation. It returns false if the consumer stopped.
+func (fw *follower) poll() (bool, error) {
+	if fw.f == nil {
+		// Files appearing later are read from the start.
+		if err := fw.open(true); err != nil || fw.f == nil {
+			return true, err
+		}
+	}
+
+	fi, err := fw.f.Stat()
+	if err != nil {
+		return true, err
+	}
+	if fi.Size() < fw.offset {
+		// Truncated: start over.
+		if _, err := fw.f.Seek(0, io.SeekStart); err != nil {
+			return true, err
+		}
+		fw.offset = 0
+		fw.partial = fw.partial[:0]
+	}
+	if ok, err := fw.read(); !ok || err != nil {
+		return ok, err
+	}
+
+	current, err := os.Stat(fw.path)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && !os.SameFile(fi, current)) {
+		// Rotated: finish the old file, then switch to the new one.
+		if ok, err := fw.read(); !ok || err != nil {
+			return ok, err
+		}
+		if len(fw.partial) > 0 && !fw.yield(string(fw.partial), nil) {
+			return false, nil
+		}
+		fw.close()
+		return true, fw.open(true)
+	}
+	return true, err
+}
+
+// read yields the complete lines read from the current file.
+func (fw *follower) read() (bool, error) {
+	if fw.buf == nil {
+		fw.buf = make([]byte, 32<<10)
+	}
+	for {
+		n, err := fw.f.Read(fw.buf)
+		fw.offset += int64(n)
+		data := fw.buf[:n]
+		for {
+			i := bytes.IndexByte(data, '\n')
+			if i < 0 {
+				break
+			}
+			line := append(fw.partial, data[:i]...)
+			fw.partial = fw.partial[:0]
+			if !fw.yield(string(bytes.TrimSuffix(line, []byte("\r"))), nil) {
+				return false, nil
+			}
+			data = data[i+1:]
+		}
+		fw.partial = append(fw.partial, data...)
+
+		if err == io.EOF || (n == 0 && err == nil) {
+			return true, nil
+		}
+		if err != nil {
+			return true, err
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package gobag
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFollow(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	if err := os.WriteFile(path, []byte("old line\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		for line, err := range FollowWith(ctx, path, FollowOptions{PollInterval: time.Millisecond}) {
+			if err != nil {
+				t.Errorf("Follow() error = %v", err)
+				return
+			}
+			lines <- line
+		}
+	}()
+
+	expect := func(want string) {
+		t.Helper()
+		select {
+		case got := <-lines:
+			if got != want {
+				t.Fatalf("Follow() yielded %q, want %q", got, want)
+			}
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for %q", want)
+		}
+	}
+	appendFile := func(s string) {
+		t.Helper()
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.WriteString(s)
+		f.Close()
+	}
+
+	// Give the follower time to open the file at its end.
+	time.Sleep(20 * time.Millisecond)
+	appendFile("first\nsec")
+	expect("first")
+	appendFile("ond\r\n")
+	expect("second")
+
+	// Truncation starts over.
+	os.WriteFile(path, []byte("x\n"), 0o644)
+	expect("x")
+
+	// Rotation yields the rest of the old file, then the new one.
+	appendFile("tail")
+	time.Sleep(20 * time.Millisecond)
+	os.Rename(path, path+".1")
+	appendFile("rotated\n")
+	expect("tail")
+	expect("rotated")
+
+	cancel()
+	for range lines {
+	}
+}
+
+func TestFollowFromStart(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "later.log")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		os.WriteFile(path, []byte("a\nb\n"), 0o644)
+	}()
+
+	var got []string
+	for line, err := range FollowWith(ctx, path, FollowOptions{FromStart: true, PollInterval: time.Millisecond}) {
+		if err != nil {
+			t.Fatalf("Follow() error = %v", err)
+		}
+		got = append(got, line)
+		if len(got) == 2 {
+			break
+		}
+	}
+	if len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("Follow() = %q, want [a b]", got)
+	}
+}
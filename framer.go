@@ -0,0 +1,161 @@
+package gobag
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// ErrFrameTooLarge is returned by Framer when a frame exceeds the
+// maximum frame size.
+var ErrFrameTooLarge = errors.New("frame too large")
+
+// FrameMode selects how a Framer separates frames.
+type FrameMode int
+
+// Framing modes.
+const (
+	// FrameDelimited ends each frame with a delimiter byte. Delimiters
+	// and backslashes within frames are escaped with a backslash, as
+	// in Fields.
+	FrameDelimited FrameMode = iota
+	// FrameLengthPrefixed precedes each frame with its length as a
+	// 4 byte big-endian unsigned integer.
+	FrameLengthPrefixed
+)
+
+// FramerOptions holds optional settings for NewFramer.
+type FramerOptions struct {
+	// Mode selects the framing. The default is FrameDelimited.
+	Mode FrameMode
+
+	// Delimiter ends frames in FrameDelimited mode. The default is
+	// '\n'. It must not be a backslash.
+	Delimiter byte
+
+	// MaxFrameSize is the largest frame accepted by ReadFrame and
+	// WriteFrame. The default is 1 MiB.
+	MaxFrameSize int
+}
+
+// Framer reads and writes discrete messages, frames, over a byte
+// stream such as a network connection. A Framer is not safe for
+// concurrent use, but a goroutine reading frames and another writing
+// them do not interfere.
+type Framer struct {
+	r    *bufio.Reader
+	w    io.Writer
+	opts FramerOptions
+}
+
+// NewFramer returns a Framer reading frames from r and writing them to
+// w. Either may be nil if the Framer is only used in one direction.
+func NewFramer(r io.Reader, w io.Writer, opts FramerOptions) *Framer {
+	if opts.Delimiter == 0 {
+		opts.Delimiter = '\n'
+	}
+	if opts.MaxFrameSize <= 0 {
+		opts.MaxFrameSize = 1 << 20
+	}
+	f := &Framer{w: w, opts: opts}
+	if r != nil {
+		f.r = bufio.NewReader(r)
+	}
+	return f
+}
+
+// ReadFrame reads the next frame. It returns io.EOF if the stream ends
+// between frames, and io.ErrUnexpectedEOF if it ends within a frame.
+// A frame larger than MaxFrameSize is skipped and reported with
+// ErrFrameTooLarge, so the next call reads the frame after it.
+func (f *Framer) ReadFrame() ([]byte, error) {
+	if f.opts.Mode == FrameLengthPrefixed {
+		return f.readPrefixed()
+	}
+	return f.readDelimited()
+}
+
+func (f *Framer) readPrefixed() ([]byte, error) {
+	var prefix [4]byte
+	if _, err := io.ReadFull(f.r, prefix[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(prefix[:])
+	if uint64(n) > uint64(f.opts.MaxFrameSize) {
+		if _, err := io.CopyN(io.Discard, f.r, int64(n)); err != nil && err != io.EOF {
+			return nil, err
+		}
+		return nil, ErrFrameTooLarge
+	}
+
+	frame := make([]byte, n)
+	if _, err := io.ReadFull(f.r, frame); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return frame, nil
+}
+
+func (f *Framer) readDelimited() ([]byte, error) {
+	frame := make([]byte, 0)
+	escaped, tooLarge := false, false
+	for {
+		c, err := f.r.ReadByte()
+		if err != nil {
+			switch {
+			case err == io.EOF && tooLarge:
+				err = ErrFrameTooLarge
+			case err == io.EOF && (len(frame) > 0 || escaped):
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+			continue
+		case c == f.opts.Delimiter && tooLarge:
+			return nil, ErrFrameTooLarge
+		case c == f.opts.Delimiter:
+			return frame, nil
+		}
+		if len(frame) >= f.opts.MaxFrameSize {
+			// Skip the rest of the frame.
+			tooLarge = true
+			continue
+		}
+		frame = append(frame, c)
+	}
+}
+
+// WriteFrame writes p as a single frame.
+func (f *Framer) WriteFrame(p []byte) error {
+	if len(p) > f.opts.MaxFrameSize {
+		return ErrFrameTooLarge
+	}
+
+	var buf []byte
+	if f.opts.Mode == FrameLengthPrefixed {
+		buf = make([]byte, 4, 4+len(p))
+		binary.BigEndian.PutUint32(buf, uint32(len(p)))
+		buf = append(buf, p...)
+	} else {
+		buf = make([]byte, 0, len(p)+1)
+		for _, c := range p {
+			if c == '\\' || c == f.opts.Delimiter {
+				buf = append(buf, '\\')
+			}
+			buf = append(buf, c)
+		}
+		buf = append(buf, f.opts.Delimiter)
+	}
+
+	_, err := f.w.Write(buf)
+	return err
+}
@@ -0,0 +1,115 @@
+package gobag
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFramerRoundTrip(t *testing.T) {
+	frames := [][]byte{
+		[]byte("hello"),
+		{},
+		[]byte("line\nbreak"),
+		[]byte(`back\slash\`),
+		[]byte("semi;colon"),
+		{0, 1, 2, 255},
+	}
+	modes := []FramerOptions{
+		{},
+		{Delimiter: ';'},
+		{Mode: FrameLengthPrefixed},
+	}
+	for _, opts := range modes {
+		var buf bytes.Buffer
+		w := NewFramer(nil, &buf, opts)
+		for _, frame := range frames {
+			if err := w.WriteFrame(frame); err != nil {
+				t.Fatalf("WriteFrame(%q) error = %v", frame, err)
+			}
+		}
+
+		r := NewFramer(&buf, nil, opts)
+		var got [][]byte
+		for {
+			frame, err := r.ReadFrame()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("ReadFrame() with %+v error = %v", opts, err)
+			}
+			got = append(got, frame)
+		}
+		if !reflect.DeepEqual(got, frames) {
+			t.Errorf("frames with %+v = %q, want %q", opts, got, frames)
+		}
+	}
+}
+
+func TestFramerEncoding(t *testing.T) {
+	var buf bytes.Buffer
+	NewFramer(nil, &buf, FramerOptions{}).WriteFrame([]byte("a\nb\\c"))
+	if got := buf.String(); got != "a\\\nb\\\\c\n" {
+		t.Errorf("delimited frame = %q", got)
+	}
+
+	buf.Reset()
+	NewFramer(nil, &buf, FramerOptions{Mode: FrameLengthPrefixed}).WriteFrame([]byte("abc"))
+	if got := buf.String(); got != "\x00\x00\x00\x03abc" {
+		t.Errorf("length-prefixed frame = %q", got)
+	}
+}
+
+func TestFramerErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		opts  FramerOptions
+		err   error
+	}{
+		{"partial", FramerOptions{}, io.ErrUnexpectedEOF},
+		{"escape\\", FramerOptions{}, io.ErrUnexpectedEOF},
+		{"toolong\n", FramerOptions{MaxFrameSize: 4}, ErrFrameTooLarge},
+		{"\x00\x00", FramerOptions{Mode: FrameLengthPrefixed}, io.ErrUnexpectedEOF},
+		{"\x00\x00\x00\x05ab", FramerOptions{Mode: FrameLengthPrefixed}, io.ErrUnexpectedEOF},
+		{"\x00\x00\x01\x00", FramerOptions{Mode: FrameLengthPrefixed, MaxFrameSize: 16}, ErrFrameTooLarge},
+		{"", FramerOptions{}, io.EOF},
+	}
+	for _, tt := range tests {
+		_, err := NewFramer(strings.NewReader(tt.input), nil, tt.opts).ReadFrame()
+		if !errors.Is(err, tt.err) {
+			t.Errorf("ReadFrame(%q) error = %v, want %v", tt.input, err, tt.err)
+		}
+	}
+
+	err := NewFramer(nil, io.Discard, FramerOptions{MaxFrameSize: 2}).WriteFrame([]byte("abc"))
+	if !errors.Is(err, ErrFrameTooLarge) {
+		t.Errorf("WriteFrame() error = %v, want %v", err, ErrFrameTooLarge)
+	}
+}
+
+func TestFramerSkipsTooLargeFrames(t *testing.T) {
+	tests := []struct {
+		input string
+		opts  FramerOptions
+	}{
+		{"0123456789\nok\n", FramerOptions{MaxFrameSize: 4}},
+		{"01234\\\n56789\nok\n", FramerOptions{MaxFrameSize: 4}},
+		{"\x00\x00\x00\x0a0123456789\x00\x00\x00\x02ok", FramerOptions{Mode: FrameLengthPrefixed, MaxFrameSize: 4}},
+	}
+	for _, tt := range tests {
+		f := NewFramer(strings.NewReader(tt.input), nil, tt.opts)
+		if _, err := f.ReadFrame(); !errors.Is(err, ErrFrameTooLarge) {
+			t.Errorf("ReadFrame(%q) error = %v, want %v", tt.input, err, ErrFrameTooLarge)
+		}
+		if frame, err := f.ReadFrame(); err != nil || string(frame) != "ok" {
+			t.Errorf("ReadFrame(%q) after ErrFrameTooLarge = %q, %v, want \"ok\"", tt.input, frame, err)
+		}
+		if _, err := f.ReadFrame(); err != io.EOF {
+			t.Errorf("ReadFrame(%q) at end = %v, want io.EOF", tt.input, err)
+		}
+	}
+}
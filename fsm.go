@@ -0,0 +1,128 @@
+package gobag
+
+import "fmt"
+
+// FSM is a finite state machine with states of type S driven by events
+// of type E. Transitions are registered per state and event, optionally
+// with a guard deciding whether they apply, and callbacks can run when
+// a state is entered or exited. An FSM is not safe for concurrent use.
+type FSM[S, E comparable] struct {
+	state       S
+	transitions map[fsmKey[S, E]][]fsmTransition[S, E]
+	enter       map[S][]func(from S, event E)
+	exit        map[S][]func(to S, event E)
+}
+
+type fsmKey[S, E comparable] struct {
+	from  S
+	event E
+}
+
+type fsmTransition[S, E comparable] struct {
+	to    S
+	guard func(from S, event E) bool
+}
+
+// TransitionError is returned by FSM.Fire when no transition from the
+// current state on the event applies.
+type TransitionError[S, E comparable] struct {
+	State S
+	Event E
+	// Guarded is true if there were transitions, but their guards
+	// rejected the event.
+	Guarded bool
+}
+
+// Error implements the error interface.
+func (e *TransitionError[S, E]) Error() string {
+	if e.Guarded {
+		return fmt.Sprintf("transition from %v on %v rejected by guard", e.State, e.Event)
+	}
+	return fmt.Sprintf("no transition from %v on %v", e.State, e.Event)
+}
+
+// NewFSM returns a state machine in the initial state, without
+// transitions.
+func NewFSM[S, E comparable](initial S) *FSM[S, E] {
+	return &FSM[S, E]{
+		state:       initial,
+		transitions: make(map[fsmKey[S, E]][]fsmTransition[S, E]),
+		enter:       make(map[S][]func(S, E)),
+		exit:        make(map[S][]func(S, E)),
+	}
+}
+
+// On registers a transition from state from to state to on event. It
+// returns the FSM, so registrations can be chained.
+func (m *FSM[S, E]) On(from S, event E, to S) *FSM[S, E] {
+	return m.OnIf(from, event, to, nil)
+}
+
+// OnIf is like On, but the transition only applies if guard returns
+// true. Transitions registered for the same state and event are tried
+// in the order they were registered, and the first that applies is
+// taken. A nil guard always applies.
+func (m *FSM[S, E]) OnIf(from S, event E, to S, guard func(from S, event E) bool) *FSM[S, E] {
+	key := fsmKey[S, E]{from, event}
+	m.transitions[key] = append(m.transitions[key], fsmTransition[S, E]{to, guard})
+	return m
+}
+
+// OnEnter registers fn to be called when the machine enters state,
+// with the state it came from and the event causing the transition.
+func (m *FSM[S, E]) OnEnter(state S, fn func(from S, event E)) *FSM[S, E] {
+	m.enter[state] = append(m.enter[state], fn)
+	return m
+}
+
+// OnExit registers fn to be called when the machine leaves state, with
+// the state it is going to and the event causing the transition.
+func (m *FSM[S, E]) OnExit(state S, fn func(to S, event E)) *FSM[S, E] {
+	m.exit[state] = append(m.exit[state], fn)
+	return m
+}
+
+// State returns the current state.
+func (m *FSM[S, E]) State() S {
+	return m.state
+}
+
+// Can reports whether event would cause a transition from the current
+// state.
+func (m *FSM[S, E]) Can(event E) bool {
+	_, err := m.find(event)
+	return err == nil
+}
+
+// Fire makes the transition on event from the current state, calling
+// the exit callbacks of the current state and then the enter callbacks
+// of the new one. Transitions back to the same state call them too.
+// Returns a *TransitionError, leaving the state unchanged, if no
+// transition applies.
+func (m *FSM[S, E]) Fire(event E) error {
+	to, err := m.find(event)
+	if err != nil {
+		return err
+	}
+
+	from := m.state
+	for _, fn := range m.exit[from] {
+		fn(to, event)
+	}
+	m.state = to
+	for _, fn := range m.enter[to] {
+		fn(from, event)
+	}
+	return nil
+}
+
+func (m *FSM[S, E]) find(event E) (S, error) {
+	transitions := m.transitions[fsmKey[S, E]{m.state, event}]
+	for _, t := range transitions {
+		if t.guard == nil || t.guard(m.state, event) {
+			return t.to, nil
+		}
+	}
+	var zero S
+	return zero, &TransitionError[S, E]{State: m.state, Event: event, Guarded: len(transitions) > 0}
+}
@@ -0,0 +1,86 @@
+package gobag
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFSM(t *testing.T) {
+	var log []string
+	retries := 0
+	m := NewFSM[string, string]("idle").
+		On("idle", "start", "running").
+		On("running", "fail", "failed").
+		OnIf("failed", "retry", "running", func(string, string) bool { return retries <= 2 }).
+		On("running", "stop", "idle").
+		OnEnter("running", func(from, event string) { log = append(log, "enter running from "+from+" on "+event) }).
+		OnExit("running", func(to, event string) { log = append(log, "exit running to "+to+" on "+event) }).
+		OnEnter("failed", func(string, string) { retries++ })
+
+	for _, event := range []string{"start", "fail", "retry", "fail", "retry"} {
+		if err := m.Fire(event); err != nil {
+			t.Fatalf("Fire(%q) error = %v", event, err)
+		}
+	}
+	if m.State() != "running" {
+		t.Errorf("State() = %q, want running", m.State())
+	}
+	expected := []string{
+		"enter running from idle on start",
+		"exit running to failed on fail",
+		"enter running from failed on retry",
+		"exit running to failed on fail",
+		"enter running from failed on retry",
+	}
+	if !reflect.DeepEqual(log, expected) {
+		t.Errorf("callbacks = %q, want %q", log, expected)
+	}
+
+	m.Fire("fail")
+	if m.Can("retry") {
+		t.Error("Can(retry) = true after two retries")
+	}
+	err := m.Fire("retry")
+	var terr *TransitionError[string, string]
+	if !errors.As(err, &terr) || !terr.Guarded || terr.State != "failed" || terr.Event != "retry" {
+		t.Errorf("Fire(retry) error = %#v", err)
+	}
+	if err.Error() != "transition from failed on retry rejected by guard" {
+		t.Errorf("Fire(retry) error = %q", err)
+	}
+	if m.State() != "failed" {
+		t.Errorf("State() after rejected event = %q, want failed", m.State())
+	}
+
+	if err := m.Fire("start"); err == nil || err.Error() != "no transition from failed on start" {
+		t.Errorf("Fire(start) error = %v", err)
+	}
+}
+
+func TestFSMGuardOrder(t *testing.T) {
+	type state int
+	type event int
+	const (
+		low state = iota
+		high
+		other
+	)
+	const poke event = 0
+
+	level := 5
+	m := NewFSM[state, event](low).
+		OnIf(low, poke, high, func(state, event) bool { return level > 3 }).
+		On(low, poke, other)
+	if m.Fire(poke); m.State() != high {
+		t.Errorf("State() = %v, want %v", m.State(), high)
+	}
+
+	level = 1
+	m = NewFSM[state, event](low).
+		OnIf(low, poke, high, func(state, event) bool { return level > 3 }).
+		On(low, poke, other)
+	if m.Fire(poke); m.State() != other {
+		t.Errorf("State() = %v, want %v", m.State(), other)
+	}
+}
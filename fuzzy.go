@@ -0,0 +1,88 @@
+package gobag
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NormalizeKey returns s folded to lower case with hyphens,
+// underscores and whitespace removed, so "max-size", "max_size" and
+// "MaxSize" all normalize to "maxsize". It is the default normalizer
+// of FuzzyMap.
+func NormalizeKey(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || unicode.IsSpace(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, s)
+}
+
+// FuzzyMap is a map keyed by strings where keys that normalize to the
+// same string address the same entry. The zero value is an empty map
+// using NormalizeKey. A FuzzyMap is not safe for concurrent use.
+type FuzzyMap[V any] struct {
+	normalize func(string) string
+	entries   map[string]fuzzyEntry[V]
+}
+
+type fuzzyEntry[V any] struct {
+	key   string
+	value V
+}
+
+// NewFuzzyMap returns an empty FuzzyMap using the normalize function
+// to compare keys. A nil normalize uses NormalizeKey.
+func NewFuzzyMap[V any](normalize func(string) string) *FuzzyMap[V] {
+	return &FuzzyMap[V]{normalize: normalize}
+}
+
+func (m *FuzzyMap[V]) norm(key string) string {
+	if m.normalize == nil {
+		return NormalizeKey(key)
+	}
+	return m.normalize(key)
+}
+
+// Set stores value under key, replacing the value and original key of
+// any entry with an equivalent key.
+func (m *FuzzyMap[V]) Set(key string, value V) {
+	if m.entries == nil {
+		m.entries = make(map[string]fuzzyEntry[V])
+	}
+	m.entries[m.norm(key)] = fuzzyEntry[V]{key: key, value: value}
+}
+
+// Get returns the value stored under a key equivalent to key, and
+// whether it was found.
+func (m *FuzzyMap[V]) Get(key string) (V, bool) {
+	e, ok := m.entries[m.norm(key)]
+	return e.value, ok
+}
+
+// Lookup is like Get, but also returns the key the value was stored
+// with.
+func (m *FuzzyMap[V]) Lookup(key string) (storedKey string, value V, ok bool) {
+	e, ok := m.entries[m.norm(key)]
+	return e.key, e.value, ok
+}
+
+// Delete removes the entry with a key equivalent to key, if any.
+func (m *FuzzyMap[V]) Delete(key string) {
+	delete(m.entries, m.norm(key))
+}
+
+// Len returns the number of entries.
+func (m *FuzzyMap[V]) Len() int {
+	return len(m.entries)
+}
+
+// Keys returns the keys the entries were stored with. The order of
+// keys is not guaranteed.
+func (m *FuzzyMap[V]) Keys() []string {
+	keys := make([]string, 0, len(m.entries))
+	for _, e := range m.entries {
+		keys = append(keys, e.key)
+	}
+	return keys
+}
@@ -0,0 +1,39 @@
+package gobag
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFuzzyMap(t *testing.T) {
+	var m FuzzyMap[int]
+	m.Set("max-size", 10)
+
+	for _, key := range []string{"max-size", "max_size", "MaxSize", "MAX SIZE"} {
+		if v, ok := m.Get(key); !ok || v != 10 {
+			t.Errorf("Get(%q) = %d, %t, want 10, true", key, v, ok)
+		}
+	}
+	if _, ok := m.Get("maxsizes"); ok {
+		t.Error(`Get("maxsizes") found an entry`)
+	}
+
+	m.Set("MaxSize", 20)
+	if k, v, ok := m.Lookup("max_size"); !ok || k != "MaxSize" || v != 20 || m.Len() != 1 {
+		t.Errorf("Lookup() = %q, %d, %t (len %d), want MaxSize, 20, true (len 1)", k, v, ok, m.Len())
+	}
+
+	m.Delete("max size")
+	if m.Len() != 0 {
+		t.Errorf("Len() = %d after Delete, want 0", m.Len())
+	}
+
+	exact := NewFuzzyMap[string](strings.ToLower)
+	exact.Set("Max-Size", "x")
+	if _, ok := exact.Get("max_size"); ok {
+		t.Error("custom normalizer ignored")
+	}
+	if v, ok := exact.Get("MAX-SIZE"); !ok || v != "x" {
+		t.Errorf(`Get("MAX-SIZE") = %q, %t, want "x", true`, v, ok)
+	}
+}
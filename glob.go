@@ -0,0 +1,197 @@
+package gobag
+
+import (
+	"container/list"
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// globCacheSize is the number of compiled glob patterns kept by
+// CompileGlob.
+const globCacheSize = 256
+
+var globCache = newLRU[string, *regexp.Regexp](globCacheSize)
+
+// CompileGlob compiles a glob pattern into an anchored regular
+// expression. The pattern syntax is:
+//
+//	pattern  matches
+//	*        any sequence of characters except '/'
+//	**       any sequence of characters, including '/'
+//	?        any single character except '/'
+//	[abc]    any character in the class; ranges such as [a-z] and
+//	         negation with [!abc] or [^abc] are supported
+//	{a,b}    any of the comma separated alternatives, which may
+//	         themselves contain patterns
+//	\c       the character c literally
+//
+// Compiled patterns are cached, so compiling the same pattern again is
+// cheap. The returned regexp is shared and must not be modified.
+func CompileGlob(pattern string) (*regexp.Regexp, error) {
+	if re, ok := globCache.get(pattern); ok {
+		return re, nil
+	}
+
+	expr, err := globToRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+	re, err := regexp.Compile("^" + expr + "$")
+	if err != nil {
+		return nil, err
+	}
+	globCache.put(pattern, re)
+
+	return re, nil
+}
+
+// MatchGlob reports whether s matches the glob pattern. See
+// CompileGlob for the pattern syntax.
+func MatchGlob(pattern, s string) (bool, error) {
+	re, err := CompileGlob(pattern)
+	if err != nil {
+		return false, err
+	}
+	return re.MatchString(s), nil
+}
+
+// MatchAnyGlob reports whether s matches any of the glob patterns.
+// Returns an error if a pattern checked before a match is invalid.
+func MatchAnyGlob(patterns []string, s string) (bool, error) {
+	for _, pattern := range patterns {
+		ok, err := MatchGlob(pattern, s)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+func globToRegexp(pattern string) (string, error) {
+	var sb strings.Builder
+	runes := []rune(pattern)
+	depth := 0
+
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '*':
+			if i+1 < len(runes) && runes[i+1] == '*' {
+				sb.WriteString(".*")
+				i++
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		case '\\':
+			if i+1 >= len(runes) {
+				return "", errors.New("dangling escape character at end of pattern")
+			}
+			i++
+			sb.WriteString(regexp.QuoteMeta(string(runes[i])))
+		case '[':
+			end := i + 1
+			if end < len(runes) && (runes[end] == '!' || runes[end] == '^') {
+				end++
+			}
+			if end < len(runes) && runes[end] == ']' {
+				end++
+			}
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end >= len(runes) {
+				return "", errors.New("unterminated character class in pattern")
+			}
+			class := runes[i+1 : end]
+			sb.WriteByte('[')
+			if class[0] == '!' || class[0] == '^' {
+				sb.WriteByte('^')
+				class = class[1:]
+			}
+			for _, c := range class {
+				if c == '\\' || c == '[' || c == ']' || c == '^' {
+					sb.WriteByte('\\')
+				}
+				sb.WriteRune(c)
+			}
+			sb.WriteByte(']')
+			i = end
+		case '{':
+			depth++
+			sb.WriteString("(?:")
+		case ',':
+			if depth > 0 {
+				sb.WriteByte('|')
+			} else {
+				sb.WriteByte(',')
+			}
+		case '}':
+			if depth == 0 {
+				return "", errors.New("unbalanced braces in pattern")
+			}
+			depth--
+			sb.WriteByte(')')
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	if depth != 0 {
+		return "", errors.New("unbalanced braces in pattern")
+	}
+
+	return sb.String(), nil
+}
+
+// lru is a fixed size, concurrency safe cache evicting the least
+// recently used entry.
+type lru[K comparable, V any] struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[K]*list.Element
+}
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+func newLRU[K comparable, V any](size int) *lru[K, V] {
+	return &lru[K, V]{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[K]*list.Element, size),
+	}
+}
+
+func (c *lru[K, V]) get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		c.order.MoveToFront(e)
+		return e.Value.(*lruEntry[K, V]).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+func (c *lru[K, V]) put(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*lruEntry[K, V]).value = value
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry[K, V]).key)
+	}
+}
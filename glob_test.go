@@ -0,0 +1,74 @@
+package gobag
+
+import "testing"
+
+func TestCompileGlob(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		s        string
+		expected bool
+	}{
+		{"*.example.com", "www.example.com", true},
+		{"*.example.com", "example.com", false},
+		{"/var/*/log", "/var/app/log", true},
+		{"/var/*/log", "/var/a/b/log", false},
+		{"/var/**/log", "/var/a/b/log", true},
+		{"file?.txt", "file1.txt", true},
+		{"file?.txt", "file10.txt", false},
+		{"[a-c]x", "bx", true},
+		{"[!a-c]x", "bx", false},
+		{"[]]", "]", true},
+		{"{web,db}-[0-9]*", "db-01", true},
+		{"{web,db}-[0-9]*", "cache-01", false},
+		{"{a,{b,c}d}", "cd", true},
+		{`a\*b`, "a*b", true},
+		{`a\*b`, "axb", false},
+		{"a+b(c)", "a+b(c)", true},
+		{"a,b", "a,b", true},
+	}
+	for _, tt := range tests {
+		got, err := MatchGlob(tt.pattern, tt.s)
+		if err != nil || got != tt.expected {
+			t.Errorf("MatchGlob(%q, %q) = %t, %v, want %t", tt.pattern, tt.s, got, err, tt.expected)
+		}
+	}
+
+	for _, bad := range []string{"[abc", "{a,b", "a}", `a\`} {
+		if _, err := CompileGlob(bad); err == nil {
+			t.Errorf("CompileGlob(%q): expected error", bad)
+		}
+	}
+
+	a, _ := CompileGlob("cached*")
+	b, _ := CompileGlob("cached*")
+	if a != b {
+		t.Error("CompileGlob() did not return the cached regexp")
+	}
+}
+
+func TestMatchAnyGlob(t *testing.T) {
+	patterns := []string{"*.log", "core.*"}
+	if ok, err := MatchAnyGlob(patterns, "core.123"); !ok || err != nil {
+		t.Errorf("MatchAnyGlob(core.123) = %t, %v, want true", ok, err)
+	}
+	if ok, err := MatchAnyGlob(patterns, "app.txt"); ok || err != nil {
+		t.Errorf("MatchAnyGlob(app.txt) = %t, %v, want false", ok, err)
+	}
+	if _, err := MatchAnyGlob([]string{"[bad"}, "x"); err == nil {
+		t.Error("MatchAnyGlob() with invalid pattern: expected error")
+	}
+}
+
+func TestLRU(t *testing.T) {
+	c := newLRU[string, int](2)
+	c.put("a", 1)
+	c.put("b", 2)
+	c.get("a")
+	c.put("c", 3)
+	if _, ok := c.get("b"); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+	if v, ok := c.get("a"); !ok || v != 1 {
+		t.Errorf(`get("a") = %d, %t, want 1, true`, v, ok)
+	}
+}
@@ -4,76 +4,222 @@ package gobag
 
 import (
 	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // Fields splits a string by the given separator rune, respecting
 // balanced parentheses, quoted substrings, and escape sequences.
 // Returns an error if quotes or parentheses are unbalanced.
 func Fields(s string, sep rune) ([]string, error) {
+	return FieldsWith(s, sep, FieldsOptions{})
+}
+
+// FieldsOptions holds optional settings for FieldsWith.
+type FieldsOptions struct {
+	// Trace, if not nil, is called for every tokenizer event.
+	Trace TraceFunc
+
+	// InvalidUTF8 selects how bytes that are not valid UTF-8 are
+	// handled. By default they are replaced by U+FFFD.
+	InvalidUTF8 UTF8Policy
+}
+
+// UTF8Policy selects how FieldsWith handles invalid UTF-8.
+type UTF8Policy int
+
+// Policies for invalid UTF-8.
+const (
+	// UTF8Replace replaces each invalid byte by U+FFFD.
+	UTF8Replace UTF8Policy = iota
+	// UTF8Error fails with an error holding the offset of the first
+	// invalid byte.
+	UTF8Error
+	// UTF8Keep passes invalid bytes through untouched.
+	UTF8Keep
+)
+
+// FieldsWith is like Fields, but takes options altering its behavior.
+func FieldsWith(s string, sep rune, opts FieldsOptions) ([]string, error) {
+	// The number of separators bounds the number of fields, and is
+	// exact unless some are quoted, escaped or within parentheses.
+	fields := make([]string, 0, strings.Count(s, string(sep))+1)
+	err := splitFields(s, sep, opts, func(f string) {
+		fields = append(fields, f)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// FieldsCount returns the number of fields Fields would return for s,
+// without building them. Returns the same errors as Fields.
+func FieldsCount(s string, sep rune) (int, error) {
+	n := 0
+	err := splitFields(s, sep, FieldsOptions{}, func(string) {
+		n++
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// FieldsKeepSep splits s at separators like Fields, but keeps each
+// field verbatim together with the separator ending it, like
+// strings.SplitAfter. Quotes and escape characters are not removed, so
+// concatenating the fields gives back s. No empty field follows a
+// trailing separator. Returns the same errors as Fields.
+func FieldsKeepSep(s string, sep rune) ([]string, error) {
+	var state quoteState
+	fields := make([]string, 0, strings.Count(s, string(sep))+1)
+	start := 0
+	for i, r := range s {
+		if state.next(r) && r == sep {
+			end := i + utf8.RuneLen(r)
+			fields = append(fields, s[start:end])
+			start = end
+		}
+	}
+	if err := state.err(); err != nil {
+		return nil, err
+	}
+
+	if start < len(s) {
+		fields = append(fields, s[start:])
+	}
+	return fields, nil
+}
+
+// splitFields implements FieldsWith, calling emit for every field.
+func splitFields(s string, sep rune, opts FieldsOptions, emit func(string)) error {
+	// Fields are substrings of s, unless escapes or replaced bytes
+	// force a copy. The copied part of the current field is held in sb
+	// and the rest is s[start:i].
 	var sb strings.Builder
-	fields := make([]string, 0)
+	start := 0
+	field := func(end int) string {
+		if sb.Len() == 0 {
+			return s[start:end]
+		}
+		sb.WriteString(s[start:end])
+		return sb.String()
+	}
+
 	var balance int
-	var inSingle, inDouble, isEscaped bool
+	var inSingle, inDouble, isEscaped, started bool
+	trace := opts.Trace
+
+	for i, size := 0, 0; i < len(s); i += size {
+		// Decode by hand, as ASCII needs no decoding at all.
+		r := rune(s[i])
+		size = 1
+		if r >= utf8.RuneSelf {
+			r, size = utf8.DecodeRuneInString(s[i:])
+		}
+
+		if trace != nil && !started {
+			trace(TraceEvent{Kind: TraceFieldStart, Offset: i, Rune: r, Depth: balance})
+		}
+		started = true
+
+		if r == utf8.RuneError && size == 1 {
+			switch opts.InvalidUTF8 {
+			case UTF8Error:
+				return fmt.Errorf("invalid UTF-8 at offset %d", i)
+			case UTF8Replace:
+				sb.WriteString(s[start:i])
+				sb.WriteRune(r)
+				start = i + 1
+			}
+			isEscaped = false
+			continue
+		}
 
-	for _, r := range s {
 		if isEscaped {
-			sb.WriteRune(r)
 			isEscaped = false
 			continue
 		}
 
 		switch r {
 		case '\\':
+			if trace != nil {
+				trace(TraceEvent{Kind: TraceEscape, Offset: i, Rune: r, Depth: balance})
+			}
+			sb.WriteString(s[start:i])
+			start = i + size
 			isEscaped = true
-			continue
 		case sep:
 			if balance == 0 && !inSingle && !inDouble {
-				fields = append(fields, sb.String())
+				f := field(i)
+				if trace != nil {
+					trace(TraceEvent{Kind: TraceFieldEnd, Offset: i, Rune: r, Field: f})
+				}
+				emit(f)
 				sb.Reset()
-				continue
+				start = i + size
+				started = false
 			}
 		case '"':
 			if !inSingle {
 				inDouble = !inDouble
+				if trace != nil {
+					trace(TraceEvent{Kind: quoteEvent(inDouble), Offset: i, Rune: r, Depth: balance})
+				}
 			}
 		case '\'':
 			if !inDouble {
 				inSingle = !inSingle
+				if trace != nil {
+					trace(TraceEvent{Kind: quoteEvent(inSingle), Offset: i, Rune: r, Depth: balance})
+				}
 			}
 		case '(':
 			if !inSingle && !inDouble {
 				balance++
+				if trace != nil {
+					trace(TraceEvent{Kind: TraceGroupEnter, Offset: i, Rune: r, Depth: balance})
+				}
 			}
 		case ')':
 			if !inSingle && !inDouble {
 				balance--
+				if trace != nil {
+					trace(TraceEvent{Kind: TraceGroupExit, Offset: i, Rune: r, Depth: balance})
+				}
 			}
 		}
-		sb.WriteRune(r)
 	}
 
 	if isEscaped {
-		return nil, errors.New("dangling escape character at end of string")
+		return errors.New("dangling escape character at end of string")
 	}
 	if balance < 0 {
-		return nil, errors.New("too many closing parentheses")
+		return errors.New("too many closing parentheses")
 	}
 	if balance != 0 {
-		return nil, errors.New("unbalanced parentheses in string")
+		return errors.New("unbalanced parentheses in string")
 	}
 	if inSingle {
-		return nil, errors.New("unbalanced single quote in string")
+		return errors.New("unbalanced single quote in string")
 	}
 	if inDouble {
-		return nil, errors.New("unbalanced double quote in string")
+		return errors.New("unbalanced double quote in string")
 	}
 
-	if sb.Len() > 0 {
-		fields = append(fields, sb.String())
+	if f := field(len(s)); f != "" {
+		if trace != nil {
+			trace(TraceEvent{Kind: TraceFieldEnd, Offset: len(s), Field: f})
+		}
+		emit(f)
 	}
 
-	return fields, nil
+	return nil
 }
 
 // UnquoteStrings unquote double quote strings in a string slice.
@@ -92,11 +238,44 @@ func UnquoteStrings(elems []string) ([]string, error) {
 
 // UnquoteString unquotes double quotes in a string.
 func UnquoteString(s string) (string, error) {
+	return UnquoteStringWith(s, UnquoteOptions{})
+}
+
+// UnquoteOptions holds optional settings for UnquoteStringWith.
+type UnquoteOptions struct {
+	// Strict requires the whole string to be a single quoted token,
+	// rejecting empty strings and any text outside the quotes.
+	Strict bool
+
+	// SingleQuote also unquotes single quoted strings. As in the
+	// shell, their content is literal and escapes are not processed.
+	SingleQuote bool
+
+	// Backtick also unquotes backtick quoted strings. As Go raw
+	// string literals, their content is literal.
+	Backtick bool
+
+	// DecodeEscapes decodes the escape sequences of Go string literals
+	// within double quotes, such as \n, \t, \xNN and \uXXXX, like
+	// strconv.Unquote does. By default only \" and \\ are decoded, and
+	// other sequences are kept as is.
+	DecodeEscapes bool
+}
+
+// UnquoteStringWith is like UnquoteString, but takes options altering
+// its behavior.
+func UnquoteStringWith(s string, opts UnquoteOptions) (string, error) {
 	var sb strings.Builder
 
-	inQuote := false
+	var quote rune
 	escape := false
-	for _, r := range s {
+	quotes := 0
+	skip := 0
+	for i, r := range s {
+		if i < skip {
+			continue
+		}
+
 		switch {
 		case escape:
 			switch r {
@@ -107,14 +286,45 @@ func UnquoteString(s string) (string, error) {
 				sb.WriteRune(r)
 			}
 			escape = false
+		case quote != 0 && quote != '"':
+			if r == quote {
+				quote = 0
+			} else {
+				sb.WriteRune(r)
+			}
 		case r == '\\':
-			if !inQuote {
+			if quote == 0 {
 				return "", errors.New("escape character found outside a quote")
 			}
+			if opts.DecodeEscapes {
+				value, multibyte, tail, err := strconv.UnquoteChar(s[i:], '"')
+				if err != nil {
+					if i+1 == len(s) {
+						return "", errors.New("dangling escape character at end of string")
+					}
+					return "", fmt.Errorf("invalid escape sequence at offset %d", i)
+				}
+				if multibyte {
+					sb.WriteRune(value)
+				} else {
+					sb.WriteByte(byte(value))
+				}
+				skip = len(s) - len(tail)
+				continue
+			}
 			escape = true
-		case r == '"':
-			inQuote = !inQuote
+		case quote == '"' && r == '"':
+			quote = 0
+		case quote == 0 && (r == '"' || (r == '\'' && opts.SingleQuote) || (r == '`' && opts.Backtick)):
+			if opts.Strict && quotes > 0 {
+				return "", errors.New("unexpected text after closing quote")
+			}
+			quote = r
+			quotes++
 		default:
+			if opts.Strict && quote == 0 {
+				return "", errors.New("unquoted text outside a quote")
+			}
 			sb.WriteRune(r)
 		}
 	}
@@ -122,12 +332,37 @@ func UnquoteString(s string) (string, error) {
 	if escape {
 		return "", errors.New("dangling escape character at end of string")
 	}
-	if inQuote {
+	switch quote {
+	case '"':
 		return "", errors.New("unterminated double quote")
+	case '\'':
+		return "", errors.New("unterminated single quote")
+	case '`':
+		return "", errors.New("unterminated backtick quote")
+	}
+	if opts.Strict && quotes == 0 {
+		return "", errors.New("missing quoted string")
 	}
 	return sb.String(), nil
 }
 
+// QuoteString returns s enclosed in double quotes, with double quotes
+// and backslashes escaped. It is the inverse of UnquoteString.
+func QuoteString(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s) + 2)
+	sb.WriteByte('"')
+	for _, r := range s {
+		if r == '"' || r == '\\' {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	sb.WriteByte('"')
+
+	return sb.String()
+}
+
 // Ternary returns v1 if the condition is true, otherwise it returns v2.
 func Ternary[T any](cond bool, v1, v2 T) T {
 	if cond {
@@ -155,6 +390,82 @@ func Deduplicate[T comparable](s []T) []T {
 	return result
 }
 
+// DeduplicateHash is like Deduplicate, but for elements that are not
+// comparable, such as slices, maps or structs holding them. Elements
+// are grouped by hash and compared with eq within a group, so hash
+// collisions do not cause distinct elements to be dropped.
+func DeduplicateHash[T any](s []T, hash func(T) uint64, eq func(a, b T) bool) []T {
+	if len(s) == 0 {
+		return []T{}
+	}
+
+	seen := make(map[uint64][]int, len(s)) // Indices into result.
+	result := make([]T, 0, len(s))
+	for _, e := range s {
+		h := hash(e)
+		if slices.ContainsFunc(seen[h], func(i int) bool { return eq(result[i], e) }) {
+			continue
+		}
+		seen[h] = append(seen[h], len(result))
+		result = append(result, e)
+	}
+
+	return result
+}
+
+// Subtract returns the elements of a with one occurrence removed for
+// every occurrence of the element in b, treating the slices as
+// multisets. Subtract([]T{x, x, y}, []T{x}) is []T{x, y}. The order of
+// the remaining elements is preserved, and the last occurrences are
+// the ones kept.
+func Subtract[T comparable](a, b []T) []T {
+	remove := make(map[T]int, len(b))
+	for _, e := range b {
+		remove[e]++
+	}
+
+	result := make([]T, 0, len(a))
+	for _, e := range a {
+		if remove[e] > 0 {
+			remove[e]--
+			continue
+		}
+		result = append(result, e)
+	}
+
+	return result
+}
+
+// ToPtrs returns a slice of pointers to copies of the elements of s,
+// as used by generated API clients for optional values. The copies
+// share a single allocation, and writing through the pointers does not
+// modify s.
+func ToPtrs[T any](s []T) []*T {
+	values := slices.Clone(s)
+	ptrs := make([]*T, len(values))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	return ptrs
+}
+
+// FromPtrs returns a slice of the values pointed to by the elements of
+// s. Nil pointers are skipped if skipNil is true, and yield the zero
+// value otherwise.
+func FromPtrs[T any](s []*T, skipNil bool) []T {
+	values := make([]T, 0, len(s))
+	for _, p := range s {
+		switch {
+		case p != nil:
+			values = append(values, *p)
+		case !skipNil:
+			var zero T
+			values = append(values, zero)
+		}
+	}
+	return values
+}
+
 // In reports whether the given element is present in the provided slice, using equality comparison.
 func In[T comparable](s []T, e T) bool {
 	for _, element := range s {
@@ -174,3 +485,28 @@ func Keys[K comparable, V any](m map[K]V) []K {
 	}
 	return keys
 }
+
+// KeysSeq returns an iterator over the keys of the given map. The
+// order of keys is not guaranteed. Unlike Keys it does not allocate a
+// slice, which suits callers ranging over the keys once.
+func KeysSeq[K comparable, V any](m map[K]V) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range m {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// ValuesSeq returns an iterator over the values of the given map. The
+// order of values is not guaranteed.
+func ValuesSeq[K comparable, V any](m map[K]V) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range m {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package gobag
+
+import (
+	"bufio"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Benchmarks of the core helpers. Sub-benchmarks are named by input
+// so results of two runs can be compared with benchstat:
+//
+//	go test -run '^$' -bench . -count 10 > old.txt
+//	go test -run '^$' -bench . -count 10 > new.txt
+//	benchstat old.txt new.txt
+//
+// Baseline on an Intel Xeon (amd64), with the single pass splitting
+// of Fields:
+//
+//	BenchmarkFields/short             95.69 ns/op    48 B/op     1 allocs/op
+//	BenchmarkFields/quoted            368.9 ns/op   128 B/op     1 allocs/op
+//	BenchmarkFields/long               6130 ns/op  1792 B/op     1 allocs/op
+//	BenchmarkFields/utf8              313.7 ns/op    80 B/op     1 allocs/op
+//	BenchmarkFields/escaped           443.6 ns/op   104 B/op     5 allocs/op
+//	BenchmarkUnquoteString/plain      54.03 ns/op     8 B/op     1 allocs/op
+//	BenchmarkUnquoteString/quoted     143.2 ns/op    24 B/op     2 allocs/op
+//	BenchmarkUnquoteString/escaped    387.4 ns/op    56 B/op     3 allocs/op
+//	BenchmarkDeduplicate/n=10         529.9 ns/op   408 B/op     4 allocs/op
+//	BenchmarkDeduplicate/n=1000       24365 ns/op 45136 B/op     6 allocs/op
+//	BenchmarkKeys/n=10                544.9 ns/op   160 B/op     1 allocs/op
+//	BenchmarkKeys/n=1000              32508 ns/op 16384 B/op     1 allocs/op
+
+var benchLines = map[string]string{
+	"short":   `a,b,c`,
+	"quoted":  `name="John, Doe",age=42,tags=(a,b,c),note='it, is'`,
+	"long":    strings.Repeat(`key=value,"quoted, field",(nested,(parens)),`, 20),
+	"utf8":    `navn="Ærlig Åse",by=Tromsø,hilsen=(hei,på,deg)`,
+	"escaped": `path=C:\\temp\\out,msg=a\,b,say=\"hi\"`,
+}
+
+var benchNames = []string{"short", "quoted", "long", "utf8", "escaped"}
+
+func BenchmarkFields(b *testing.B) {
+	for _, name := range benchNames {
+		s := benchLines[name]
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(s)))
+			for b.Loop() {
+				if _, err := Fields(s, ','); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkFieldsCount(b *testing.B) {
+	for _, name := range benchNames {
+		s := benchLines[name]
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(s)))
+			for b.Loop() {
+				if _, err := FieldsCount(s, ','); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkFieldsLazy(b *testing.B) {
+	for _, name := range benchNames {
+		s := benchLines[name]
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(s)))
+			for b.Loop() {
+				if _, err := FieldsLazy(s, ','); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkUnquoteString(b *testing.B) {
+	inputs := map[string]string{
+		"plain":   `hello`,
+		"quoted":  `"hello, world"`,
+		"escaped": `"say \"hi\" to \\ everyone"`,
+	}
+	for _, name := range []string{"plain", "quoted", "escaped"} {
+		s := inputs[name]
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := UnquoteString(s); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDeduplicate(b *testing.B) {
+	for _, n := range []int{10, 1000} {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = i % (n / 2)
+		}
+		b.Run("n="+strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				Deduplicate(s)
+			}
+		})
+	}
+}
+
+func BenchmarkKeys(b *testing.B) {
+	for _, n := range []int{10, 1000} {
+		m := make(map[string]int, n)
+		for i := range n {
+			m[strconv.Itoa(i)] = i
+		}
+		b.Run("n="+strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				Keys(m)
+			}
+		})
+	}
+}
+
+func BenchmarkKeysSeq(b *testing.B) {
+	for _, n := range []int{10, 1000} {
+		m := make(map[string]int, n)
+		for i := range n {
+			m[strconv.Itoa(i)] = i
+		}
+		b.Run("n="+strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				for range KeysSeq(m) {
+				}
+			}
+		})
+	}
+}
+
+// TestAllocs guards the allocation counts of the hot paths against
+// regressions. Lower counts after an optimization should lower the
+// limits here.
+func TestAllocs(t *testing.T) {
+	if testing.CoverMode() != "" {
+		t.Skip("allocation counts differ with coverage enabled")
+	}
+
+	m := map[string]int{"a": 1, "b": 2, "c": 3}
+	dups := []int{1, 2, 1, 3, 2}
+	tests := []struct {
+		name string
+		max  float64
+		fn   func()
+	}{
+		{"Fields short", 1, func() { Fields(benchLines["short"], ',') }},
+		{"Fields quoted", 1, func() { Fields(benchLines["quoted"], ',') }},
+		{"Fields escaped", 5, func() { Fields(benchLines["escaped"], ',') }},
+		{"FieldsCount", 0, func() { FieldsCount(benchLines["quoted"], ',') }},
+		{"UnquoteString plain", 1, func() { UnquoteString("hello") }},
+		{"UnquoteString quoted", 2, func() { UnquoteString(`"hello, world"`) }},
+		{"Deduplicate", 2, func() { Deduplicate(dups) }},
+		{"Keys", 1, func() { Keys(m) }},
+		{"KeysSeq", 0, func() {
+			for range KeysSeq(m) {
+			}
+		}},
+	}
+	for _, tt := range tests {
+		if got := testing.AllocsPerRun(100, tt.fn); got > tt.max {
+			t.Errorf("%s: %v allocations, want at most %v", tt.name, got, tt.max)
+		}
+	}
+}
+
+func BenchmarkSplitRecords(b *testing.B) {
+	for _, n := range []int{1000, 10000} {
+		s := strings.Repeat("a,\"b\nc\",(d,e)\n", n)
+		b.Run("n="+strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				SplitRecords(s)
+			}
+		})
+	}
+}
+
+func BenchmarkScanRecords(b *testing.B) {
+	// A single record spanning many reads of the scanner.
+	s := "\"" + strings.Repeat("multi line value\n", 10000) + "\"\n"
+	splits := map[string]func() bufio.SplitFunc{
+		"stateless": func() bufio.SplitFunc { return ScanRecords },
+		"stateful":  NewRecordSplitFunc,
+	}
+	for _, name := range []string{"stateless", "stateful"} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				scanner := bufio.NewScanner(strings.NewReader(s))
+				scanner.Buffer(nil, 1<<20)
+				scanner.Split(splits[name]())
+				for scanner.Scan() {
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParseEnvMultiline(b *testing.B) {
+	// A quoted value spanning many lines, and a long continued line.
+	for _, input := range []struct{ name, s string }{
+		{"quoted", "KEY=\"" + strings.Repeat("line of text\n", 10000) + "\"\n"},
+		{"continued", "KEY=" + strings.Repeat("word \\\n", 10000) + "end\n"},
+	} {
+		b.Run(input.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := ParseEnv(strings.NewReader(input.s)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -2,8 +2,13 @@ package gobag
 
 import (
 	"errors"
+	"fmt"
+	"hash/fnv"
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
+	"unicode/utf8"
 )
 
 func TestFields(t *testing.T) {
@@ -206,3 +211,436 @@ func TestDeduplicate(t *testing.T) {
 		}
 	}
 }
+
+func TestDeduplicateHash(t *testing.T) {
+	hash := func(s []int) uint64 {
+		h := fnv.New64a()
+		for _, n := range s {
+			fmt.Fprint(h, n, ",")
+		}
+		return h.Sum64()
+	}
+	collide := func([]int) uint64 { return 42 }
+
+	tests := []struct {
+		input    [][]int
+		expected [][]int
+	}{
+		{[][]int{{1, 2}, {3}, {1, 2}, {}, {3}, nil}, [][]int{{1, 2}, {3}, {}}},
+		{[][]int{{1}}, [][]int{{1}}},
+		{nil, [][]int{}},
+	}
+	for _, tt := range tests {
+		for _, h := range []func([]int) uint64{hash, collide} {
+			result := DeduplicateHash(tt.input, h, slices.Equal)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("DeduplicateHash(%v) = %v; want %v", tt.input, result, tt.expected)
+			}
+		}
+	}
+}
+
+func TestSubtract(t *testing.T) {
+	tests := []struct {
+		a, b     []string
+		expected []string
+	}{
+		{[]string{"x", "x", "y"}, []string{"x"}, []string{"x", "y"}},
+		{[]string{"a", "b", "a", "c", "a"}, []string{"a", "a", "c"}, []string{"b", "a"}},
+		{[]string{"a"}, []string{"a", "a", "b"}, []string{}},
+		{[]string{"a", "b"}, nil, []string{"a", "b"}},
+		{nil, []string{"a"}, []string{}},
+	}
+	for _, tt := range tests {
+		result := Subtract(tt.a, tt.b)
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("Subtract(%q, %q) = %q; want %q", tt.a, tt.b, result, tt.expected)
+		}
+	}
+}
+
+func TestToPtrs(t *testing.T) {
+	s := []int{1, 2, 3}
+	ptrs := ToPtrs(s)
+	if len(ptrs) != 3 || *ptrs[0] != 1 || *ptrs[1] != 2 || *ptrs[2] != 3 {
+		t.Fatalf("ToPtrs(%v) = %v", s, ptrs)
+	}
+	*ptrs[0] = 10
+	if s[0] != 1 {
+		t.Errorf("writing through ToPtrs() pointer modified s: %v", s)
+	}
+	if ptrs := ToPtrs[int](nil); len(ptrs) != 0 {
+		t.Errorf("ToPtrs(nil) = %v; want empty", ptrs)
+	}
+}
+
+func TestFromPtrs(t *testing.T) {
+	a, b := "a", "b"
+	tests := []struct {
+		s        []*string
+		skipNil  bool
+		expected []string
+	}{
+		{[]*string{&a, nil, &b}, false, []string{"a", "", "b"}},
+		{[]*string{&a, nil, &b}, true, []string{"a", "b"}},
+		{[]*string{nil}, true, []string{}},
+		{nil, false, []string{}},
+	}
+	for _, tt := range tests {
+		result := FromPtrs(tt.s, tt.skipNil)
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("FromPtrs(%v, %v) = %q; want %q", tt.s, tt.skipNil, result, tt.expected)
+		}
+	}
+
+	if result := FromPtrs(ToPtrs([]string{"x", "y"}), false); !reflect.DeepEqual(result, []string{"x", "y"}) {
+		t.Errorf("FromPtrs(ToPtrs()) = %q; want [x y]", result)
+	}
+}
+
+func TestUnquoteStringStrict(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		err      error
+	}{
+		{`"foo bar"`, "foo bar", nil},
+		{`"foo\"bar"`, `foo"bar`, nil},
+		{`""`, "", nil},
+		{``, "", errors.New("missing quoted string")},
+		{`foo`, "", errors.New("unquoted text outside a quote")},
+		{`"foo",bar`, "", errors.New("unquoted text outside a quote")},
+		{`"foo ","bar"`, "", errors.New("unquoted text outside a quote")},
+		{`"foo""bar"`, "", errors.New("unexpected text after closing quote")},
+		{`"foo`, "", errors.New("unterminated double quote")},
+	}
+	for _, tt := range tests {
+		got, err := UnquoteStringWith(tt.input, UnquoteOptions{Strict: true})
+		if tt.err != nil {
+			if err == nil || err.Error() != tt.err.Error() {
+				t.Errorf("UnquoteStringWith(%q) error = %v, want %v", tt.input, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.expected {
+			t.Errorf("UnquoteStringWith(%q) = %q, %v, want %q, nil", tt.input, got, err, tt.expected)
+		}
+	}
+}
+
+func TestUnquoteStringQuoteStyles(t *testing.T) {
+	opts := UnquoteOptions{SingleQuote: true, Backtick: true}
+	tests := []struct {
+		input    string
+		expected string
+		err      error
+	}{
+		{`'foo bar'`, "foo bar", nil},
+		{`'foo\bar'`, `foo\bar`, nil},
+		{`'say "hi"'`, `say "hi"`, nil},
+		{"`foo\\n`", `foo\n`, nil},
+		{"`it's`", "it's", nil},
+		{`"a",'b',` + "`c`", "a,b,c", nil},
+		{`"it's"`, "it's", nil},
+		{`'foo`, "", errors.New("unterminated single quote")},
+		{"`foo", "", errors.New("unterminated backtick quote")},
+	}
+	for _, tt := range tests {
+		got, err := UnquoteStringWith(tt.input, opts)
+		if tt.err != nil {
+			if err == nil || err.Error() != tt.err.Error() {
+				t.Errorf("UnquoteStringWith(%q) error = %v, want %v", tt.input, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.expected {
+			t.Errorf("UnquoteStringWith(%q) = %q, %v, want %q, nil", tt.input, got, err, tt.expected)
+		}
+	}
+
+	if got, _ := UnquoteString(`'foo'`); got != `'foo'` {
+		t.Errorf("UnquoteString(%q) = %q, want single quotes kept by default", `'foo'`, got)
+	}
+}
+
+func TestUnquoteStringDecodeEscapes(t *testing.T) {
+	opts := UnquoteOptions{DecodeEscapes: true}
+	tests := []struct {
+		input    string
+		expected string
+		err      error
+	}{
+		{`"a\nb"`, "a\nb", nil},
+		{`"\t\r\\\""`, "\t\r\\\"", nil},
+		{`"\x41å\U0001F600"`, "Aå😀", nil},
+		{`"\xff"`, "\xff", nil},
+		{`"foo","b\tr"`, "foo,b\tr", nil},
+		{`"\q"`, "", errors.New("invalid escape sequence at offset 1")},
+		{`"\u12"`, "", errors.New("invalid escape sequence at offset 1")},
+		{`"foo\`, "", errors.New("dangling escape character at end of string")},
+	}
+	for _, tt := range tests {
+		got, err := UnquoteStringWith(tt.input, opts)
+		if tt.err != nil {
+			if err == nil || err.Error() != tt.err.Error() {
+				t.Errorf("UnquoteStringWith(%q) error = %v, want %v", tt.input, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.expected {
+			t.Errorf("UnquoteStringWith(%q) = %q, %v, want %q, nil", tt.input, got, err, tt.expected)
+		}
+	}
+}
+
+func TestFieldsInvalidUTF8(t *testing.T) {
+	input := "a\xff,b\\\xfe,\"c\xfd\""
+	tests := []struct {
+		policy   UTF8Policy
+		expected []string
+		err      error
+	}{
+		{UTF8Replace, []string{"a�", "b�", "\"c�\""}, nil},
+		{UTF8Keep, []string{"a\xff", "b\xfe", "\"c\xfd\""}, nil},
+		{UTF8Error, nil, errors.New("invalid UTF-8 at offset 1")},
+	}
+	for _, tt := range tests {
+		got, err := FieldsWith(input, ',', FieldsOptions{InvalidUTF8: tt.policy})
+		if tt.err != nil {
+			if err == nil || err.Error() != tt.err.Error() {
+				t.Errorf("FieldsWith(%d) error = %v, want %v", tt.policy, err, tt.err)
+			}
+		} else if err != nil {
+			t.Errorf("FieldsWith(%d) error = %v, want nil", tt.policy, err)
+		}
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("FieldsWith(%d) = %q, want %q", tt.policy, got, tt.expected)
+		}
+	}
+
+	if got, err := FieldsWith("a,�", ',', FieldsOptions{InvalidUTF8: UTF8Error}); err != nil || got[1] != "�" {
+		t.Errorf("FieldsWith() with literal U+FFFD = %q, %v", got, err)
+	}
+}
+
+func TestFieldsKeepSep(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+		err      error
+	}{
+		{"", []string{}, nil},
+		{"a,b,c", []string{"a,", "b,", "c"}, nil},
+		{"a,,b,", []string{"a,", ",", "b,"}, nil},
+		{`x="a, b", y=(1,2),z\,w`, []string{`x="a, b",`, ` y=(1,2),`, `z\,w`}, nil},
+		{"a;b", []string{"a;b"}, nil},
+		{`a,"b`, nil, errors.New("unbalanced double quote in string")},
+	}
+	for _, tt := range tests {
+		got, err := FieldsKeepSep(tt.input, ',')
+		if fmt.Sprint(err) != fmt.Sprint(tt.err) {
+			t.Errorf("FieldsKeepSep(%q) error = %v, want %v", tt.input, err, tt.err)
+		}
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("FieldsKeepSep(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+		if err == nil && strings.Join(got, "") != tt.input {
+			t.Errorf("FieldsKeepSep(%q) does not join back to the input: %q", tt.input, got)
+		}
+	}
+
+	if got, _ := FieldsKeepSep("æøå→ø→", '→'); !reflect.DeepEqual(got, []string{"æøå→", "ø→"}) {
+		t.Errorf("FieldsKeepSep() with multibyte separator = %q", got)
+	}
+}
+
+func TestKeysSeqValuesSeq(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2, "c": 3}
+
+	keys := slices.Sorted(KeysSeq(m))
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("KeysSeq() = %q, want %q", keys, want)
+	}
+	values := slices.Sorted(ValuesSeq(m))
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(values, want) {
+		t.Errorf("ValuesSeq() = %v, want %v", values, want)
+	}
+
+	n := 0
+	for range KeysSeq(m) {
+		if n++; n == 2 {
+			break
+		}
+	}
+	if n != 2 {
+		t.Errorf("KeysSeq() yielded %d keys before break, want 2", n)
+	}
+	for range ValuesSeq(map[string]int(nil)) {
+		t.Error("ValuesSeq(nil) yielded a value")
+	}
+}
+
+func TestFieldsCount(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+		err      error
+	}{
+		{"", 0, nil},
+		{"a", 1, nil},
+		{"a,b,c", 3, nil},
+		{"a,,b,", 3, nil},
+		{`"a,b",(c,d),e\,f`, 3, nil},
+		{`a,"b`, 0, errors.New("unbalanced double quote in string")},
+		{`a,(b`, 0, errors.New("unbalanced parentheses in string")},
+	}
+	for _, tt := range tests {
+		got, err := FieldsCount(tt.input, ',')
+		if fmt.Sprint(err) != fmt.Sprint(tt.err) {
+			t.Errorf("FieldsCount(%q) error = %v, want %v", tt.input, err, tt.err)
+		}
+		if got != tt.expected {
+			t.Errorf("FieldsCount(%q) = %d, want %d", tt.input, got, tt.expected)
+		}
+		if fields, err := Fields(tt.input, ','); err == nil && len(fields) != got {
+			t.Errorf("FieldsCount(%q) = %d, but Fields returned %d fields", tt.input, got, len(fields))
+		}
+	}
+}
+
+// fieldsRunes is the straightforward rune by rune implementation of
+// FieldsWith, which the optimized one must behave identically to.
+func fieldsRunes(s string, sep rune, opts FieldsOptions) ([]string, error) {
+	var sb strings.Builder
+	fields := make([]string, 0)
+	var balance int
+	var inSingle, inDouble, isEscaped, started bool
+	trace := opts.Trace
+
+	for i, r := range s {
+		if trace != nil && !started {
+			trace(TraceEvent{Kind: TraceFieldStart, Offset: i, Rune: r, Depth: balance})
+		}
+		started = true
+
+		if r == utf8.RuneError && opts.InvalidUTF8 != UTF8Replace {
+			if _, size := utf8.DecodeRuneInString(s[i:]); size == 1 {
+				if opts.InvalidUTF8 == UTF8Error {
+					return nil, fmt.Errorf("invalid UTF-8 at offset %d", i)
+				}
+				sb.WriteByte(s[i])
+				isEscaped = false
+				continue
+			}
+		}
+
+		if isEscaped {
+			sb.WriteRune(r)
+			isEscaped = false
+			continue
+		}
+
+		switch r {
+		case '\\':
+			if trace != nil {
+				trace(TraceEvent{Kind: TraceEscape, Offset: i, Rune: r, Depth: balance})
+			}
+			isEscaped = true
+			continue
+		case sep:
+			if balance == 0 && !inSingle && !inDouble {
+				if trace != nil {
+					trace(TraceEvent{Kind: TraceFieldEnd, Offset: i, Rune: r, Field: sb.String()})
+				}
+				fields = append(fields, sb.String())
+				sb.Reset()
+				started = false
+				continue
+			}
+		case '"':
+			if !inSingle {
+				inDouble = !inDouble
+				if trace != nil {
+					trace(TraceEvent{Kind: quoteEvent(inDouble), Offset: i, Rune: r, Depth: balance})
+				}
+			}
+		case '\'':
+			if !inDouble {
+				inSingle = !inSingle
+				if trace != nil {
+					trace(TraceEvent{Kind: quoteEvent(inSingle), Offset: i, Rune: r, Depth: balance})
+				}
+			}
+		case '(':
+			if !inSingle && !inDouble {
+				balance++
+				if trace != nil {
+					trace(TraceEvent{Kind: TraceGroupEnter, Offset: i, Rune: r, Depth: balance})
+				}
+			}
+		case ')':
+			if !inSingle && !inDouble {
+				balance--
+				if trace != nil {
+					trace(TraceEvent{Kind: TraceGroupExit, Offset: i, Rune: r, Depth: balance})
+				}
+			}
+		}
+		sb.WriteRune(r)
+	}
+
+	if isEscaped {
+		return nil, errors.New("dangling escape character at end of string")
+	}
+	if balance < 0 {
+		return nil, errors.New("too many closing parentheses")
+	}
+	if balance != 0 {
+		return nil, errors.New("unbalanced parentheses in string")
+	}
+	if inSingle {
+		return nil, errors.New("unbalanced single quote in string")
+	}
+	if inDouble {
+		return nil, errors.New("unbalanced double quote in string")
+	}
+
+	if sb.Len() > 0 {
+		if trace != nil {
+			trace(TraceEvent{Kind: TraceFieldEnd, Offset: len(s), Field: sb.String()})
+		}
+		fields = append(fields, sb.String())
+	}
+
+	return fields, nil
+}
+
+func TestFieldsMatchesReference(t *testing.T) {
+	inputs := []string{
+		"", ",", ",,a,,", "a,b,c", `a\,b,c\`, `\\,\"`, `"a,b",'c,d',(e,f)`,
+		`(a,(b,c)),d)`, `"unterminated,a`, `x='it''s',y="\"q\""`,
+		"Ærlig,Åse,(på,deg)", "a\xff,b\\\xfe,\"c\xfd\"", "\xff", "a,\ufffd,\xe2\x82",
+		"tab\tsep\tfields", "\u00e6\\\u00f8,\\\xff",
+	}
+	for _, s := range inputs {
+		for _, sep := range []rune{',', '\t', 'ø', '\\', '"'} {
+			for _, policy := range []UTF8Policy{UTF8Replace, UTF8Keep, UTF8Error} {
+				var got, want []TraceEvent
+				gotFields, gotErr := FieldsWith(s, sep, FieldsOptions{
+					InvalidUTF8: policy,
+					Trace:       func(e TraceEvent) { got = append(got, e) },
+				})
+				wantFields, wantErr := fieldsRunes(s, sep, FieldsOptions{
+					InvalidUTF8: policy,
+					Trace:       func(e TraceEvent) { want = append(want, e) },
+				})
+				if !reflect.DeepEqual(gotFields, wantFields) || fmt.Sprint(gotErr) != fmt.Sprint(wantErr) {
+					t.Errorf("FieldsWith(%q, %q, %d) = %q, %v, want %q, %v", s, sep, policy, gotFields, gotErr, wantFields, wantErr)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("FieldsWith(%q, %q, %d) traced %v, want %v", s, sep, policy, got, want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package gobagtest
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// EqualSlices reports whether got equals want, and fails the test
+// otherwise, listing each index where they differ with the wanted and
+// actual element.
+func EqualSlices[T comparable](t testing.TB, want, got []T) bool {
+	t.Helper()
+
+	var diffs []string
+	for i := range max(len(want), len(got)) {
+		switch {
+		case i >= len(got):
+			diffs = append(diffs, fmt.Sprintf("[%d]: missing, want %#v", i, want[i]))
+		case i >= len(want):
+			diffs = append(diffs, fmt.Sprintf("[%d]: unexpected %#v", i, got[i]))
+		case got[i] != want[i]:
+			diffs = append(diffs, fmt.Sprintf("[%d]: want %#v, got %#v", i, want[i], got[i]))
+		}
+	}
+	if len(diffs) == 0 {
+		return true
+	}
+
+	t.Errorf("slices differ (len want %d, got %d):\n\t%s", len(want), len(got), strings.Join(diffs, "\n\t"))
+	return false
+}
+
+// EqualMaps reports whether got equals want, and fails the test
+// otherwise, listing each key that is missing, unexpected or has a
+// different value, in order of the formatted keys.
+func EqualMaps[K, V comparable](t testing.TB, want, got map[K]V) bool {
+	t.Helper()
+
+	keys := slices.Collect(maps.Keys(want))
+	for k := range got {
+		if _, ok := want[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.SortFunc(keys, func(a, b K) int {
+		return cmp.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	})
+
+	var diffs []string
+	for _, k := range keys {
+		w, inWant := want[k]
+		g, inGot := got[k]
+		switch {
+		case !inGot:
+			diffs = append(diffs, fmt.Sprintf("[%#v]: missing, want %#v", k, w))
+		case !inWant:
+			diffs = append(diffs, fmt.Sprintf("[%#v]: unexpected %#v", k, g))
+		case g != w:
+			diffs = append(diffs, fmt.Sprintf("[%#v]: want %#v, got %#v", k, w, g))
+		}
+	}
+	if len(diffs) == 0 {
+		return true
+	}
+
+	t.Errorf("maps differ (len want %d, got %d):\n\t%s", len(want), len(got), strings.Join(diffs, "\n\t"))
+	return false
+}
@@ -0,0 +1,48 @@
+package gobagtest
+
+import "testing"
+
+func TestEqualSlices(t *testing.T) {
+	if !EqualSlices(t, []string{"a", "b"}, []string{"a", "b"}) {
+		t.Error("EqualSlices() with equal slices = false")
+	}
+	EqualSlices[int](t, nil, []int{})
+
+	tests := []struct {
+		want, got []string
+		expected  string
+	}{
+		{[]string{"a", "b"}, []string{"a", "x"}, "slices differ (len want 2, got 2):\n\t[1]: want \"b\", got \"x\""},
+		{[]string{"a", "b"}, []string{"a"}, "slices differ (len want 2, got 1):\n\t[1]: missing, want \"b\""},
+		{[]string{"a"}, []string{"x", "c"}, "slices differ (len want 1, got 2):\n\t[0]: want \"a\", got \"x\"\n\t[1]: unexpected \"c\""},
+	}
+	for _, tc := range tests {
+		r := &recorder{TB: t}
+		if EqualSlices(r, tc.want, tc.got) {
+			t.Errorf("EqualSlices(%q, %q) = true", tc.want, tc.got)
+		}
+		if len(r.errors) != 1 || r.errors[0] != tc.expected {
+			t.Errorf("EqualSlices(%q, %q) reported %q, want %q", tc.want, tc.got, r.errors, tc.expected)
+		}
+	}
+}
+
+func TestEqualMaps(t *testing.T) {
+	if !EqualMaps(t, map[string]int{"a": 1}, map[string]int{"a": 1}) {
+		t.Error("EqualMaps() with equal maps = false")
+	}
+
+	r := &recorder{TB: t}
+	want := map[string]int{"a": 1, "b": 2, "c": 3}
+	got := map[string]int{"a": 1, "b": 5, "d": 4}
+	if EqualMaps(r, want, got) {
+		t.Error("EqualMaps() with different maps = true")
+	}
+	expected := "maps differ (len want 3, got 3):\n" +
+		"\t[\"b\"]: want 2, got 5\n" +
+		"\t[\"c\"]: missing, want 3\n" +
+		"\t[\"d\"]: unexpected 4"
+	if len(r.errors) != 1 || r.errors[0] != expected {
+		t.Errorf("EqualMaps() reported %q, want %q", r.errors, expected)
+	}
+}
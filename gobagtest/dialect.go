@@ -0,0 +1,126 @@
+package gobagtest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// FieldsFunc is a function splitting s into fields at sep, like
+// gobag.Fields.
+type FieldsFunc func(s string, sep rune) ([]string, error)
+
+// Dialect describes the features of the gobag.Fields syntax a splitter
+// supports, selecting the cases RunFieldsDialectTests checks.
+type Dialect struct {
+	// Sep is the separator passed to the splitter. The default is ','.
+	Sep rune
+
+	DoubleQuotes bool // "..." groups text, and the quotes are kept.
+	SingleQuotes bool // '...' groups text, and the quotes are kept.
+	Parentheses  bool // (...) groups text, and may be nested.
+	Escapes      bool // A backslash makes the next character literal.
+
+	// Errors makes unbalanced quotes and parentheses and a dangling
+	// escape character errors.
+	Errors bool
+}
+
+// FieldsDialect is the full dialect of gobag.Fields.
+var FieldsDialect = Dialect{
+	Sep:          ',',
+	DoubleQuotes: true,
+	SingleQuotes: true,
+	Parentheses:  true,
+	Escapes:      true,
+	Errors:       true,
+}
+
+// dialectCase is a conformance case written with ',' as separator.
+type dialectCase struct {
+	name     string
+	needs    Dialect
+	input    string
+	expected []string
+	err      bool
+}
+
+var dialectCases = []dialectCase{
+	{"plain", Dialect{}, "a,b,c", []string{"a", "b", "c"}, false},
+	{"empty input", Dialect{}, "", []string{}, false},
+	{"single field", Dialect{}, "abc", []string{"abc"}, false},
+	{"empty fields", Dialect{}, "a,,b", []string{"a", "", "b"}, false},
+	{"leading separator", Dialect{}, ",a", []string{"", "a"}, false},
+	{"trailing separator", Dialect{}, "a,b,", []string{"a", "b"}, false},
+	{"whitespace kept", Dialect{}, " a , b ", []string{" a ", " b "}, false},
+	{"multibyte text", Dialect{}, "æøå,日本", []string{"æøå", "日本"}, false},
+
+	{"double quotes", Dialect{DoubleQuotes: true}, `"a,b",c`, []string{`"a,b"`, "c"}, false},
+	{"double quotes within field", Dialect{DoubleQuotes: true}, `k="a,b",c`, []string{`k="a,b"`, "c"}, false},
+	{"empty double quotes", Dialect{DoubleQuotes: true}, `"",a`, []string{`""`, "a"}, false},
+	{"single quotes", Dialect{SingleQuotes: true}, `'a,b',c`, []string{`'a,b'`, "c"}, false},
+	{"single within double", Dialect{DoubleQuotes: true, SingleQuotes: true}, `"it's,ok",c`, []string{`"it's,ok"`, "c"}, false},
+	{"double within single", Dialect{DoubleQuotes: true, SingleQuotes: true}, `'say "a,b"',c`, []string{`'say "a,b"'`, "c"}, false},
+	{"parentheses", Dialect{Parentheses: true}, "f(a,b),c", []string{"f(a,b)", "c"}, false},
+	{"nested parentheses", Dialect{Parentheses: true}, "((a,b),c),d", []string{"((a,b),c)", "d"}, false},
+	{"quoted parenthesis", Dialect{DoubleQuotes: true, Parentheses: true}, `"(",a`, []string{`"("`, "a"}, false},
+	{"escaped separator", Dialect{Escapes: true}, `a\,b,c`, []string{"a,b", "c"}, false},
+	{"escaped backslash", Dialect{Escapes: true}, `a\\,b`, []string{`a\`, "b"}, false},
+	{"escaped quote", Dialect{DoubleQuotes: true, Escapes: true}, `\"a,b`, []string{`"a`, "b"}, false},
+	{"escape within quotes", Dialect{DoubleQuotes: true, Escapes: true}, `"a\"b,c"`, []string{`"a"b,c"`}, false},
+
+	{"unbalanced double quote", Dialect{DoubleQuotes: true, Errors: true}, `"a,b`, nil, true},
+	{"unbalanced single quote", Dialect{SingleQuotes: true, Errors: true}, `a,'b`, nil, true},
+	{"unclosed parenthesis", Dialect{Parentheses: true, Errors: true}, "(a,b", nil, true},
+	{"extra closing parenthesis", Dialect{Parentheses: true, Errors: true}, "a),b", nil, true},
+	{"dangling escape", Dialect{Escapes: true, Errors: true}, `a,b\`, nil, true},
+}
+
+// supports reports whether d has all the features of needs.
+func (d Dialect) supports(needs Dialect) bool {
+	return (d.DoubleQuotes || !needs.DoubleQuotes) &&
+		(d.SingleQuotes || !needs.SingleQuotes) &&
+		(d.Parentheses || !needs.Parentheses) &&
+		(d.Escapes || !needs.Escapes) &&
+		(d.Errors || !needs.Errors)
+}
+
+// RunFieldsDialectTests checks that split follows the semantics of
+// gobag.Fields for the features of dialect, running each case as a
+// subtest. It lets custom splitters built on the tokenizer verify that
+// they split compatibly.
+func RunFieldsDialectTests(t *testing.T, split FieldsFunc, dialect Dialect) {
+	t.Helper()
+
+	sep := dialect.Sep
+	if sep == 0 {
+		sep = ','
+	}
+	for _, tc := range dialectCases {
+		if !dialect.supports(tc.needs) || strings.ContainsRune(tc.input, sep) && sep != ',' {
+			continue
+		}
+		input := strings.ReplaceAll(tc.input, ",", string(sep))
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := split(input, sep)
+			if tc.err {
+				if err == nil {
+					t.Errorf("split(%q) = %q, want an error", input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("split(%q) error = %v", input, err)
+			}
+			expected := make([]string, len(tc.expected))
+			for i, f := range tc.expected {
+				expected[i] = strings.ReplaceAll(f, ",", string(sep))
+			}
+			if len(got) != 0 || len(expected) != 0 {
+				if !reflect.DeepEqual(got, expected) {
+					t.Errorf("split(%q) = %q, want %q", input, got, expected)
+				}
+			}
+		})
+	}
+}
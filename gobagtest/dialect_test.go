@@ -0,0 +1,47 @@
+package gobagtest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stianwa/gobag"
+)
+
+func TestFieldsDialect(t *testing.T) {
+	RunFieldsDialectTests(t, gobag.Fields, FieldsDialect)
+
+	tabs := FieldsDialect
+	tabs.Sep = '\t'
+	RunFieldsDialectTests(t, gobag.Fields, tabs)
+
+	arrows := FieldsDialect
+	arrows.Sep = '→'
+	RunFieldsDialectTests(t, gobag.Fields, arrows)
+}
+
+func TestFieldsDialectLazy(t *testing.T) {
+	lazy := func(s string, sep rune) ([]string, error) {
+		fields, err := gobag.FieldsLazy(s, sep)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]string, len(fields))
+		for i, f := range fields {
+			values[i] = f.Value()
+		}
+		return values, nil
+	}
+	RunFieldsDialectTests(t, lazy, FieldsDialect)
+}
+
+func TestPlainDialect(t *testing.T) {
+	// A splitter without quoting passes the plain cases only.
+	plain := func(s string, sep rune) ([]string, error) {
+		fields := strings.Split(s, string(sep))
+		if fields[len(fields)-1] == "" {
+			fields = fields[:len(fields)-1]
+		}
+		return fields, nil
+	}
+	RunFieldsDialectTests(t, plain, Dialect{})
+}
@@ -0,0 +1,3 @@
+// Package gobagtest provides helpers for testing code built on gobag,
+// and for testing gobag itself.
+package gobagtest
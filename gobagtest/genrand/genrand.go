@@ -0,0 +1,209 @@
+// Package genrand generates random strings in the syntax of
+// gobag.Fields, with quotes, parentheses and escapes nested to varying
+// depth. The strings are either valid, or invalid in a way Fields must
+// report as an error, and serve property tests using testing/quick as
+// well as fuzz test corpora.
+package genrand
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+)
+
+// Options holds optional settings for StringWith and InvalidStringWith.
+type Options struct {
+	// Sep is the field separator. The default is ','.
+	Sep rune
+
+	// MaxDepth is the maximum nesting of parentheses. The default is 3.
+	MaxDepth int
+
+	// Alphabet holds the plain characters to draw from. The default is
+	// a mix of ASCII letters, whitespace and multibyte characters.
+	Alphabet string
+}
+
+func (o Options) withDefaults() Options {
+	if o.Sep == 0 {
+		o.Sep = ','
+	}
+	if o.MaxDepth <= 0 {
+		o.MaxDepth = 3
+	}
+	if o.Alphabet == "" {
+		o.Alphabet = "abcxyz09 \tæøå日本"
+	}
+	return o
+}
+
+// String returns a random string of about size characters that Fields
+// splits at ',' without error.
+func String(r *rand.Rand, size int) string {
+	return StringWith(r, size, Options{})
+}
+
+// StringWith is like String, but takes options altering its behavior.
+func StringWith(r *rand.Rand, size int, opts Options) string {
+	g := generator{r: r, opts: opts.withDefaults(), budget: size}
+	g.alphabet = []rune(g.opts.Alphabet)
+
+	var sb strings.Builder
+	for g.budget > 0 {
+		g.item(&sb, 0)
+	}
+	return sb.String()
+}
+
+// InvalidString returns a random string of about size characters that
+// Fields rejects with an error: it has an unbalanced quote or
+// parenthesis, or ends in an escape character.
+func InvalidString(r *rand.Rand, size int) string {
+	return InvalidStringWith(r, size, Options{})
+}
+
+// InvalidStringWith is like InvalidString, but takes options altering
+// its behavior.
+func InvalidStringWith(r *rand.Rand, size int, opts Options) string {
+	s := StringWith(r, max(size-1, 0), opts)
+	switch r.Intn(6) {
+	case 0:
+		return s + `\`
+	case 1:
+		return s + `"`
+	case 2:
+		return s + `'`
+	case 3:
+		return s + "("
+	case 4:
+		return "(" + s
+	default:
+		return ")" + s
+	}
+}
+
+// Corpus returns n strings generated from seed, alternating between
+// valid and invalid ones, to seed a fuzz test with testing.F.Add.
+func Corpus(seed int64, n int) []string {
+	r := rand.New(rand.NewSource(seed))
+	corpus := make([]string, n)
+	for i := range corpus {
+		size := r.Intn(64)
+		if i%2 == 0 {
+			corpus[i] = String(r, size)
+		} else {
+			corpus[i] = InvalidString(r, size)
+		}
+	}
+	return corpus
+}
+
+// Valid is a string that Fields splits at ',' without error. It
+// implements quick.Generator, so properties taking a Valid are checked
+// with generated strings.
+type Valid string
+
+// Generate returns a random Valid of about size characters.
+func (Valid) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(Valid(String(r, size)))
+}
+
+// Invalid is a string that Fields rejects with an error. It implements
+// quick.Generator, so properties taking an Invalid are checked with
+// generated strings.
+type Invalid string
+
+// Generate returns a random Invalid of about size characters.
+func (Invalid) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(Invalid(InvalidString(r, size)))
+}
+
+// generator writes random items until its budget of characters is
+// spent.
+type generator struct {
+	r        *rand.Rand
+	opts     Options
+	alphabet []rune
+	budget   int
+}
+
+// specials are the characters with a meaning in the syntax.
+const specials = `\"'()`
+
+// item writes a random item at parenthesis depth depth to sb.
+func (g *generator) item(sb *strings.Builder, depth int) {
+	switch n := g.r.Intn(10); {
+	case n < 4:
+		g.plain(sb)
+	case n < 5:
+		sb.WriteRune(g.opts.Sep)
+		g.budget--
+	case n < 6:
+		g.escape(sb)
+	case n < 7:
+		g.quoted(sb, '"')
+	case n < 8:
+		g.quoted(sb, '\'')
+	default:
+		if depth >= g.opts.MaxDepth {
+			g.plain(sb)
+			return
+		}
+		sb.WriteByte('(')
+		g.budget -= 2
+		for inner := g.r.Intn(4); inner > 0 && g.budget > 0; inner-- {
+			g.item(sb, depth+1)
+		}
+		sb.WriteByte(')')
+	}
+}
+
+func (g *generator) plain(sb *strings.Builder) {
+	sb.WriteRune(g.alphabet[g.r.Intn(len(g.alphabet))])
+	g.budget--
+}
+
+// escape writes an escape character followed by a character that is
+// usually special.
+func (g *generator) escape(sb *strings.Builder) {
+	sb.WriteByte('\\')
+	switch n := g.r.Intn(len(specials) + 2); {
+	case n < len(specials):
+		sb.WriteByte(specials[n])
+	case n == len(specials):
+		sb.WriteRune(g.opts.Sep)
+	default:
+		sb.WriteRune(g.alphabet[g.r.Intn(len(g.alphabet))])
+	}
+	g.budget -= 2
+}
+
+// quoted writes text quoted by q, holding plain characters, separators,
+// parentheses, escapes and the other kind of quote.
+func (g *generator) quoted(sb *strings.Builder, q byte) {
+	other := byte('"')
+	if q == '"' {
+		other = '\''
+	}
+
+	sb.WriteByte(q)
+	g.budget -= 2
+	for inner := g.r.Intn(6); inner > 0 && g.budget > 0; inner-- {
+		switch g.r.Intn(6) {
+		case 0:
+			sb.WriteRune(g.opts.Sep)
+			g.budget--
+		case 1:
+			sb.WriteByte("()"[g.r.Intn(2)])
+			g.budget--
+		case 2:
+			sb.WriteByte(other)
+			g.budget--
+		case 3:
+			g.escape(sb)
+		default:
+			g.plain(sb)
+		}
+	}
+	sb.WriteByte(q)
+}
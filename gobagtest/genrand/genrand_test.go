@@ -0,0 +1,117 @@
+package genrand
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+	"testing/quick"
+
+	"github.com/stianwa/gobag"
+)
+
+func TestValid(t *testing.T) {
+	valid := func(s Valid) bool {
+		_, err := gobag.Fields(string(s), ',')
+		return err == nil
+	}
+	if err := quick.Check(valid, &quick.Config{MaxCount: 2000}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	invalid := func(s Invalid) bool {
+		_, err := gobag.Fields(string(s), ',')
+		return err != nil
+	}
+	if err := quick.Check(invalid, &quick.Config{MaxCount: 2000}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStringWith(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	opts := Options{Sep: ';', MaxDepth: 1, Alphabet: "x"}
+	for range 500 {
+		s := StringWith(r, 40, opts)
+		if depth(s) > 1 {
+			t.Fatalf("StringWith(MaxDepth: 1) = %q, nested parentheses", s)
+		}
+		if _, err := gobag.Fields(s, ';'); err != nil {
+			t.Fatalf("Fields(%q, ';') error = %v", s, err)
+		}
+		if _, err := gobag.Fields(InvalidStringWith(r, 40, opts), ';'); err == nil {
+			t.Fatalf("Fields(InvalidStringWith(...)) succeeded")
+		}
+	}
+}
+
+// depth returns the maximum nesting of unquoted parentheses in s.
+func depth(s string) int {
+	var balance, deepest int
+	var quote rune
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(':
+			balance++
+			deepest = max(deepest, balance)
+		case r == ')':
+			balance--
+		}
+	}
+	return deepest
+}
+
+func TestStringSize(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	if s := String(r, 0); s != "" {
+		t.Errorf("String(r, 0) = %q, want empty", s)
+	}
+	for range 100 {
+		if s := String(r, 20); len([]rune(s)) > 40 {
+			t.Errorf("String(r, 20) = %q, too long", s)
+		}
+	}
+}
+
+func TestCorpus(t *testing.T) {
+	corpus := Corpus(1, 10)
+	if !slices.Equal(corpus, Corpus(1, 10)) {
+		t.Error("Corpus is not deterministic")
+	}
+	for i, s := range corpus {
+		_, err := gobag.Fields(s, ',')
+		if (err == nil) != (i%2 == 0) {
+			t.Errorf("Corpus[%d] = %q, Fields error = %v", i, s, err)
+		}
+	}
+}
+
+func FuzzFieldsLazy(f *testing.F) {
+	for _, s := range Corpus(1, 32) {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		fields, err := gobag.Fields(s, ',')
+		lazy, lazyErr := gobag.FieldsLazy(s, ',')
+		if (err == nil) != (lazyErr == nil) {
+			t.Fatalf("Fields error = %v, FieldsLazy error = %v", err, lazyErr)
+		}
+		for i, f := range lazy {
+			if f.Value() != fields[i] {
+				t.Errorf("field %d: FieldsLazy = %q, Fields = %q", i, f.Value(), fields[i])
+			}
+		}
+	})
+}
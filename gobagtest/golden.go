@@ -0,0 +1,46 @@
+package gobagtest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stianwa/gobag"
+)
+
+// update is the -gobagtest.update flag of go test, making Golden
+// rewrite the golden files. It is prefixed so it does not clash with
+// an -update flag of the packages under test.
+var update = flag.Bool("gobagtest.update", false, "update golden files of gobagtest.Golden")
+
+// Golden compares got to the contents of the golden file
+// testdata/<name>.golden and reports a test error with a line diff if
+// they differ. Running the tests with -gobagtest.update writes got to
+// the golden file instead, creating it if needed.
+func Golden[T ~string | ~[]byte](t testing.TB, name string, got T) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("golden: %v", err)
+			return
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("golden: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("golden: %v (run go test -gobagtest.update to create it)", err)
+		return
+	}
+	if string(want) != string(got) {
+		diff := gobag.RenderDiff(strings.Split(string(want), "\n"), strings.Split(string(got), "\n"))
+		t.Errorf("golden: %s differs (-want +got):\n%s", path, diff)
+	}
+}
@@ -0,0 +1,70 @@
+package gobagtest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// recorder is a testing.TB recording errors instead of failing.
+type recorder struct {
+	testing.TB
+	errors []string
+	fatal  bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+	r.fatal = true
+}
+
+func TestGolden(t *testing.T) {
+	Golden(t, "example", "alpha\nbeta\ngamma\n")
+	Golden(t, "example", []byte("alpha\nbeta\ngamma\n"))
+
+	r := &recorder{TB: t}
+	Golden(r, "example", "alpha\nBETA\ngamma\n")
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "-beta\n+BETA") {
+		t.Errorf("Golden() with changed output reported %q", r.errors)
+	}
+
+	r = &recorder{TB: t}
+	Golden(r, "missing", "x")
+	if !r.fatal || !strings.Contains(r.errors[0], "go test -gobagtest.update") {
+		t.Errorf("Golden() with missing file reported %q", r.errors)
+	}
+}
+
+func TestGoldenUpdate(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	*update = true
+	defer func() { *update = false }()
+	Golden(t, "sub/new", "created\n")
+
+	data, err := os.ReadFile(filepath.Join(dir, "testdata", "sub", "new.golden"))
+	if err != nil || string(data) != "created\n" {
+		t.Errorf("golden file = %q, %v", data, err)
+	}
+}
+
+func TestGoldenFlag(t *testing.T) {
+	// Packages using Golden must remain free to define -update.
+	if flag.Lookup("update") != nil {
+		t.Error("gobagtest defines -update")
+	}
+	if flag.Lookup("gobagtest.update") == nil {
+		t.Error("gobagtest does not define -gobagtest.update")
+	}
+	flag.NewFlagSet("test", flag.PanicOnError).Bool("update", false, "")
+}
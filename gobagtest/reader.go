@@ -0,0 +1,77 @@
+package gobagtest
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrInjected is the error returned by the readers of this package
+// when no other error is given.
+var ErrInjected = errors.New("injected read error")
+
+type errAfterReader struct {
+	r   io.Reader
+	n   int
+	err error
+}
+
+// ErrAfterN returns a reader reading the first n bytes of r, and then
+// failing with err, or ErrInjected if err is nil. If r ends before n
+// bytes, the reader ends with it. It exercises the error handling of
+// parsers reading from r partway through the input.
+func ErrAfterN(r io.Reader, n int, err error) io.Reader {
+	if err == nil {
+		err = ErrInjected
+	}
+	return &errAfterReader{r: r, n: n, err: err}
+}
+
+func (e *errAfterReader) Read(p []byte) (int, error) {
+	if e.n <= 0 {
+		return 0, e.err
+	}
+	if len(p) > e.n {
+		p = p[:e.n]
+	}
+	n, err := e.r.Read(p)
+	e.n -= n
+	return n, err
+}
+
+// FlakyReader is a reader failing every few reads with an error, such
+// as a temporary network error, while the reads in between succeed and
+// the input is not lost. It checks that callers retrying failed reads
+// see the input intact, and that others give up.
+type FlakyReader struct {
+	r     io.Reader
+	every int
+	err   error
+	reads int
+
+	// Failures counts the reads that failed.
+	Failures int
+}
+
+// NewFlakyReader returns a FlakyReader reading from r, failing every
+// every reads with err, or ErrInjected if err is nil. It panics if
+// every is less than 1.
+func NewFlakyReader(r io.Reader, every int, err error) *FlakyReader {
+	if every < 1 {
+		panic("gobagtest: flaky reader must fail at most every read")
+	}
+	if err == nil {
+		err = ErrInjected
+	}
+	return &FlakyReader{r: r, every: every, err: err}
+}
+
+// Read reads from the underlying reader, unless it is the turn of this
+// read to fail.
+func (f *FlakyReader) Read(p []byte) (int, error) {
+	f.reads++
+	if f.reads%f.every == 0 {
+		f.Failures++
+		return 0, f.err
+	}
+	return f.r.Read(p)
+}
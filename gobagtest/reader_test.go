@@ -0,0 +1,53 @@
+package gobagtest
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stianwa/gobag"
+)
+
+func TestErrAfterN(t *testing.T) {
+	data, err := io.ReadAll(ErrAfterN(strings.NewReader("abcdef"), 4, nil))
+	if string(data) != "abcd" || !errors.Is(err, ErrInjected) {
+		t.Errorf("ReadAll(ErrAfterN(4)) = %q, %v, want \"abcd\", ErrInjected", data, err)
+	}
+
+	data, err = io.ReadAll(ErrAfterN(strings.NewReader("ab"), 4, nil))
+	if string(data) != "ab" || err != nil {
+		t.Errorf("ReadAll(ErrAfterN(4)) of short input = %q, %v, want \"ab\", nil", data, err)
+	}
+
+	custom := errors.New("disk on fire")
+	_, err = gobag.ParseEnv(ErrAfterN(strings.NewReader("A=1\nB=2\n"), 4, custom))
+	if !errors.Is(err, custom) {
+		t.Errorf("ParseEnv(ErrAfterN(4)) error = %v, want %v", err, custom)
+	}
+}
+
+func TestFlakyReader(t *testing.T) {
+	flaky := NewFlakyReader(iotest.OneByteReader(strings.NewReader("abcdef")), 3, nil)
+	var data []byte
+	buf := make([]byte, 8)
+	for {
+		n, err := flaky.Read(buf)
+		data = append(data, buf[:n]...)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && !errors.Is(err, ErrInjected) {
+			t.Fatalf("Read() error = %v", err)
+		}
+	}
+	if string(data) != "abcdef" || flaky.Failures != 3 {
+		t.Errorf("retried reads = %q with %d failures, want \"abcdef\" with 3", data, flaky.Failures)
+	}
+
+	_, err := io.ReadAll(NewFlakyReader(strings.NewReader("abc"), 1, nil))
+	if !errors.Is(err, ErrInjected) {
+		t.Errorf("ReadAll(NewFlakyReader(1)) error = %v, want ErrInjected", err)
+	}
+}
@@ -0,0 +1,50 @@
+package gobagtest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TempFileWith creates a file called name holding contents in a new
+// temporary directory, and returns its path. The directory is removed
+// when the test and its subtests complete. The test fails if the file
+// cannot be created.
+func TempFileWith[T ~string | ~[]byte](t testing.TB, name string, contents T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("temp file: %v", err)
+	}
+	return path
+}
+
+// TempDirTree creates a new temporary directory holding the files of
+// tree, mapping slash separated paths relative to the directory to
+// their contents, and returns the directory. Parent directories are
+// created as needed, and a path ending in a slash creates an empty
+// directory. The directory is removed when the test and its subtests
+// complete. The test fails if the tree cannot be created.
+func TempDirTree(t testing.TB, tree map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, contents := range tree {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if strings.HasSuffix(name, "/") {
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				t.Fatalf("temp dir tree: %v", err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("temp dir tree: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+			t.Fatalf("temp dir tree: %v", err)
+		}
+	}
+	return dir
+}
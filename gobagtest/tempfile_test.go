@@ -0,0 +1,61 @@
+package gobagtest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stianwa/gobag"
+)
+
+func TestTempFileWith(t *testing.T) {
+	path := TempFileWith(t, "hosts.txt", "b\na\n")
+	if filepath.Base(path) != "hosts.txt" {
+		t.Errorf("TempFileWith() = %q, want a file named hosts.txt", path)
+	}
+
+	changed, err := gobag.RewriteFile(path, func(lines []string) ([]string, error) {
+		return []string{lines[1], lines[0]}, nil
+	})
+	if err != nil || !changed {
+		t.Fatalf("RewriteFile() = %v, %v", changed, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "a\nb\n" {
+		t.Errorf("rewritten file = %q, %v, want \"a\\nb\\n\"", data, err)
+	}
+
+	if data, _ := os.ReadFile(TempFileWith(t, "raw", []byte{0, 1})); string(data) != "\x00\x01" {
+		t.Errorf("TempFileWith() with bytes wrote %q", data)
+	}
+}
+
+func TestTempDirTree(t *testing.T) {
+	dir := TempDirTree(t, map[string]string{
+		"logs/a.log":     "one\ntwo\n",
+		"logs/old/b.log": "three\n",
+		"empty/":         "",
+	})
+
+	if info, err := os.Stat(filepath.Join(dir, "empty")); err != nil || !info.IsDir() {
+		t.Errorf("empty/ not created as a directory: %v", err)
+	}
+
+	var lines []string
+	for line, err := range gobag.IterLines(filepath.Join(dir, "logs", "*.log"), filepath.Join(dir, "logs", "old", "*.log")) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, line.Text)
+	}
+	EqualSlices(t, []string{"one", "two", "three"}, lines)
+}
+
+func TestTempFileWithFailure(t *testing.T) {
+	r := &recorder{TB: t}
+	TempFileWith(r, "missing/file", "x")
+	if !r.fatal || !strings.HasPrefix(r.errors[0], "temp file:") {
+		t.Errorf("TempFileWith() in missing directory reported %q", r.errors)
+	}
+}
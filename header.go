@@ -0,0 +1,51 @@
+package gobag
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ParseHeaderBlock reads a block of RFC 822 style "Key: value" header
+// lines from r, up to the first blank line or the end of input. A line
+// starting with a space or tab continues the value of the previous
+// header. The headers are returned in order, including repeated keys,
+// along with a reader for the rest of the input after the blank line.
+// Returns an error with the line number for malformed lines.
+func ParseHeaderBlock(r io.Reader) (Params, io.Reader, error) {
+	br := bufio.NewReader(r)
+	headers := make(Params, 0)
+
+	for lineno := 1; ; lineno++ {
+		line, err := br.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, nil, err
+		}
+		eof := err != nil
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case line == "":
+			return headers, br, nil
+		case line[0] == ' ' || line[0] == '\t':
+			if len(headers) == 0 {
+				return nil, nil, fmt.Errorf("line %d: continuation line without header", lineno)
+			}
+			last := &headers[len(headers)-1]
+			last.Value = strings.TrimSpace(last.Value + " " + strings.TrimSpace(line))
+		default:
+			key, value, ok := strings.Cut(line, ":")
+			key = strings.TrimSpace(key)
+			if !ok || key == "" || strings.ContainsAny(key, " \t") {
+				return nil, nil, fmt.Errorf("line %d: malformed header line", lineno)
+			}
+			headers = append(headers, Param{Key: key, Value: strings.TrimSpace(value)})
+		}
+
+		if eof {
+			return headers, br, nil
+		}
+	}
+}
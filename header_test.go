@@ -0,0 +1,39 @@
+package gobag
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseHeaderBlock(t *testing.T) {
+	input := "Title: Hello\r\nTags: a\r\nTags: b,\r\n  c\r\nEmpty:\r\n\r\nBody line 1\nBody line 2\n"
+	headers, body, err := ParseHeaderBlock(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseHeaderBlock() error = %v", err)
+	}
+
+	want := Params{{"Title", "Hello"}, {"Tags", "a"}, {"Tags", "b, c"}, {"Empty", ""}}
+	if !reflect.DeepEqual(headers, want) {
+		t.Errorf("ParseHeaderBlock() headers = %q, want %q", headers, want)
+	}
+	rest, _ := io.ReadAll(body)
+	if string(rest) != "Body line 1\nBody line 2\n" {
+		t.Errorf("ParseHeaderBlock() body = %q", rest)
+	}
+
+	headers, body, err = ParseHeaderBlock(strings.NewReader("Key: value"))
+	if err != nil || !reflect.DeepEqual(headers, Params{{"Key", "value"}}) {
+		t.Errorf("ParseHeaderBlock() without body = %q, %v", headers, err)
+	}
+	if rest, _ := io.ReadAll(body); len(rest) != 0 {
+		t.Errorf("ParseHeaderBlock() body = %q, want empty", rest)
+	}
+
+	for _, bad := range []string{" cont\n", "no colon\n", "bad key: x\n", ": x\n"} {
+		if _, _, err := ParseHeaderBlock(strings.NewReader(bad)); err == nil {
+			t.Errorf("ParseHeaderBlock(%q): expected error", bad)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package gobag
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// HostPort is a network endpoint given by a host name or IP address
+// and a port.
+type HostPort struct {
+	Host string
+	Port uint16
+}
+
+// String returns the endpoint in host:port form, with IPv6 addresses
+// in brackets.
+func (hp HostPort) String() string {
+	return net.JoinHostPort(hp.Host, strconv.Itoa(int(hp.Port)))
+}
+
+// AddrPort returns the endpoint as a netip.AddrPort. Returns an error
+// if Host is not an IP address.
+func (hp HostPort) AddrPort() (netip.AddrPort, error) {
+	addr, err := netip.ParseAddr(hp.Host)
+	if err != nil {
+		return netip.AddrPort{}, err
+	}
+	return netip.AddrPortFrom(addr, hp.Port), nil
+}
+
+// ParseHostPorts parses a comma separated list of endpoints, such as
+// "db1:5432, [2001:db8::1]:5433, db2". Entries without a port get
+// defaultPort. IPv6 addresses must be in brackets when a port is
+// given, but may be bare otherwise. Entries may be surrounded by
+// whitespace and quoted, and empty entries are ignored.
+func ParseHostPorts(s string, defaultPort uint16) ([]HostPort, error) {
+	entries, err := Fields(s, ',')
+	if err != nil {
+		return nil, err
+	}
+
+	hostPorts := make([]HostPort, 0, len(entries))
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		if unquoted, err := UnquoteStringWith(e, UnquoteOptions{Strict: true, SingleQuote: true}); err == nil {
+			e = strings.TrimSpace(unquoted)
+		}
+		if e == "" {
+			continue
+		}
+
+		hp, err := parseHostPort(e, defaultPort)
+		if err != nil {
+			return nil, err
+		}
+		hostPorts = append(hostPorts, hp)
+	}
+
+	return hostPorts, nil
+}
+
+func parseHostPort(s string, defaultPort uint16) (HostPort, error) {
+	// Bare IPv6 address, or bracketed address without a port.
+	if addr, err := netip.ParseAddr(s); err == nil {
+		return HostPort{Host: addr.String(), Port: defaultPort}, nil
+	}
+	if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		addr, err := netip.ParseAddr(s[1 : len(s)-1])
+		if err != nil {
+			return HostPort{}, fmt.Errorf("invalid address %q: %w", s, err)
+		}
+		return HostPort{Host: addr.String(), Port: defaultPort}, nil
+	}
+	if !strings.Contains(s, ":") {
+		return HostPort{Host: s, Port: defaultPort}, nil
+	}
+
+	host, port, err := net.SplitHostPort(s)
+	if err != nil {
+		return HostPort{}, fmt.Errorf("invalid endpoint %q: %w", s, err)
+	}
+	if host == "" {
+		return HostPort{}, fmt.Errorf("invalid endpoint %q: missing host", s)
+	}
+	n, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return HostPort{}, fmt.Errorf("invalid port in endpoint %q", s)
+	}
+
+	return HostPort{Host: host, Port: uint16(n)}, nil
+}
@@ -0,0 +1,40 @@
+package gobag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseHostPorts(t *testing.T) {
+	got, err := ParseHostPorts(`db1:5432, [2001:db8::1]:5433, db2, ::1, "[fe80::1]", 10.0.0.1:80,`, 5432)
+	if err != nil {
+		t.Fatalf("ParseHostPorts() error = %v", err)
+	}
+	want := []HostPort{
+		{"db1", 5432},
+		{"2001:db8::1", 5433},
+		{"db2", 5432},
+		{"::1", 5432},
+		{"fe80::1", 5432},
+		{"10.0.0.1", 80},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseHostPorts() = %v, want %v", got, want)
+	}
+
+	if s := got[1].String(); s != "[2001:db8::1]:5433" {
+		t.Errorf("String() = %q", s)
+	}
+	if ap, err := got[5].AddrPort(); err != nil || ap.String() != "10.0.0.1:80" {
+		t.Errorf("AddrPort() = %v, %v", ap, err)
+	}
+	if _, err := got[0].AddrPort(); err == nil {
+		t.Error("AddrPort() of host name: expected error")
+	}
+
+	for _, bad := range []string{"db:99999", ":80", "[zz::1]", "db:http"} {
+		if _, err := ParseHostPorts(bad, 1); err == nil {
+			t.Errorf("ParseHostPorts(%q): expected error", bad)
+		}
+	}
+}
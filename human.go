@@ -0,0 +1,178 @@
+package gobag
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	siPrefixes  = []string{"", "k", "M", "G", "T", "P", "E"}
+	iecPrefixes = []string{"", "Ki", "Mi", "Gi", "Ti", "Pi", "Ei"}
+)
+
+// HumanInt formats n abbreviated with a metric suffix and at most one
+// decimal, e.g. 950 as "950", 1500 as "1.5k" and 1234567 as "1.2M".
+func HumanInt(n int64) string {
+	return humanize(n, 1000, siPrefixes, "")
+}
+
+// HumanBytes formats a byte count using binary (IEC) units, e.g. 512
+// as "512 B", 1536 as "1.5 KiB" and 1<<30 as "1 GiB".
+func HumanBytes(n int64) string {
+	return humanize(n, 1024, iecPrefixes, " ") + "B"
+}
+
+func humanize(n int64, base float64, prefixes []string, space string) string {
+	sign := ""
+	v := float64(n)
+	if n < 0 {
+		sign = "-"
+		v = -v
+	}
+
+	unit := 0
+	for v >= base && unit < len(prefixes)-1 {
+		v /= base
+		unit++
+	}
+	// Rounding to one decimal may carry over into the next unit, as
+	// with 999960 becoming "1000.0k".
+	if unit > 0 && math.Round(v*10)/10 >= base && unit < len(prefixes)-1 {
+		v /= base
+		unit++
+	}
+
+	s := strconv.FormatFloat(v, 'f', 1, 64)
+	if unit == 0 {
+		s = strconv.FormatFloat(v, 'f', 0, 64)
+	}
+	s = strings.TrimSuffix(s, ".0")
+
+	if unit == 0 && space != "" {
+		return sign + s + space
+	}
+	return sign + s + space + prefixes[unit]
+}
+
+// ParseHumanInt parses a number with an optional metric suffix, the
+// inverse of HumanInt. The suffix is one of k, M, G, T, P or E, case
+// insensitive except for m and M, which both mean mega. The number may
+// have a fraction, as in "1.5k", but the result must be a whole
+// number in range of int64.
+func ParseHumanInt(s string) (int64, error) {
+	num, suffix := splitHumanSuffix(s)
+	unit := -1
+	for i, p := range siPrefixes {
+		if strings.EqualFold(suffix, p) {
+			unit = i
+			break
+		}
+	}
+	if unit < 0 {
+		return 0, fmt.Errorf("invalid number %q: unknown suffix %q", s, suffix)
+	}
+
+	n, err := scaleHuman(num, math.Pow(1000, float64(unit)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q: %w", s, err)
+	}
+	return n, nil
+}
+
+// ParseHumanBytes parses a byte size, the inverse of HumanBytes. Units
+// with an i, such as KiB and MiB, are binary (powers of 1024), while
+// kB, MB, GB and so on are decimal (powers of 1000). As is common in
+// configuration files, a bare prefix such as "64K" or "1G" is binary.
+// The unit is case insensitive, and "512" or "512 B" are plain bytes.
+func ParseHumanBytes(s string) (int64, error) {
+	num, suffix := splitHumanSuffix(s)
+
+	base := 1024.0
+	prefix := suffix
+	switch lower := strings.ToLower(suffix); {
+	case lower == "b" || lower == "":
+		prefix = ""
+	case strings.HasSuffix(lower, "ib"):
+		prefix = suffix[:len(suffix)-2]
+	case strings.HasSuffix(lower, "b"):
+		prefix = suffix[:len(suffix)-1]
+		base = 1000
+	}
+
+	unit := -1
+	for i, p := range siPrefixes {
+		if i > 0 && strings.EqualFold(prefix, p) {
+			unit = i
+			break
+		}
+	}
+	if prefix == "" {
+		unit = 0
+	}
+	if unit < 0 {
+		return 0, fmt.Errorf("invalid byte size %q: unknown unit %q", s, suffix)
+	}
+
+	n, err := scaleHuman(num, math.Pow(base, float64(unit)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q: %w", s, err)
+	}
+	return n, nil
+}
+
+// splitHumanSuffix splits s into its numeric part and its trimmed
+// unit suffix.
+func splitHumanSuffix(s string) (num, suffix string) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= '0' && r <= '9' || r == '.' || r == '-' || r == '+')
+	})
+	if i < 0 {
+		return s, ""
+	}
+	return s[:i], strings.TrimSpace(s[i:])
+}
+
+// scaleHuman returns num multiplied by scale, requiring the result to
+// be a whole number in range of int64.
+func scaleHuman(num string, scale float64) (int64, error) {
+	if n, err := strconv.ParseInt(num, 10, 64); err == nil {
+		m := int64(scale)
+		if n != 0 && (n*m/m != n || n*m/n != m) {
+			return 0, errors.New("value out of range")
+		}
+		return n * m, nil
+	}
+
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed number %q", num)
+	}
+	f *= scale
+	if f != math.Trunc(f) {
+		return 0, errors.New("not a whole number")
+	}
+	if f >= math.MaxInt64 || f < math.MinInt64 {
+		return 0, errors.New("value out of range")
+	}
+	return int64(f), nil
+}
+
+// Ordinal returns n with its English ordinal suffix, e.g. "1st",
+// "2nd", "3rd", "4th", "11th" and "22nd".
+func Ordinal(n int) string {
+	suffix := "th"
+	switch abs := max(n, -n); {
+	case abs%100 >= 11 && abs%100 <= 13:
+	case abs%10 == 1:
+		suffix = "st"
+	case abs%10 == 2:
+		suffix = "nd"
+	case abs%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
+}
@@ -0,0 +1,124 @@
+package gobag
+
+import "testing"
+
+func TestHumanInt(t *testing.T) {
+	tests := []struct {
+		input    int64
+		expected string
+	}{
+		{0, "0"},
+		{950, "950"},
+		{1000, "1k"},
+		{1500, "1.5k"},
+		{1234567, "1.2M"},
+		{999960, "1M"},
+		{-2500, "-2.5k"},
+		{3_000_000_000, "3G"},
+	}
+	for _, tt := range tests {
+		if got := HumanInt(tt.input); got != tt.expected {
+			t.Errorf("HumanInt(%d) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestHumanBytes(t *testing.T) {
+	tests := []struct {
+		input    int64
+		expected string
+	}{
+		{0, "0 B"},
+		{512, "512 B"},
+		{1024, "1 KiB"},
+		{1536, "1.5 KiB"},
+		{1 << 30, "1 GiB"},
+		{5 << 40, "5 TiB"},
+	}
+	for _, tt := range tests {
+		if got := HumanBytes(tt.input); got != tt.expected {
+			t.Errorf("HumanBytes(%d) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestParseHumanInt(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+		err      string
+	}{
+		{"950", 950, ""},
+		{"1.5k", 1500, ""},
+		{"1.2M", 1200000, ""},
+		{"3m", 3000000, ""},
+		{" 2 G ", 2000000000, ""},
+		{"-4k", -4000, ""},
+		{"1.0005k", 0, `invalid number "1.0005k": not a whole number`},
+		{"10X", 0, `invalid number "10X": unknown suffix "X"`},
+		{"k", 0, `invalid number "k": malformed number ""`},
+		{"10E", 0, `invalid number "10E": value out of range`},
+	}
+	for _, tt := range tests {
+		got, err := ParseHumanInt(tt.input)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("ParseHumanInt(%q) error = %v, want %q", tt.input, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.expected {
+			t.Errorf("ParseHumanInt(%q) = %d, %v, want %d", tt.input, got, err, tt.expected)
+		}
+	}
+}
+
+func TestParseHumanBytes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+		err      string
+	}{
+		{"512", 512, ""},
+		{"512 B", 512, ""},
+		{"1.5 KiB", 1536, ""},
+		{"64K", 64 << 10, ""},
+		{"1g", 1 << 30, ""},
+		{"10MB", 10_000_000, ""},
+		{"2 kb", 2000, ""},
+		{"1 TiB", 1 << 40, ""},
+		{"5 XB", 0, `invalid byte size "5 XB": unknown unit "XB"`},
+		{"1.1 B", 0, `invalid byte size "1.1 B": not a whole number`},
+	}
+	for _, tt := range tests {
+		got, err := ParseHumanBytes(tt.input)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("ParseHumanBytes(%q) error = %v, want %q", tt.input, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.expected {
+			t.Errorf("ParseHumanBytes(%q) = %d, %v, want %d", tt.input, got, err, tt.expected)
+		}
+	}
+
+	for _, n := range []int64{0, 512, 1536, 1 << 20, 3 << 30} {
+		if got, err := ParseHumanBytes(HumanBytes(n)); err != nil || got != n {
+			t.Errorf("ParseHumanBytes(HumanBytes(%d)) = %d, %v", n, got, err)
+		}
+	}
+}
+
+func TestOrdinal(t *testing.T) {
+	tests := map[int]string{
+		0: "0th", 1: "1st", 2: "2nd", 3: "3rd", 4: "4th",
+		11: "11th", 12: "12th", 13: "13th", 21: "21st", 22: "22nd",
+		101: "101st", 111: "111th", 112: "112th", -1: "-1st",
+	}
+	for n, expected := range tests {
+		if got := Ordinal(n); got != expected {
+			t.Errorf("Ordinal(%d) = %q, want %q", n, got, expected)
+		}
+	}
+}
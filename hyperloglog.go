@@ -0,0 +1,120 @@
+package gobag
+
+import (
+	"errors"
+	"hash/fnv"
+	"math"
+	"math/bits"
+)
+
+// HyperLogLog estimates the number of distinct values in a stream in
+// fixed memory. With precision p it uses 2^p bytes and has a typical
+// relative error of 1.04/sqrt(2^p), about 0.8% for the default
+// precision of 14. Values are hashed with a fixed function, so
+// estimators filled in different processes can be merged. A
+// HyperLogLog is not safe for concurrent use.
+type HyperLogLog struct {
+	p         uint8
+	registers []uint8
+}
+
+// Limits and default of the HyperLogLog precision.
+const (
+	MinHyperLogLogPrecision     = 4
+	MaxHyperLogLogPrecision     = 18
+	DefaultHyperLogLogPrecision = 14
+)
+
+// NewHyperLogLog returns an empty estimator with the given precision,
+// between MinHyperLogLogPrecision and MaxHyperLogLogPrecision. A
+// precision of 0 selects DefaultHyperLogLogPrecision.
+func NewHyperLogLog(precision int) (*HyperLogLog, error) {
+	if precision == 0 {
+		precision = DefaultHyperLogLogPrecision
+	}
+	if precision < MinHyperLogLogPrecision || precision > MaxHyperLogLogPrecision {
+		return nil, errors.New("hyperloglog precision out of range")
+	}
+	return &HyperLogLog{
+		p:         uint8(precision),
+		registers: make([]uint8, 1<<precision),
+	}, nil
+}
+
+// Add adds a value to the estimator.
+func (h *HyperLogLog) Add(b []byte) {
+	f := fnv.New64a()
+	f.Write(b)
+	h.addHash(mix64(f.Sum64()))
+}
+
+// AddString adds a value to the estimator.
+func (h *HyperLogLog) AddString(s string) {
+	h.Add([]byte(s))
+}
+
+func (h *HyperLogLog) addHash(x uint64) {
+	i := x >> (64 - h.p)
+	rank := uint8(min(bits.LeadingZeros64(x<<h.p), 64-int(h.p)) + 1)
+	h.registers[i] = max(h.registers[i], rank)
+}
+
+// Estimate returns the estimated number of distinct values added.
+func (h *HyperLogLog) Estimate() uint64 {
+	m := float64(len(h.registers))
+	sum := 0.0
+	zeros := 0
+	for _, r := range h.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	var alpha float64
+	switch len(h.registers) {
+	case 16:
+		alpha = 0.673
+	case 32:
+		alpha = 0.697
+	case 64:
+		alpha = 0.709
+	default:
+		alpha = 0.7213 / (1 + 1.079/m)
+	}
+	estimate := alpha * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		// Linear counting is more accurate for small cardinalities.
+		estimate = m * math.Log(m/float64(zeros))
+	}
+
+	return uint64(estimate + 0.5)
+}
+
+// Merge adds the values of other to h, so h estimates the number of
+// distinct values added to either. Both must have the same precision.
+func (h *HyperLogLog) Merge(other *HyperLogLog) error {
+	if h.p != other.p {
+		return errors.New("cannot merge hyperloglogs of different precision")
+	}
+	for i, r := range other.registers {
+		h.registers[i] = max(h.registers[i], r)
+	}
+	return nil
+}
+
+// Reset removes all values from the estimator.
+func (h *HyperLogLog) Reset() {
+	clear(h.registers)
+}
+
+// mix64 spreads the bits of a hash evenly, as FNV leaves the high bits
+// poorly mixed for short inputs.
+func mix64(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
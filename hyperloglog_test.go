@@ -0,0 +1,60 @@
+package gobag
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestHyperLogLog(t *testing.T) {
+	for _, n := range []int{0, 10, 1000, 100000} {
+		h, err := NewHyperLogLog(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range n {
+			h.AddString("value-" + strconv.Itoa(i))
+			h.Add([]byte("value-" + strconv.Itoa(i)))
+		}
+		got := h.Estimate()
+		if diff := math.Abs(float64(got) - float64(n)); diff > 0.03*float64(n) {
+			t.Errorf("Estimate() after %d distinct values = %d", n, got)
+		}
+	}
+}
+
+func TestHyperLogLogMerge(t *testing.T) {
+	a, _ := NewHyperLogLog(12)
+	b, _ := NewHyperLogLog(12)
+	for i := range 20000 {
+		a.AddString(strconv.Itoa(i))
+		b.AddString(strconv.Itoa(i + 10000))
+	}
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+	if got := a.Estimate(); math.Abs(float64(got)-30000) > 0.05*30000 {
+		t.Errorf("Estimate() after Merge = %d, want about 30000", got)
+	}
+
+	c, _ := NewHyperLogLog(10)
+	if err := a.Merge(c); err == nil || err.Error() != "cannot merge hyperloglogs of different precision" {
+		t.Errorf("Merge() with different precision error = %v", err)
+	}
+
+	a.Reset()
+	if got := a.Estimate(); got != 0 {
+		t.Errorf("Estimate() after Reset = %d, want 0", got)
+	}
+}
+
+func TestNewHyperLogLog(t *testing.T) {
+	for _, p := range []int{-1, 3, 19} {
+		if _, err := NewHyperLogLog(p); err == nil || err.Error() != "hyperloglog precision out of range" {
+			t.Errorf("NewHyperLogLog(%d) error = %v", p, err)
+		}
+	}
+	if h, err := NewHyperLogLog(4); err != nil || len(h.registers) != 16 {
+		t.Errorf("NewHyperLogLog(4) = %v, %v", h, err)
+	}
+}
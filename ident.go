@@ -0,0 +1,50 @@
+package gobag
+
+import "unicode"
+
+// SplitIdentifier splits an identifier into its words, recognizing
+// camelCase and PascalCase boundaries as well as '_', '-', '.' and
+// space separators. Runs of capitals are kept together as acronyms,
+// and digits stay with the word they follow:
+//
+//	SplitIdentifier("HTTPServerMaxConns") // ["HTTP" "Server" "Max" "Conns"]
+//	SplitIdentifier("parseUTF8String")    // ["parse" "UTF8" "String"]
+//	SplitIdentifier("max_idle-conns")     // ["max" "idle" "conns"]
+func SplitIdentifier(s string) []string {
+	words := make([]string, 0)
+	runes := []rune(s)
+	start := -1
+
+	flush := func(end int) {
+		if start >= 0 && end > start {
+			words = append(words, string(runes[start:end]))
+		}
+		start = -1
+	}
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush(i)
+			continue
+		}
+		if start < 0 {
+			start = i
+			continue
+		}
+
+		prev := runes[i-1]
+		switch {
+		case unicode.IsUpper(r) && !unicode.IsUpper(prev):
+			// "maxConns", "utf8String"
+			flush(i)
+			start = i
+		case unicode.IsUpper(r) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+			// "HTTPServer": the last capital starts the next word.
+			flush(i)
+			start = i
+		}
+	}
+	flush(len(runes))
+
+	return words
+}
@@ -0,0 +1,29 @@
+package gobag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitIdentifier(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"HTTPServerMaxConns", []string{"HTTP", "Server", "Max", "Conns"}},
+		{"parseUTF8String", []string{"parse", "UTF8", "String"}},
+		{"Base64Encode", []string{"Base64", "Encode"}},
+		{"userID", []string{"user", "ID"}},
+		{"max_idle-conns", []string{"max", "idle", "conns"}},
+		{"__init__", []string{"init"}},
+		{"log.Level", []string{"log", "Level"}},
+		{"X", []string{"X"}},
+		{"already lower case", []string{"already", "lower", "case"}},
+		{"", []string{}},
+	}
+	for _, tt := range tests {
+		if got := SplitIdentifier(tt.input); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("SplitIdentifier(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package gobag
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// sortableDigits are the digits of sortable IDs, in ASCII order so IDs
+// sort as strings in the order they were generated.
+const sortableDigits = "0123456789abcdefghijklmnopqrstuv"
+
+// Lengths of the parts of sortable IDs, in base 32 digits.
+const (
+	sortableTimeDigits  = 10 // Milliseconds since 1970, 50 bits.
+	sortableCountDigits = 4  // IDs within a millisecond, 20 bits.
+)
+
+// IDGen generates unique IDs for tagging records, either numbered in
+// sequence, like "req-000123", or sortable by creation time across
+// program runs, like "req-01hf7uaq000003". It is safe for concurrent
+// use.
+type IDGen struct {
+	mu       sync.Mutex
+	prefix   string
+	width    int
+	sortable bool
+	seq      uint64
+	lastMs   int64
+	clock    Clock
+}
+
+// NewIDGen returns an IDGen of sequence numbered IDs starting at 1,
+// made of prefix followed by the number padded with zeros to width
+// digits.
+func NewIDGen(prefix string, width int) *IDGen {
+	return &IDGen{prefix: prefix, width: width, clock: RealClock{}}
+}
+
+// NewSortableIDGen returns an IDGen of IDs made of prefix followed by
+// 14 base 32 digits: the time in milliseconds and a counter of the IDs
+// generated within the millisecond. IDs sort in the order they were
+// generated, also if the clock goes backwards.
+func NewSortableIDGen(prefix string) *IDGen {
+	return &IDGen{prefix: prefix, sortable: true, clock: RealClock{}}
+}
+
+// Next returns a new ID.
+func (g *IDGen) Next() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.sortable {
+		g.seq++
+		n := strconv.FormatUint(g.seq, 10)
+		if pad := g.width - len(n); pad > 0 {
+			n = strings.Repeat("0", pad) + n
+		}
+		return g.prefix + n
+	}
+
+	ms := g.clock.Now().UnixMilli()
+	switch {
+	case ms > g.lastMs:
+		g.lastMs, g.seq = ms, 0
+	case g.seq+1 < 1<<(5*sortableCountDigits):
+		g.seq++
+	default:
+		// The counter is exhausted; borrow the next millisecond.
+		g.lastMs, g.seq = g.lastMs+1, 0
+	}
+
+	var buf [sortableTimeDigits + sortableCountDigits]byte
+	putBase32(buf[:sortableTimeDigits], uint64(g.lastMs))
+	putBase32(buf[sortableTimeDigits:], g.seq)
+	return g.prefix + string(buf[:])
+}
+
+// putBase32 writes v to buf as base 32 digits, padded with zeros.
+func putBase32(buf []byte, v uint64) {
+	for i := len(buf) - 1; i >= 0; i-- {
+		buf[i] = sortableDigits[v&31]
+		v >>= 5
+	}
+}
@@ -0,0 +1,76 @@
+package gobag
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestIDGen(t *testing.T) {
+	g := NewIDGen("req-", 6)
+	for _, want := range []string{"req-000001", "req-000002", "req-000003"} {
+		if got := g.Next(); got != want {
+			t.Errorf("Next() = %q, want %q", got, want)
+		}
+	}
+
+	g = NewIDGen("", 2)
+	g.seq = 99
+	if got := g.Next(); got != "100" {
+		t.Errorf("Next() past width = %q, want 100", got)
+	}
+}
+
+func TestSortableIDGen(t *testing.T) {
+	clock := NewFakeClock(time.UnixMilli(1700000000000))
+	g := NewSortableIDGen("job-")
+	g.clock = clock
+
+	first := g.Next()
+	if first != "job-01hf7uaq000000" {
+		t.Errorf("Next() = %q, want job-01hf7uaq000000", first)
+	}
+	ids := []string{first, g.Next(), g.Next()}
+	clock.Advance(-time.Second) // The clock goes backwards.
+	ids = append(ids, g.Next())
+	clock.Advance(time.Hour)
+	ids = append(ids, g.Next())
+
+	if !slices.IsSorted(ids) {
+		t.Errorf("IDs not sorted: %q", ids)
+	}
+	if len(slices.Compact(slices.Clone(ids))) != len(ids) {
+		t.Errorf("duplicate IDs: %q", ids)
+	}
+
+	g.seq = 1<<20 - 1
+	last := g.lastMs
+	g.Next()
+	if g.lastMs != last+1 || g.seq != 0 {
+		t.Errorf("exhausted counter gave time %d, count %d, want %d, 0", g.lastMs, g.seq, last+1)
+	}
+}
+
+func TestIDGenConcurrent(t *testing.T) {
+	g := NewSortableIDGen("")
+	var mu sync.Mutex
+	seen := map[string]bool{}
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				id := g.Next()
+				mu.Lock()
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(seen) != 8000 {
+		t.Errorf("%d unique IDs, want 8000", len(seen))
+	}
+}
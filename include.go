@@ -0,0 +1,164 @@
+package gobag
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Setting is a key = value setting read by LoadSettings, with the file,
+// line and column of its key.
+type Setting struct {
+	Section string
+	Key     string
+	Value   string
+	File    string
+	Line    int
+	Column  int
+}
+
+// Provenanced returns the value of the setting with its location.
+func (s Setting) Provenanced() Provenanced[string] {
+	return Provenanced[string]{
+		Value:    s.Value,
+		File:     s.File,
+		Position: Position{Line: s.Line, Column: s.Column},
+	}
+}
+
+// IncludeOptions holds optional settings for LoadSettings.
+type IncludeOptions struct {
+	// Directive is the keyword of include lines. The default is
+	// "include".
+	Directive string
+
+	// MaxDepth is the maximum nesting of included files. The default
+	// is 8.
+	MaxDepth int
+}
+
+// LoadSettings reads the settings of the configuration file at path,
+// following include directives. The file syntax is that of
+// ParseDocument, extended with lines of the form
+//
+//	include /etc/app.d/*.conf
+//
+// which read the settings of every file matching the pattern, in
+// lexical order, at that point. Relative patterns are resolved against
+// the directory of the including file. A pattern without wildcards
+// must name an existing file, while a wildcard pattern may match
+// nothing. An included file starts in the section of the include line,
+// and its section headers do not affect the including file.
+//
+// Returns an error if files include each other in a cycle or are
+// nested deeper than the depth limit. Errors in included files are
+// reported with the chain of files and lines including them.
+func LoadSettings(path string, opts IncludeOptions) ([]Setting, error) {
+	if opts.Directive == "" {
+		opts.Directive = "include"
+	}
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = 8
+	}
+
+	l := &settingsLoader{opts: opts, settings: make([]Setting, 0)}
+	if err := l.load(path, ""); err != nil {
+		return nil, err
+	}
+	return l.settings, nil
+}
+
+type settingsLoader struct {
+	opts     IncludeOptions
+	stack    []string // Absolute paths of the files being read.
+	settings []Setting
+}
+
+func (l *settingsLoader) load(path, section string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if i := slices.Index(l.stack, abs); i >= 0 {
+		return fmt.Errorf("include cycle: %s", strings.Join(append(l.stack[i:], abs), " -> "))
+	}
+	if len(l.stack) > l.opts.MaxDepth {
+		return fmt.Errorf("includes nested deeper than %d levels", l.opts.MaxDepth)
+	}
+	l.stack = append(l.stack, abs)
+	defer func() { l.stack = l.stack[:len(l.stack)-1] }()
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	err = readLogicalLines(f, docComment, func(lineno int, text, _ string) error {
+		body := strings.TrimSpace(stripComment(text, docComment))
+		if pattern, ok := l.directive(body); ok {
+			if err := l.include(filepath.Dir(path), decodeValue(pattern), section); err != nil {
+				return fmt.Errorf("line %d: %w", lineno, err)
+			}
+			return nil
+		}
+
+		line := docLine{section: section}
+		if err := parseDocLine(lineno, text, &line); err != nil {
+			return err
+		}
+		section = line.section
+		if line.key != "" {
+			l.settings = append(l.settings, Setting{
+				Section: line.section,
+				Key:     line.key,
+				Value:   line.value,
+				File:    path,
+				Line:    lineno,
+				Column:  utf8.RuneCountInString(text[:len(text)-len(strings.TrimLeftFunc(text, unicode.IsSpace))]) + 1,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// directive returns the argument of body if it is an include line.
+func (l *settingsLoader) directive(body string) (string, bool) {
+	rest, ok := strings.CutPrefix(body, l.opts.Directive)
+	if !ok || rest == "" || !unicode.IsSpace(rune(rest[0])) {
+		return "", false
+	}
+	// A setting with a key equal to the directive is not an include.
+	if _, _, isSetting := cutUnquoted(rest, '='); isSetting {
+		return "", false
+	}
+	return strings.TrimSpace(rest), true
+}
+
+func (l *settingsLoader) include(dir, pattern, section string) error {
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(dir, pattern)
+	}
+	names, err := filepath.Glob(pattern)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 && !strings.ContainsAny(pattern, `*?[\`) {
+		return fmt.Errorf("included file %s does not exist", pattern)
+	}
+
+	for _, name := range names {
+		if err := l.load(name, section); err != nil {
+			return err
+		}
+	}
+	return nil
+}
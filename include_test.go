@@ -0,0 +1,105 @@
+package gobag
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadSettings(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"main.conf":        "a = 1\ninclude conf.d/*.conf\n[db]\ninclude db.conf\ninclude = not a directive\n",
+		"conf.d/10-b.conf": "  b = 2\n",
+		"conf.d/20-c.conf": "# comment\n[other]\nc = 3\n",
+		"conf.d/skip.txt":  "x = 0\n",
+		"db.conf":          "host = db1\n",
+	})
+
+	settings, err := LoadSettings(filepath.Join(dir, "main.conf"), IncludeOptions{})
+	if err != nil {
+		t.Fatalf("LoadSettings() error = %v", err)
+	}
+	for i := range settings {
+		settings[i].File, _ = filepath.Rel(dir, settings[i].File)
+	}
+
+	want := []Setting{
+		{"", "a", "1", "main.conf", 1, 1},
+		{"", "b", "2", "conf.d/10-b.conf", 1, 3},
+		{"other", "c", "3", "conf.d/20-c.conf", 3, 1},
+		{"db", "host", "db1", "db.conf", 1, 1},
+		{"db", "include", "not a directive", "main.conf", 5, 1},
+	}
+	if !reflect.DeepEqual(settings, want) {
+		t.Errorf("LoadSettings() =\n%v\nwant\n%v", settings, want)
+	}
+
+	if err := settings[1].Provenanced().Errorf("bad value"); err.Error() != "conf.d/10-b.conf:1:3: bad value" {
+		t.Errorf("Provenanced().Errorf() = %q", err)
+	}
+}
+
+func TestLoadSettingsErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"cycle-a.conf":   "include cycle-b.conf\n",
+		"cycle-b.conf":   "x = 1\ninclude cycle-a.conf\n",
+		"missing.conf":   "include nothere.conf\n",
+		"empty.conf":     "include none.d/*.conf\n",
+		"deep.conf":      "include deep.conf\n",
+		"bad.conf":       "include bad-inner.conf\n",
+		"bad-inner.conf": "novalue\n",
+	})
+
+	tests := map[string]string{
+		"cycle-a.conf": "include cycle: ",
+		"missing.conf": "nothere.conf does not exist",
+		"bad.conf":     "bad-inner.conf: line 1: expected key = value",
+	}
+	for name, want := range tests {
+		_, err := LoadSettings(filepath.Join(dir, name), IncludeOptions{})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("LoadSettings(%s) error = %v, want containing %q", name, err, want)
+		}
+	}
+
+	if _, err := LoadSettings(filepath.Join(dir, "empty.conf"), IncludeOptions{}); err != nil {
+		t.Errorf("LoadSettings() with empty glob error = %v", err)
+	}
+
+	// A file including itself is a cycle, regardless of depth.
+	_, err := LoadSettings(filepath.Join(dir, "deep.conf"), IncludeOptions{})
+	if err == nil || !strings.Contains(err.Error(), "include cycle") {
+		t.Errorf("LoadSettings(deep.conf) error = %v, want cycle", err)
+	}
+
+	chain := make(map[string]string)
+	for i := range 5 {
+		chain[filepath.Join("chain", string(rune('a'+i))+".conf")] = "include " + string(rune('b'+i)) + ".conf\n"
+	}
+	chain[filepath.Join("chain", "f.conf")] = "x = 1\n"
+	writeFiles(t, dir, chain)
+	if _, err := LoadSettings(filepath.Join(dir, "chain", "a.conf"), IncludeOptions{MaxDepth: 3}); err == nil ||
+		!strings.Contains(err.Error(), "nested deeper than 3") {
+		t.Errorf("LoadSettings() error = %v, want depth limit", err)
+	}
+	if _, err := LoadSettings(filepath.Join(dir, "chain", "a.conf"), IncludeOptions{}); err != nil {
+		t.Errorf("LoadSettings() error = %v", err)
+	}
+}
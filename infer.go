@@ -0,0 +1,113 @@
+package gobag
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// InferOptions holds optional settings for InferTypesWith.
+type InferOptions struct {
+	// Decimal is the decimal separator of floats. The default is '.'.
+	Decimal rune
+
+	// Thousands is the digit group separator of numbers, such as ','
+	// in "1,234.5" or '.' in "1.234,5". By default digits are not
+	// grouped.
+	Thousands rune
+
+	// TimeLayouts are the layouts of time values. By default the
+	// built-in layouts of ParseTimeAny are used.
+	TimeLayouts []string
+}
+
+// InferTypes guesses the kind of each column of rows, such as the
+// records of a delimited file without the header. A column gets the
+// most specific kind that all its non-empty values have: KindInt,
+// then KindFloat, KindBool, KindDuration and KindTime, and otherwise
+// KindString. Columns without any values are KindString. Rows may have
+// different lengths; the result has one kind per column of the longest
+// row.
+func InferTypes(rows [][]string) []Kind {
+	return InferTypesWith(rows, InferOptions{})
+}
+
+// InferTypesWith is like InferTypes, but takes options altering its
+// behavior.
+func InferTypesWith(rows [][]string, opts InferOptions) []Kind {
+	if opts.Decimal == 0 {
+		opts.Decimal = '.'
+	}
+
+	var columns int
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+
+	candidates := []Kind{KindInt, KindFloat, KindBool, KindDuration, KindTime}
+	kinds := make([]Kind, columns)
+	for c := range columns {
+		// possible tracks which candidates all values so far have.
+		possible := make([]bool, len(candidates))
+		seen := false
+		for _, row := range rows {
+			if c >= len(row) {
+				continue
+			}
+			v := strings.TrimSpace(row[c])
+			if v == "" {
+				continue
+			}
+			for i, k := range candidates {
+				if !seen || possible[i] {
+					possible[i] = opts.is(k, v)
+				}
+			}
+			seen = true
+		}
+
+		kinds[c] = KindString
+		for i, k := range candidates {
+			if seen && possible[i] {
+				kinds[c] = k
+				break
+			}
+		}
+	}
+
+	return kinds
+}
+
+// is reports whether v is a value of kind k.
+func (opts InferOptions) is(k Kind, v string) bool {
+	switch k {
+	case KindInt, KindFloat:
+		if opts.Thousands != 0 {
+			v = strings.ReplaceAll(v, string(opts.Thousands), "")
+		}
+		if k == KindInt {
+			_, err := strconv.ParseInt(v, 10, 64)
+			return err == nil
+		}
+		if opts.Decimal != '.' {
+			if strings.ContainsRune(v, '.') {
+				return false
+			}
+			v = strings.Replace(v, string(opts.Decimal), ".", 1)
+		}
+		// Only plain decimal notation, not hexadecimal, underscores,
+		// or special values such as "inf" and "NaN".
+		_, err := strconv.ParseFloat(v, 64)
+		return err == nil && strings.Trim(v, "-+.0123456789eE") == ""
+	case KindBool:
+		_, ok := parseBool(v)
+		return ok
+	case KindDuration:
+		_, err := time.ParseDuration(v)
+		return err == nil
+	case KindTime:
+		_, err := ParseTimeAny(v, opts.TimeLayouts...)
+		return err == nil
+	}
+	return false
+}
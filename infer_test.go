@@ -0,0 +1,50 @@
+package gobag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInferTypes(t *testing.T) {
+	rows := [][]string{
+		{"1", "1.5", "yes", "5s", "2024-05-01", "web", "", "1"},
+		{"-20", "2", "no", "1m30s", "2024-05-01T12:00:00Z", "db", "", "x"},
+		{" 3 ", "", "TRUE", "", "2024-05-02 08:00:00", "42", ""},
+	}
+	want := []Kind{KindInt, KindFloat, KindBool, KindDuration, KindTime, KindString, KindString, KindString}
+	if got := InferTypes(rows); !reflect.DeepEqual(got, want) {
+		t.Errorf("InferTypes() = %v, want %v", got, want)
+	}
+
+	// Words parsed by strconv.ParseFloat are still text.
+	rows = [][]string{
+		{"inf", "NaN", "Infinity", "1e3", "-2.5E-1"},
+		{"-Inf", "nan", "+infinity", "2", "0x1p-2"},
+	}
+	want = []Kind{KindString, KindString, KindString, KindFloat, KindString}
+	if got := InferTypes(rows); !reflect.DeepEqual(got, want) {
+		t.Errorf("InferTypes() of special floats = %v, want %v", got, want)
+	}
+
+	if got := InferTypes(nil); len(got) != 0 {
+		t.Errorf("InferTypes(nil) = %v, want empty", got)
+	}
+}
+
+func TestInferTypesWith(t *testing.T) {
+	rows := [][]string{
+		{"1.234", "1.234,5", "01/05/2024"},
+		{"12", "0,25", "31/12/2023"},
+	}
+	opts := InferOptions{Decimal: ',', Thousands: '.', TimeLayouts: []string{"02/01/2006"}}
+	want := []Kind{KindInt, KindFloat, KindTime}
+	if got := InferTypesWith(rows, opts); !reflect.DeepEqual(got, want) {
+		t.Errorf("InferTypesWith() = %v, want %v", got, want)
+	}
+
+	// Without the options the same values are not numbers or times.
+	want = []Kind{KindFloat, KindString, KindString}
+	if got := InferTypes(rows); !reflect.DeepEqual(got, want) {
+		t.Errorf("InferTypes() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,95 @@
+package gobag
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// INISection is a section of an INI file as parsed by
+// ParseINIOrdered.
+type INISection struct {
+	Name   string
+	Params Params
+}
+
+// ParseINI parses an INI file into a map of sections, each a map of
+// keys to values. Keys before the first section header belong to the
+// section named "". If a key is repeated within a section, the last
+// value wins. See ParseINIOrdered for the syntax.
+func ParseINI(r io.Reader) (map[string]map[string]string, error) {
+	sections, err := ParseINIOrdered(r)
+	if err != nil {
+		return nil, err
+	}
+
+	ini := make(map[string]map[string]string, len(sections))
+	for _, section := range sections {
+		values := GetOrInsertFunc(ini, section.Name, func() map[string]string {
+			return make(map[string]string, len(section.Params))
+		})
+		for _, p := range section.Params {
+			values[p.Key] = p.Value
+		}
+	}
+
+	return ini, nil
+}
+
+// ParseINIOrdered parses an INI file into its sections, keeping the
+// order of sections and keys as well as repeated keys. Sections that
+// are declared more than once are merged into the first.
+//
+// Section headers are written as [name], and settings as key = value.
+// Comments start with '#' or ';', either at the start of a line or
+// after whitespace. Keys and values may be quoted, following the
+// quoting rules of Fields, and quoted values may span several lines.
+// A line ending in a backslash continues on the next line. Returns an
+// error with the line number for malformed lines.
+func ParseINIOrdered(r io.Reader) ([]INISection, error) {
+	sections := []INISection{{Name: ""}}
+	index := map[string]int{"": 0}
+	current := 0
+
+	err := readLogicalLines(r, "#;", func(lineno int, text, _ string) error {
+		text = strings.TrimSpace(stripComment(text, "#;"))
+		if text == "" {
+			return nil
+		}
+
+		if strings.HasPrefix(text, "[") {
+			if !strings.HasSuffix(text, "]") {
+				return fmt.Errorf("line %d: unterminated section header", lineno)
+			}
+			name := decodeValue(text[1 : len(text)-1])
+			i, ok := index[name]
+			if !ok {
+				i = len(sections)
+				index[name] = i
+				sections = append(sections, INISection{Name: name})
+			}
+			current = i
+			return nil
+		}
+
+		key, value, ok := cutUnquoted(text, '=')
+		if !ok {
+			return fmt.Errorf("line %d: expected key = value", lineno)
+		}
+		if key = decodeValue(key); key == "" {
+			return fmt.Errorf("line %d: missing key", lineno)
+		}
+		sections[current].Params = append(sections[current].Params, Param{Key: key, Value: decodeValue(value)})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sections[0].Params) == 0 {
+		sections = sections[1:]
+	}
+
+	return sections, nil
+}
@@ -0,0 +1,80 @@
+package gobag
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testINI = `; global settings
+name = demo
+
+[server]
+# it's a comment with a quote
+host = example.com  ; inline comment
+port=8080
+motd = "Welcome,
+  friend"
+path = /usr/local/\
+       bin
+tags = a
+tags = b
+
+[ "client side" ]
+url = 'http://x/#frag'
+[server]
+timeout = 5s
+`
+
+func TestParseINIOrdered(t *testing.T) {
+	sections, err := ParseINIOrdered(strings.NewReader(testINI))
+	if err != nil {
+		t.Fatalf("ParseINIOrdered() error = %v", err)
+	}
+
+	want := []INISection{
+		{Name: "", Params: Params{{"name", "demo"}}},
+		{Name: "server", Params: Params{
+			{"host", "example.com"},
+			{"port", "8080"},
+			{"motd", "Welcome,\n  friend"},
+			{"path", "/usr/local/bin"},
+			{"tags", "a"},
+			{"tags", "b"},
+			{"timeout", "5s"},
+		}},
+		{Name: "client side", Params: Params{{"url", "http://x/#frag"}}},
+	}
+	if !reflect.DeepEqual(sections, want) {
+		t.Errorf("ParseINIOrdered() = %q, want %q", sections, want)
+	}
+}
+
+func TestParseINI(t *testing.T) {
+	ini, err := ParseINI(strings.NewReader(testINI))
+	if err != nil {
+		t.Fatalf("ParseINI() error = %v", err)
+	}
+	if got := ini["server"]["tags"]; got != "b" {
+		t.Errorf(`ini["server"]["tags"] = %q, want "b"`, got)
+	}
+	if got := ini[""]["name"]; got != "demo" {
+		t.Errorf(`ini[""]["name"] = %q, want "demo"`, got)
+	}
+
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{"[server\nport=1", "line 1: unterminated section header"},
+		{"a=1\njunk", "line 2: expected key = value"},
+		{"= 1", "line 1: missing key"},
+		{"a=1\nb=\"open\n\n", "line 2: unbalanced double quote in string"},
+	}
+	for _, tt := range tests {
+		_, err := ParseINI(strings.NewReader(tt.input))
+		if err == nil || err.Error() != tt.err {
+			t.Errorf("ParseINI(%q) error = %v, want %q", tt.input, err, tt.err)
+		}
+	}
+}
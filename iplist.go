@@ -0,0 +1,127 @@
+package gobag
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// ParseIPList parses a comma separated list of IP addresses and CIDR
+// prefixes, such as "10.0.0.0/8, 192.168.1.5, fe80::/10". Entries may
+// be surrounded by whitespace and quoted, and empty entries are
+// ignored. Single addresses are returned as prefixes covering just
+// that address, and all prefixes are masked.
+func ParseIPList(s string) ([]netip.Prefix, error) {
+	entries, err := Fields(s, ',')
+	if err != nil {
+		return nil, err
+	}
+
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		if unquoted, err := UnquoteStringWith(e, UnquoteOptions{Strict: true, SingleQuote: true}); err == nil {
+			e = strings.TrimSpace(unquoted)
+		}
+		if e == "" {
+			continue
+		}
+
+		if strings.Contains(e, "/") {
+			p, err := netip.ParsePrefix(e)
+			if err != nil {
+				return nil, fmt.Errorf("invalid prefix %q: %w", e, err)
+			}
+			prefixes = append(prefixes, p.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(e)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %q: %w", e, err)
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+
+	return prefixes, nil
+}
+
+// IPMatcher reports whether addresses are covered by a set of
+// prefixes. It stores the prefixes in a binary trie, so lookups take
+// time proportional to the address length regardless of the number of
+// prefixes. The zero value matches nothing. An IPMatcher is not safe
+// for concurrent use while prefixes are being added.
+type IPMatcher struct {
+	v4, v6 ipNode
+}
+
+type ipNode struct {
+	children [2]*ipNode
+	terminal bool
+}
+
+// NewIPMatcher returns an IPMatcher for the given prefixes.
+func NewIPMatcher(prefixes ...netip.Prefix) *IPMatcher {
+	m := &IPMatcher{}
+	for _, p := range prefixes {
+		m.Add(p)
+	}
+	return m
+}
+
+// Add adds the prefix p to the matcher. IPv4-mapped IPv6 prefixes are
+// treated as IPv4 prefixes. Invalid prefixes are ignored.
+func (m *IPMatcher) Add(p netip.Prefix) {
+	if !p.IsValid() {
+		return
+	}
+	addr, bits := p.Addr(), p.Bits()
+	if addr.Is4In6() && bits >= 96 {
+		addr, bits = addr.Unmap(), bits-96
+	}
+
+	node := m.root(addr)
+	raw := addr.AsSlice()
+	for i := range bits {
+		if node.terminal {
+			// Already covered by a shorter prefix.
+			return
+		}
+		bit := raw[i/8] >> (7 - i%8) & 1
+		if node.children[bit] == nil {
+			node.children[bit] = &ipNode{}
+		}
+		node = node.children[bit]
+	}
+	node.terminal = true
+	// Longer prefixes below are now redundant.
+	node.children = [2]*ipNode{}
+}
+
+// Contains reports whether addr is covered by any of the prefixes.
+func (m *IPMatcher) Contains(addr netip.Addr) bool {
+	if !addr.IsValid() {
+		return false
+	}
+	addr = addr.Unmap()
+
+	node := m.root(addr)
+	raw := addr.AsSlice()
+	for i := range addr.BitLen() {
+		if node.terminal {
+			return true
+		}
+		node = node.children[raw[i/8]>>(7-i%8)&1]
+		if node == nil {
+			return false
+		}
+	}
+
+	return node.terminal
+}
+
+func (m *IPMatcher) root(addr netip.Addr) *ipNode {
+	if addr.Is4() {
+		return &m.v4
+	}
+	return &m.v6
+}
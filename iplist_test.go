@@ -0,0 +1,65 @@
+package gobag
+
+import (
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func TestParseIPList(t *testing.T) {
+	got, err := ParseIPList(`10.1.2.3/8, 192.168.1.5, "fe80::/10",, ::1`)
+	if err != nil {
+		t.Fatalf("ParseIPList() error = %v", err)
+	}
+	want := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("192.168.1.5/32"),
+		netip.MustParsePrefix("fe80::/10"),
+		netip.MustParsePrefix("::1/128"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseIPList() = %v, want %v", got, want)
+	}
+
+	for _, bad := range []string{"10.0.0.0/33", "300.1.1.1", `"10.0.0.1`} {
+		if _, err := ParseIPList(bad); err == nil {
+			t.Errorf("ParseIPList(%q): expected error", bad)
+		}
+	}
+}
+
+func TestIPMatcher(t *testing.T) {
+	prefixes, err := ParseIPList("10.0.0.0/8, 192.168.1.5, fe80::/10, 10.1.0.0/16")
+	if err != nil {
+		t.Fatalf("ParseIPList() error = %v", err)
+	}
+	m := NewIPMatcher(prefixes...)
+
+	tests := []struct {
+		addr     string
+		expected bool
+	}{
+		{"10.200.3.4", true},
+		{"10.1.2.3", true},
+		{"11.0.0.1", false},
+		{"192.168.1.5", true},
+		{"192.168.1.6", false},
+		{"fe80::1", true},
+		{"fec0::1", false},
+		{"::ffff:10.0.0.1", true},
+	}
+	for _, tt := range tests {
+		if got := m.Contains(netip.MustParseAddr(tt.addr)); got != tt.expected {
+			t.Errorf("Contains(%s) = %t, want %t", tt.addr, got, tt.expected)
+		}
+	}
+
+	var empty IPMatcher
+	if empty.Contains(netip.MustParseAddr("10.0.0.1")) {
+		t.Error("zero IPMatcher matched an address")
+	}
+	all := NewIPMatcher(netip.MustParsePrefix("0.0.0.0/0"))
+	if !all.Contains(netip.MustParseAddr("1.2.3.4")) || all.Contains(netip.MustParseAddr("::1")) {
+		t.Error("0.0.0.0/0 matcher is wrong")
+	}
+}
@@ -0,0 +1,78 @@
+package gobag
+
+import (
+	"bufio"
+	"fmt"
+	"iter"
+	"path/filepath"
+	"strings"
+)
+
+// Line is a line of text read by IterLines.
+type Line struct {
+	Filename string
+	Lineno   int
+	Text     string
+}
+
+// String returns the line in the form "filename:lineno: text".
+func (l Line) String() string {
+	return fmt.Sprintf("%s:%d: %s", l.Filename, l.Lineno, l.Text)
+}
+
+// IterLines yields the lines of the files matching the patterns, in
+// the order of the patterns and, for each pattern, in lexical order of
+// the file names. Patterns use the syntax of filepath.Match, and a
+// pattern without wildcards names a single file. Files are opened
+// with OpenMaybeCompressed, so compressed files are read transparently.
+//
+// Errors, such as a pattern matching no files or a file that cannot be
+// read, are yielded with a Line holding the file name and the number
+// of the last line read, and iteration continues with the next file.
+func IterLines(patterns ...string) iter.Seq2[Line, error] {
+	return func(yield func(Line, error) bool) {
+		for _, pattern := range patterns {
+			names, err := filepath.Glob(pattern)
+			if err == nil && len(names) == 0 {
+				err = fmt.Errorf("%s: no such file", pattern)
+			}
+			if err != nil {
+				if !yield(Line{Filename: pattern}, err) {
+					return
+				}
+				continue
+			}
+
+			for _, name := range names {
+				if !iterFileLines(name, yield) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// iterFileLines yields the lines of the named file. It returns false
+// if the consumer stopped.
+func iterFileLines(name string, yield func(Line, error) bool) bool {
+	rc, err := OpenMaybeCompressed(name)
+	if err != nil {
+		return yield(Line{Filename: name}, err)
+	}
+	defer rc.Close()
+
+	scanner := bufio.NewScanner(rc)
+	scanner.Buffer(nil, 1<<24)
+	var lineno int
+	for scanner.Scan() {
+		lineno++
+		line := Line{Filename: name, Lineno: lineno, Text: strings.TrimSuffix(scanner.Text(), "\r")}
+		if !yield(line, nil) {
+			return false
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return yield(Line{Filename: name, Lineno: lineno}, fmt.Errorf("%s: %w", name, err))
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+package gobag
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestIterLines(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "b.log"), []byte("b1\nb2\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "a.log"), []byte("a1\r\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "c.txt"), []byte("c1"), 0o644)
+
+	var got []Line
+	var errs int
+	for line, err := range IterLines(filepath.Join(dir, "*.log"), filepath.Join(dir, "missing"), filepath.Join(dir, "c.txt")) {
+		if err != nil {
+			errs++
+			if line.Filename != filepath.Join(dir, "missing") {
+				t.Errorf("error reported for %q, want missing file", line.Filename)
+			}
+			continue
+		}
+		line.Filename = filepath.Base(line.Filename)
+		got = append(got, line)
+	}
+
+	want := []Line{
+		{"a.log", 1, "a1"},
+		{"b.log", 1, "b1"},
+		{"b.log", 2, "b2"},
+		{"c.txt", 1, "c1"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("IterLines() = %v, want %v", got, want)
+	}
+	if errs != 1 {
+		t.Errorf("IterLines() yielded %d errors, want 1", errs)
+	}
+
+	if s := want[1].String(); s != "b.log:1: b1" {
+		t.Errorf("Line.String() = %q", s)
+	}
+
+	for range IterLines(filepath.Join(dir, "*.log")) {
+		break
+	}
+}
@@ -0,0 +1,124 @@
+package gobag
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Layers resolves configuration values from layers of settings, such
+// as defaults, a configuration file, the environment and command line
+// flags, where each layer overrides the ones added before it. Keys are
+// compared after normalization, so "max-size" in a file, "MAX_SIZE" in
+// the environment and "maxSize" as a flag can all refer to the same
+// setting. The zero value is empty and compares keys with
+// NormalizeKey. Layers is not safe for concurrent modification.
+type Layers struct {
+	normalize func(string) string
+	layers    []configLayer
+}
+
+type configLayer struct {
+	name   string
+	values *FuzzyMap[string]
+}
+
+// NewLayers returns an empty Layers using the normalize function to
+// compare keys. A nil normalize uses NormalizeKey.
+func NewLayers(normalize func(string) string) *Layers {
+	return &Layers{normalize: normalize}
+}
+
+// Add adds a layer of values named name, taking precedence over all
+// layers added before.
+func (l *Layers) Add(name string, values map[string]string) {
+	layer := configLayer{name: name, values: NewFuzzyMap[string](l.normalize)}
+	for k, v := range values {
+		layer.values.Set(k, v)
+	}
+	l.layers = append(l.layers, layer)
+}
+
+// AddEnv adds a layer named name of the environment variables whose
+// name starts with prefix, keyed by their name without the prefix.
+// With prefix "APP_", the variable APP_MAX_SIZE sets the key
+// "MAX_SIZE", which NormalizeKey matches with "max-size".
+func (l *Layers) AddEnv(name, prefix string) {
+	values := make(map[string]string)
+	for _, kv := range os.Environ() {
+		k, v, _ := strings.Cut(kv, "=")
+		if key, ok := strings.CutPrefix(k, prefix); ok && key != "" {
+			values[key] = v
+		}
+	}
+	l.Add(name, values)
+}
+
+// Get returns the value of key from the topmost layer setting it, and
+// whether any layer does.
+func (l *Layers) Get(key string) (string, bool) {
+	i, v := l.resolve(key)
+	return v, i >= 0
+}
+
+// Origin returns the name of the layer the value of key comes from,
+// and whether any layer sets it.
+func (l *Layers) Origin(key string) (string, bool) {
+	if i, _ := l.resolve(key); i >= 0 {
+		return l.layers[i].name, true
+	}
+	return "", false
+}
+
+// resolve returns the index of the topmost layer setting key and its
+// value there, or -1 if no layer sets it.
+func (l *Layers) resolve(key string) (int, string) {
+	for i := len(l.layers) - 1; i >= 0; i-- {
+		if v, ok := l.layers[i].values.Get(key); ok {
+			return i, v
+		}
+	}
+	return -1, ""
+}
+
+// Map returns the resolved values of all keys. Each key is spelled as
+// in the lowest layer setting it, typically the defaults.
+func (l *Layers) Map() map[string]string {
+	m := make(map[string]string)
+	seen := NewFuzzyMap[bool](l.normalize)
+	for _, layer := range l.layers {
+		for _, k := range layer.values.Keys() {
+			if _, ok := seen.Get(k); ok {
+				continue
+			}
+			seen.Set(k, true)
+			m[k], _ = l.Get(k)
+		}
+	}
+	return m
+}
+
+// Explain returns a description of how the value of key is resolved:
+// the winning value and its layer, followed by every layer setting
+// the key from the top down, for example:
+//
+//	max-size = 64 (from flags)
+//	  flags: 64
+//	  env: 32 (overridden)
+//	  defaults: 16 (overridden)
+func (l *Layers) Explain(key string) string {
+	i, value := l.resolve(key)
+	if i < 0 {
+		return key + " is not set"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s = %s (from %s)", key, value, l.layers[i].name)
+	for j, layer := range slices.Backward(l.layers) {
+		if v, ok := layer.values.Get(key); ok {
+			fmt.Fprintf(&sb, "\n  %s: %s%s", layer.name, v, Ternary(j < i, " (overridden)", ""))
+		}
+	}
+	return sb.String()
+}
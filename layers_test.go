@@ -0,0 +1,62 @@
+package gobag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLayers(t *testing.T) {
+	t.Setenv("TESTAPP_MAX_SIZE", "32")
+	t.Setenv("TESTAPP_", "ignored")
+
+	var l Layers
+	l.Add("defaults", map[string]string{"max-size": "16", "host": "localhost", "debug": "false"})
+	l.Add("file", map[string]string{"host": "db1"})
+	l.AddEnv("env", "TESTAPP_")
+	l.Add("flags", map[string]string{"maxSize": "64"})
+
+	tests := []struct {
+		key, value, origin string
+	}{
+		{"max-size", "64", "flags"},
+		{"MAX_SIZE", "64", "flags"},
+		{"host", "db1", "file"},
+		{"debug", "false", "defaults"},
+	}
+	for _, tt := range tests {
+		if v, ok := l.Get(tt.key); !ok || v != tt.value {
+			t.Errorf("Get(%q) = %q, %v, want %q", tt.key, v, ok, tt.value)
+		}
+		if o, ok := l.Origin(tt.key); !ok || o != tt.origin {
+			t.Errorf("Origin(%q) = %q, %v, want %q", tt.key, o, ok, tt.origin)
+		}
+	}
+	if _, ok := l.Get("missing"); ok {
+		t.Error("Get(missing) found a value")
+	}
+
+	want := map[string]string{"max-size": "64", "host": "db1", "debug": "false"}
+	if got := l.Map(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Map() = %v, want %v", got, want)
+	}
+
+	explain := "max-size = 64 (from flags)\n" +
+		"  flags: 64\n" +
+		"  env: 32 (overridden)\n" +
+		"  defaults: 16 (overridden)"
+	if got := l.Explain("max-size"); got != explain {
+		t.Errorf("Explain() =\n%s\nwant\n%s", got, explain)
+	}
+	if got := l.Explain("nope"); got != "nope is not set" {
+		t.Errorf("Explain(nope) = %q", got)
+	}
+}
+
+func TestLayersNormalize(t *testing.T) {
+	l := NewLayers(func(s string) string { return s })
+	l.Add("a", map[string]string{"Key": "1"})
+	l.Add("b", map[string]string{"key": "2"})
+	if v, _ := l.Get("Key"); v != "1" {
+		t.Errorf("Get(Key) = %q, want %q", v, "1")
+	}
+}
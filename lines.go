@@ -0,0 +1,120 @@
+package gobag
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// readLogicalLines reads r line by line and calls fn with every
+// logical line, the number of its first physical line, and its raw
+// physical lines joined by newlines, including any carriage returns.
+// Blank lines and lines whose first non-blank character is in comment
+// are passed with an empty text. A line ending in a backslash
+// continues on the next line, with the backslash, the newline and the
+// indentation of the next line removed, and a line with an open quote
+// or parenthesis continues until it is closed, keeping the newlines.
+// Returns an error if the input ends with an open quote or
+// parenthesis, or if fn fails.
+func readLogicalLines(r io.Reader, comment string, fn func(lineno int, text, raw string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<24)
+	scanner.Split(scanRawLines)
+
+	var pending, raw strings.Builder
+	var state quoteState // State at the end of pending.
+	var start, lineno int
+	var active, continued bool
+	for scanner.Scan() {
+		lineno++
+		physical := scanner.Text()
+		line := strings.TrimSuffix(physical, "\r")
+
+		var text string // Text appended to the logical line.
+		switch {
+		case !active:
+			trimmed := strings.TrimSpace(line)
+			if trimmed == "" || strings.ContainsRune(comment, rune(trimmed[0])) {
+				if err := fn(lineno, "", physical); err != nil {
+					return err
+				}
+				continue
+			}
+			active, start = true, lineno
+			state = quoteState{}
+			raw.WriteString(physical)
+			text = line
+		case continued:
+			raw.WriteString("\n" + physical)
+			text = strings.TrimLeftFunc(line, unicode.IsSpace)
+		default:
+			raw.WriteString("\n" + physical)
+			text = "\n" + line
+		}
+
+		for _, r := range text {
+			state.next(r)
+		}
+		continued = state.escaped
+		if continued {
+			// Drop the backslash, which escapes nothing once the
+			// lines are joined.
+			state.escaped = false
+			pending.WriteString(text[:len(text)-1])
+			continue
+		}
+		pending.WriteString(text)
+		if state.inSingle || state.inDouble || state.balance > 0 {
+			continue
+		}
+
+		if err := fn(start, pending.String(), raw.String()); err != nil {
+			return err
+		}
+		pending.Reset()
+		raw.Reset()
+		active = false
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if active {
+		if continued {
+			return fn(start, pending.String(), raw.String())
+		}
+		return fmt.Errorf("line %d: %w", start, state.err())
+	}
+
+	return nil
+}
+
+// stripComment removes a trailing comment from s. A comment starts
+// with a character in comment that is outside quotes and parentheses,
+// and is at the start of s or preceded by whitespace.
+func stripComment(s, comment string) string {
+	var state quoteState
+	prevSpace := true
+	for i, r := range s {
+		if state.next(r) && prevSpace && strings.ContainsRune(comment, r) {
+			return s[:i]
+		}
+		prevSpace = unicode.IsSpace(r)
+	}
+	return s
+}
+
+// scanRawLines is like bufio.ScanLines, but keeps the carriage return
+// of lines ending in "\r\n".
+func scanRawLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
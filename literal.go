@@ -0,0 +1,162 @@
+package gobag
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ParseLiteral parses a small literal value, in the spirit of a
+// lenient JSON, into a Go value:
+//
+//   - null yields nil
+//   - true and false yield a bool
+//   - integers, including hexadecimal, octal and binary forms, yield an
+//     int64, and other numbers a float64
+//   - strings in double or single quotes yield a string, with the
+//     escape sequences of Go string literals decoded
+//   - lists in brackets or parentheses, with elements separated by
+//     commas and an optional trailing comma, yield a []any
+//
+// Returns an error with the offset of the first invalid input.
+func ParseLiteral(s string) (any, error) {
+	p := &literalParser{s: s}
+	v, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.s) {
+		return nil, p.errorf("unexpected %q after value", p.s[p.pos:])
+	}
+
+	return v, nil
+}
+
+type literalParser struct {
+	s   string
+	pos int
+}
+
+func (p *literalParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *literalParser) skipSpace() {
+	p.pos += len(p.s[p.pos:]) - len(strings.TrimLeftFunc(p.s[p.pos:], unicode.IsSpace))
+}
+
+func (p *literalParser) value() (any, error) {
+	p.skipSpace()
+	if p.pos >= len(p.s) {
+		return nil, p.errorf("missing value")
+	}
+
+	switch c := p.s[p.pos]; c {
+	case '[':
+		return p.list(']')
+	case '(':
+		return p.list(')')
+	case '"', '\'':
+		return p.quoted(c)
+	}
+
+	return p.scalar()
+}
+
+func (p *literalParser) list(closing byte) (any, error) {
+	p.pos++
+	list := make([]any, 0)
+	for {
+		p.skipSpace()
+		if p.pos < len(p.s) && p.s[p.pos] == closing {
+			p.pos++
+			return list, nil
+		}
+
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+
+		p.skipSpace()
+		switch {
+		case p.pos >= len(p.s):
+			return nil, p.errorf("unterminated list")
+		case p.s[p.pos] == ',':
+			p.pos++
+		case p.s[p.pos] != closing:
+			return nil, p.errorf("expected ',' or %q in list", closing)
+		}
+	}
+}
+
+func (p *literalParser) quoted(quote byte) (any, error) {
+	start := p.pos
+	p.pos++
+
+	var sb strings.Builder
+	for {
+		if p.pos >= len(p.s) {
+			p.pos = start
+			return nil, p.errorf("unterminated string")
+		}
+		if p.s[p.pos] == quote {
+			p.pos++
+			return sb.String(), nil
+		}
+		if p.s[p.pos] == '\\' && p.pos+1 < len(p.s) && p.s[p.pos+1] == quote {
+			// Allow escaping the quote in use, e.g. 'it\'s'.
+			sb.WriteByte(quote)
+			p.pos += 2
+			continue
+		}
+
+		value, multibyte, tail, err := strconv.UnquoteChar(p.s[p.pos:], quote)
+		if err != nil {
+			return nil, p.errorf("invalid escape sequence in string")
+		}
+		if multibyte {
+			sb.WriteRune(value)
+		} else {
+			sb.WriteByte(byte(value))
+		}
+		p.pos = len(p.s) - len(tail)
+	}
+}
+
+func (p *literalParser) scalar() (any, error) {
+	start := p.pos
+	for p.pos < len(p.s) {
+		r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+		if unicode.IsSpace(r) || strings.ContainsRune(",[]()\"'", r) {
+			break
+		}
+		p.pos += size
+	}
+
+	tok := p.s[start:p.pos]
+	switch tok {
+	case "null":
+		return nil, nil
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	if n, err := strconv.ParseInt(tok, 0, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(tok, 64); err == nil && !strings.ContainsAny(tok, "nN") {
+		return f, nil
+	}
+
+	p.pos = start
+	if tok == "" {
+		return nil, p.errorf("unexpected %q", p.s[p.pos:p.pos+1])
+	}
+	return nil, p.errorf("invalid literal %q", tok)
+}
@@ -0,0 +1,54 @@
+package gobag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLiteral(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{"null", nil},
+		{" true ", true},
+		{"false", false},
+		{"42", int64(42)},
+		{"-0x1f", int64(-31)},
+		{"1_000", int64(1000)},
+		{"3.5e2", 350.0},
+		{`"a\tbå"`, "a\tbå"},
+		{`'it\'s'`, "it's"},
+		{`'say "hi"'`, `say "hi"`},
+		{"[]", []any{}},
+		{"[1, 'two', [3.0, null], (true,),]", []any{int64(1), "two", []any{3.0, nil}, []any{true}}},
+	}
+	for _, tt := range tests {
+		got, err := ParseLiteral(tt.input)
+		if err != nil || !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("ParseLiteral(%q) = %#v, %v, want %#v", tt.input, got, err, tt.expected)
+		}
+	}
+
+	errors := []struct {
+		input string
+		err   string
+	}{
+		{"", "offset 0: missing value"},
+		{"yes", `offset 0: invalid literal "yes"`},
+		{"NaN", `offset 0: invalid literal "NaN"`},
+		{"[1 2]", `offset 3: expected ',' or ']' in list`},
+		{"[1,", "offset 3: missing value"},
+		{"[1", "offset 2: unterminated list"},
+		{`"abc`, "offset 0: unterminated string"},
+		{`1 2`, `offset 2: unexpected "2" after value`},
+		{`[,]`, `offset 1: unexpected ","`},
+		{`"\q"`, "offset 1: invalid escape sequence in string"},
+	}
+	for _, tt := range errors {
+		_, err := ParseLiteral(tt.input)
+		if err == nil || err.Error() != tt.err {
+			t.Errorf("ParseLiteral(%q) error = %v, want %q", tt.input, err, tt.err)
+		}
+	}
+}
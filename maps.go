@@ -0,0 +1,87 @@
+package gobag
+
+// GetOrInsert returns the value stored under k in m. If k is not
+// present, def is inserted and returned.
+func GetOrInsert[K comparable, V any](m map[K]V, k K, def V) V {
+	if v, ok := m[k]; ok {
+		return v
+	}
+	m[k] = def
+	return def
+}
+
+// GetOrInsertFunc is like GetOrInsert, but only calls fn to create
+// the value when k is not present.
+func GetOrInsertFunc[K comparable, V any](m map[K]V, k K, fn func() V) V {
+	if v, ok := m[k]; ok {
+		return v
+	}
+	v := fn()
+	m[k] = v
+	return v
+}
+
+// SetDefaults inserts every key of defaults that is not already
+// present in dst, leaving existing values untouched.
+func SetDefaults[K comparable, V any](dst, defaults map[K]V) {
+	for k, v := range defaults {
+		if _, ok := dst[k]; !ok {
+			dst[k] = v
+		}
+	}
+}
+
+// Get2 returns the value stored under k1 and k2 in the two-level map
+// m, and whether it was found.
+func Get2[K1, K2 comparable, V any](m map[K1]map[K2]V, k1 K1, k2 K2) (V, bool) {
+	v, ok := m[k1][k2]
+	return v, ok
+}
+
+// Set2 stores v under k1 and k2 in the two-level map m, creating the
+// inner map if needed. The outer map must not be nil.
+func Set2[K1, K2 comparable, V any](m map[K1]map[K2]V, k1 K1, k2 K2, v V) {
+	inner, ok := m[k1]
+	if !ok {
+		inner = make(map[K2]V)
+		m[k1] = inner
+	}
+	inner[k2] = v
+}
+
+// Delete2 removes the value stored under k1 and k2 in the two-level
+// map m, and removes the inner map if it becomes empty.
+func Delete2[K1, K2 comparable, V any](m map[K1]map[K2]V, k1 K1, k2 K2) {
+	inner, ok := m[k1]
+	if !ok {
+		return
+	}
+	delete(inner, k2)
+	if len(inner) == 0 {
+		delete(m, k1)
+	}
+}
+
+// Entry2 is a single value of a two-level map along with its keys.
+type Entry2[K1, K2 comparable, V any] struct {
+	Key1  K1
+	Key2  K2
+	Value V
+}
+
+// Flatten2 returns all values of the two-level map m as entries.
+// The order of entries is not guaranteed.
+func Flatten2[K1, K2 comparable, V any](m map[K1]map[K2]V) []Entry2[K1, K2, V] {
+	var n int
+	for _, inner := range m {
+		n += len(inner)
+	}
+
+	entries := make([]Entry2[K1, K2, V], 0, n)
+	for k1, inner := range m {
+		for k2, v := range inner {
+			entries = append(entries, Entry2[K1, K2, V]{Key1: k1, Key2: k2, Value: v})
+		}
+	}
+	return entries
+}
@@ -0,0 +1,63 @@
+package gobag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetOrInsert(t *testing.T) {
+	m := map[string]int{"a": 1}
+	if v := GetOrInsert(m, "a", 5); v != 1 {
+		t.Errorf(`GetOrInsert("a") = %d, want 1`, v)
+	}
+	if v := GetOrInsert(m, "b", 5); v != 5 || m["b"] != 5 {
+		t.Errorf(`GetOrInsert("b") = %d, map %v, want 5 inserted`, v, m)
+	}
+
+	calls := 0
+	fn := func() []string { calls++; return []string{} }
+	groups := map[string][]string{}
+	groups["x"] = append(GetOrInsertFunc(groups, "x", fn), "1")
+	groups["x"] = append(GetOrInsertFunc(groups, "x", fn), "2")
+	if calls != 1 || !reflect.DeepEqual(groups["x"], []string{"1", "2"}) {
+		t.Errorf("GetOrInsertFunc() calls = %d, groups = %v", calls, groups)
+	}
+}
+
+func TestSetDefaults(t *testing.T) {
+	opts := map[string]string{"port": "8080"}
+	SetDefaults(opts, map[string]string{"port": "80", "host": "localhost"})
+	want := map[string]string{"port": "8080", "host": "localhost"}
+	if !reflect.DeepEqual(opts, want) {
+		t.Errorf("SetDefaults() = %v, want %v", opts, want)
+	}
+}
+
+func TestTwoLevelMap(t *testing.T) {
+	m := map[string]map[string]int{}
+	Set2(m, "eth0", "mtu", 1500)
+	Set2(m, "eth0", "metric", 10)
+	Set2(m, "eth1", "mtu", 9000)
+
+	if v, ok := Get2(m, "eth0", "mtu"); !ok || v != 1500 {
+		t.Errorf(`Get2("eth0", "mtu") = %d, %t, want 1500, true`, v, ok)
+	}
+	if _, ok := Get2(m, "eth2", "mtu"); ok {
+		t.Error(`Get2("eth2", "mtu") found a value`)
+	}
+
+	if got := len(Flatten2(m)); got != 3 {
+		t.Errorf("len(Flatten2()) = %d, want 3", got)
+	}
+
+	Delete2(m, "eth1", "mtu")
+	Delete2(m, "eth0", "mtu")
+	Delete2(m, "eth9", "mtu")
+	want := map[string]map[string]int{"eth0": {"metric": 10}}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("after Delete2 = %v, want %v", m, want)
+	}
+	if got := Flatten2(m); !reflect.DeepEqual(got, []Entry2[string, string, int]{{"eth0", "metric", 10}}) {
+		t.Errorf("Flatten2() = %v", got)
+	}
+}
@@ -0,0 +1,39 @@
+package gobag
+
+import "strings"
+
+// Mask is the replacement MaskFields uses for secret values.
+const Mask = "***"
+
+// MaskFields returns s with the values of key=value fields whose key
+// matches one of keys, compared case-insensitively, replaced by Mask.
+// Everything else, including spacing and the quote style of masked
+// values, is left untouched, making the result safe for logging.
+// Returns an error if quotes or parentheses are unbalanced.
+func MaskFields(s string, sep rune, keys []string) (string, error) {
+	fields, err := splitRaw(s, sep)
+	if err != nil {
+		return "", err
+	}
+
+	for i, f := range fields {
+		key, value, ok := cutUnquoted(f, '=')
+		if !ok || !matchesKey(key, keys) {
+			continue
+		}
+		fields[i] = key + "=" + spliceValue(value, Mask, Mask)
+	}
+
+	return strings.Join(fields, string(sep)), nil
+}
+
+func matchesKey(key string, keys []string) bool {
+	key = decodeValue(key)
+	for _, k := range keys {
+		if strings.EqualFold(key, k) {
+			return true
+		}
+	}
+
+	return false
+}
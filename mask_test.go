@@ -0,0 +1,28 @@
+package gobag
+
+import "testing"
+
+func TestMaskFields(t *testing.T) {
+	tests := []struct {
+		input    string
+		sep      rune
+		expected string
+	}{
+		{`user=bob password=secret`, ' ', `user=bob password=***`},
+		{`user=bob, Password = "s3 cr,et" , host=x`, ',', `user=bob, Password = "***" , host=x`},
+		{`token='abc' other=(password=x)`, ' ', `token='***' other=(password=x)`},
+		{`"password"=x`, ' ', `"password"=***`},
+		{`nothing here`, ' ', `nothing here`},
+		{`password`, ' ', `password`},
+	}
+	for _, tt := range tests {
+		got, err := MaskFields(tt.input, tt.sep, []string{"password", "token"})
+		if err != nil || got != tt.expected {
+			t.Errorf("MaskFields(%q) = %q, %v, want %q, nil", tt.input, got, err, tt.expected)
+		}
+	}
+
+	if _, err := MaskFields(`password="x`, ' ', []string{"password"}); err == nil {
+		t.Error("MaskFields() with unbalanced quote: expected error")
+	}
+}
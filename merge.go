@@ -0,0 +1,97 @@
+package gobag
+
+import (
+	"container/heap"
+	"iter"
+)
+
+// MergeSorted merges slices that are each sorted according to less
+// into a single sorted slice. Equal elements keep their relative order,
+// with those of earlier slices first. It runs in O(n log k) time for n
+// elements in k slices.
+func MergeSorted[T any](less func(a, b T) bool, slices ...[]T) []T {
+	var n int
+	seqs := make([]iter.Seq[T], len(slices))
+	for i, s := range slices {
+		n += len(s)
+		seqs[i] = func(yield func(T) bool) {
+			for _, v := range s {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+
+	merged := make([]T, 0, n)
+	for v := range MergeSortedSeq(less, seqs...) {
+		merged = append(merged, v)
+	}
+	return merged
+}
+
+// MergeSortedSeq is like MergeSorted, but merges sequences lazily, so
+// inputs such as the records of large sorted files need not be held in
+// memory.
+func MergeSortedSeq[T any](less func(a, b T) bool, seqs ...iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		h := &mergeHeap[T]{less: less}
+		for i, seq := range seqs {
+			next, stop := iter.Pull(seq)
+			defer stop()
+			if v, ok := next(); ok {
+				h.items = append(h.items, mergeItem[T]{v, i, next})
+			}
+		}
+		heap.Init(h)
+
+		for len(h.items) > 0 {
+			top := &h.items[0]
+			if !yield(top.value) {
+				return
+			}
+			if v, ok := top.next(); ok {
+				top.value = v
+				heap.Fix(h, 0)
+			} else {
+				heap.Pop(h)
+			}
+		}
+	}
+}
+
+type mergeItem[T any] struct {
+	value T
+	index int
+	next  func() (T, bool)
+}
+
+// mergeHeap orders the current head of each input, breaking ties by
+// input index to keep the merge stable.
+type mergeHeap[T any] struct {
+	items []mergeItem[T]
+	less  func(a, b T) bool
+}
+
+func (h *mergeHeap[T]) Len() int { return len(h.items) }
+
+func (h *mergeHeap[T]) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	if h.less(a.value, b.value) {
+		return true
+	}
+	if h.less(b.value, a.value) {
+		return false
+	}
+	return a.index < b.index
+}
+
+func (h *mergeHeap[T]) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *mergeHeap[T]) Push(x any) { h.items = append(h.items, x.(mergeItem[T])) }
+
+func (h *mergeHeap[T]) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
@@ -0,0 +1,63 @@
+package gobag
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestMergeSorted(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+
+	tests := []struct {
+		input    [][]int
+		expected []int
+	}{
+		{nil, []int{}},
+		{[][]int{{}, {}}, []int{}},
+		{[][]int{{1, 4, 7}}, []int{1, 4, 7}},
+		{[][]int{{1, 4, 7}, {2, 5, 8}, {0, 3, 6, 9}}, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{[][]int{{5}, {}, {1, 1, 9}}, []int{1, 1, 5, 9}},
+	}
+	for _, tt := range tests {
+		if got := MergeSorted(less, tt.input...); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("MergeSorted(%v) = %v, want %v", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestMergeSortedStable(t *testing.T) {
+	type rec struct {
+		key, src string
+	}
+	less := func(a, b rec) bool { return a.key < b.key }
+	got := MergeSorted(less,
+		[]rec{{"a", "1"}, {"b", "1"}},
+		[]rec{{"a", "2"}, {"b", "2"}},
+		[]rec{{"a", "3"}},
+	)
+	want := []rec{{"a", "1"}, {"a", "2"}, {"a", "3"}, {"b", "1"}, {"b", "2"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeSorted() = %v, want %v", got, want)
+	}
+}
+
+func TestMergeSortedSeq(t *testing.T) {
+	less := func(a, b string) bool { return a < b }
+	seq := MergeSortedSeq(less,
+		slices.Values([]string{"apple", "cherry"}),
+		slices.Values([]string{"banana", "date"}),
+	)
+
+	var got []string
+	for v := range seq {
+		got = append(got, v)
+		if len(got) == 3 {
+			break
+		}
+	}
+	want := []string{"apple", "banana", "cherry"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeSortedSeq() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,161 @@
+package gobag
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NormalizeOptions holds optional settings for NormalizeWith.
+type NormalizeOptions struct {
+	// Sort sorts the normalized fields.
+	Sort bool
+}
+
+// Normalize re-parses a line of fields separated by sep and re-emits
+// it in canonical form: surrounding whitespace is removed from each
+// field, key=value fields lose the spacing around the equals sign, and
+// values are quoted only when needed. Lines that only differ in such
+// details normalize to the same string. Parenthesized groups are kept
+// verbatim. Returns an error if quotes or parentheses are unbalanced.
+func Normalize(s string, sep rune) (string, error) {
+	return NormalizeWith(s, sep, NormalizeOptions{})
+}
+
+// NormalizeWith is like Normalize, but takes options altering its
+// behavior.
+func NormalizeWith(s string, sep rune, opts NormalizeOptions) (string, error) {
+	fields, err := normalizedFields(s, sep)
+	if err != nil {
+		return "", err
+	}
+	if opts.Sort {
+		slices.Sort(fields)
+	}
+
+	return strings.Join(fields, string(sep)), nil
+}
+
+// normalizedFields splits s like Normalize and returns the normalized
+// fields.
+func normalizedFields(s string, sep rune) ([]string, error) {
+	raw, err := splitRaw(s, sep)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(raw))
+	for _, f := range raw {
+		f = trimField(f)
+		if f == "" && unicode.IsSpace(sep) {
+			// Runs of whitespace separators.
+			continue
+		}
+		fields = append(fields, normalizeField(f, sep))
+	}
+
+	return fields, nil
+}
+
+// normalizeField returns the canonical form of a single trimmed field.
+func normalizeField(f string, sep rune) string {
+	if strings.HasPrefix(f, "(") {
+		return f
+	}
+	if key, value, ok := cutUnquoted(f, '='); ok {
+		key, value = trimField(key), trimField(value)
+		return normalizeValue(key, sep, true) + "=" + normalizeValue(value, sep, false)
+	}
+
+	return normalizeValue(f, sep, true)
+}
+
+// normalizeValue returns the canonical form of a trimmed positional
+// field, key or value. Unless it is a value, text containing '=' is
+// quoted so it is not read back as a key=value field.
+func normalizeValue(v string, sep rune, quoteEquals bool) string {
+	if strings.HasPrefix(v, "(") {
+		return v
+	}
+	if u, err := UnquoteStringWith(v, UnquoteOptions{Strict: true, SingleQuote: true}); err == nil {
+		v = u
+	} else {
+		v = unescape(v)
+	}
+	if quoteEquals && strings.ContainsRune(v, '=') {
+		return QuoteString(v)
+	}
+
+	return quoteIfNeeded(v, sep)
+}
+
+// trimField removes surrounding whitespace from the raw field f,
+// keeping trailing whitespace that is escaped.
+func trimField(f string) string {
+	f = strings.TrimLeftFunc(f, unicode.IsSpace)
+	trimmed := strings.TrimRightFunc(f, unicode.IsSpace)
+	if len(trimmed) < len(f) && (len(trimmed)-len(strings.TrimRight(trimmed, `\`)))%2 == 1 {
+		_, size := utf8.DecodeRuneInString(f[len(trimmed):])
+		trimmed = f[:len(trimmed)+size]
+	}
+	return trimmed
+}
+
+// CollapseSpaces replaces runs of whitespace in s by a single space
+// and removes leading and trailing whitespace, leaving quoted text and
+// escaped characters untouched. An unterminated quote extends to the
+// end of s.
+func CollapseSpaces(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	var state quoteState
+	space := false
+	for _, r := range s {
+		literal := state.inSingle || state.inDouble || state.escaped
+		state.next(r)
+		if !literal && unicode.IsSpace(r) {
+			space = true
+			continue
+		}
+		if space && sb.Len() > 0 {
+			sb.WriteByte(' ')
+		}
+		space = false
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
+
+// cutUnquoted slices s around the first instance of sep that is not
+// within quotes or parentheses, and not escaped.
+func cutUnquoted(s string, sep rune) (before, after string, found bool) {
+	var state quoteState
+	for i, r := range s {
+		if state.next(r) && r == sep {
+			return s[:i], s[i+len(string(sep)):], true
+		}
+	}
+
+	return s, "", false
+}
+
+// quoteIfNeeded returns v quoted with QuoteString if it would not
+// otherwise survive being split by Fields with sep and trimmed.
+func quoteIfNeeded(v string, sep rune) string {
+	if v == "" {
+		if unicode.IsSpace(sep) {
+			return `""`
+		}
+		return v
+	}
+	if strings.TrimSpace(v) != v || strings.ContainsAny(v, `"'()\`) || strings.ContainsRune(v, sep) {
+		return QuoteString(v)
+	}
+	if unicode.IsSpace(sep) && strings.IndexFunc(v, unicode.IsSpace) >= 0 {
+		return QuoteString(v)
+	}
+
+	return v
+}
@@ -0,0 +1,105 @@
+package gobag
+
+import (
+	"errors"
+	"testing"
+	"testing/quick"
+
+	"github.com/stianwa/gobag/gobagtest/genrand"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		input    string
+		sep      rune
+		expected string
+		err      error
+	}{
+		{` a , b ,c`, ',', `a,b,c`, nil},
+		{`"a",'b',"c d"`, ',', `a,b,c d`, nil},
+		{`"a,b", c`, ',', `"a,b",c`, nil},
+		{`key = "value" , other='x y'`, ',', `key=value,other=x y`, nil},
+		{`a\,b,c`, ',', `"a,b",c`, nil},
+		{`x   y  "z w"`, ' ', `x y "z w"`, nil},
+		{`"" x`, ' ', `"" x`, nil},
+		{`a, (b , c) ,d`, ',', `a,(b , c),d`, nil},
+		{`"say \"hi\""`, ',', `"say \"hi\""`, nil},
+		{`"a=b",c`, ',', `"a=b",c`, nil},
+		{`a\=b,c`, ',', `"a=b",c`, nil},
+		{`k="a=b", k = a=b`, ',', `k=a=b,k=a=b`, nil},
+		{`"a=b" = 1`, ',', `"a=b"=1`, nil},
+		{`a\ , k=b\ `, ',', `"a ",k="b "`, nil},
+		{`a,"b`, ',', ``, errors.New("unbalanced double quote in string")},
+	}
+	for _, tt := range tests {
+		got, err := Normalize(tt.input, tt.sep)
+		if tt.err != nil {
+			if err == nil || err.Error() != tt.err.Error() {
+				t.Errorf("Normalize(%q) error = %v, want %v", tt.input, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.expected {
+			t.Errorf("Normalize(%q) = %q, %v, want %q, nil", tt.input, got, err, tt.expected)
+			continue
+		}
+		if again, _ := Normalize(got, tt.sep); again != got {
+			t.Errorf("Normalize(%q) = %q, not idempotent", got, again)
+		}
+	}
+
+	got, err := NormalizeWith("c=3, a=1, b=2", ',', NormalizeOptions{Sort: true})
+	if err != nil || got != "a=1,b=2,c=3" {
+		t.Errorf("NormalizeWith(Sort) = %q, %v, want %q, nil", got, err, "a=1,b=2,c=3")
+	}
+}
+
+func TestCollapseSpaces(t *testing.T) {
+	tests := []struct {
+		input, expected string
+	}{
+		{"", ""},
+		{"   ", ""},
+		{"  a \t b\n\nc  ", "a b c"},
+		{`key =  "two  spaces"   x`, `key = "two  spaces" x`},
+		{`'single\t quoted '  and  "double"`, `'single\t quoted ' and "double"`},
+		{`it\'s   fine`, `it\'s fine`},
+		{`a\  b`, `a\  b`},
+		{`a  "unterminated   quote  `, `a "unterminated   quote  `},
+		{"(a,   b)", "(a, b)"},
+	}
+	for _, tt := range tests {
+		if got := CollapseSpaces(tt.input); got != tt.expected {
+			t.Errorf("CollapseSpaces(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestQuoteString(t *testing.T) {
+	for _, s := range []string{"", "foo", `a"b`, `c:\dir\`, "æøå"} {
+		q := QuoteString(s)
+		got, err := UnquoteString(q)
+		if err != nil || got != s {
+			t.Errorf("UnquoteString(QuoteString(%q)) = %q, %v", s, got, err)
+		}
+	}
+}
+
+func TestNormalizeIdempotent(t *testing.T) {
+	normalizeTwice := func(s genrand.Valid) bool {
+		once, err := Normalize(string(s), ',')
+		if err != nil {
+			t.Logf("Normalize(%q) error = %v", s, err)
+			return false
+		}
+		twice, err := Normalize(once, ',')
+		if err != nil || twice != once {
+			t.Logf("Normalize(%q) = %q, normalized again = %q, %v", s, once, twice, err)
+			return false
+		}
+		return true
+	}
+	if err := quick.Check(normalizeTwice, &quick.Config{MaxCount: 5000}); err != nil {
+		t.Error(err)
+	}
+}
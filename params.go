@@ -0,0 +1,117 @@
+package gobag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Param is a single key and value of Params.
+type Param struct {
+	Key, Value string
+}
+
+// Params is an ordered list of parameters, as parsed by ParseParams.
+// Unlike url.Values it keeps the original order of all parameters,
+// including repeated keys.
+type Params []Param
+
+// ParseParams parses parameters separated by sep, each of the form
+// key, assign and value, such as "host=db port=5432 opt=a opt=b" with
+// sep ' ' and assign '='. Keys and values may be surrounded by
+// whitespace and quoted, and quoted values may contain sep. A
+// parameter without assign gets an empty value, and empty parameters
+// are ignored. Returns an error for empty keys, or if quotes or
+// parentheses are unbalanced.
+func ParseParams(s string, sep, assign rune) (Params, error) {
+	fields, err := splitRaw(s, sep)
+	if err != nil {
+		return nil, err
+	}
+
+	params := make(Params, 0, len(fields))
+	for _, f := range fields {
+		if strings.TrimSpace(f) == "" {
+			continue
+		}
+		key, value, _ := cutUnquoted(f, assign)
+		p := Param{Key: decodeValue(key), Value: decodeValue(value)}
+		if p.Key == "" {
+			return nil, fmt.Errorf("missing key in parameter %q", strings.TrimSpace(f))
+		}
+		params = append(params, p)
+	}
+
+	return params, nil
+}
+
+// Get returns the first value of key, or an empty string if key is
+// not present.
+func (p Params) Get(key string) string {
+	v, _ := p.Lookup(key)
+	return v
+}
+
+// Lookup returns the first value of key, and whether key is present.
+func (p Params) Lookup(key string) (string, bool) {
+	for _, param := range p {
+		if param.Key == key {
+			return param.Value, true
+		}
+	}
+	return "", false
+}
+
+// Values returns all values of key in order.
+func (p Params) Values(key string) []string {
+	values := make([]string, 0)
+	for _, param := range p {
+		if param.Key == key {
+			values = append(values, param.Value)
+		}
+	}
+	return values
+}
+
+// Has reports whether key is present.
+func (p Params) Has(key string) bool {
+	_, ok := p.Lookup(key)
+	return ok
+}
+
+// Keys returns the distinct keys in order of first appearance.
+func (p Params) Keys() []string {
+	keys := make([]string, 0, len(p))
+	for _, param := range p {
+		keys = append(keys, param.Key)
+	}
+	return Deduplicate(keys)
+}
+
+// ParseTagOptions parses the value of a struct tag in the common
+// form of a name followed by comma separated options, where options
+// are either flags or key=value pairs: `name,omitempty,max=5`. Flags
+// get an empty value in opts. The name, keys and values may be quoted
+// to include commas, as in `name,pattern='a,b'`. Returns an error if
+// quotes or parentheses are unbalanced, or if an option has an empty
+// key.
+func ParseTagOptions(tag string) (name string, opts map[string]string, err error) {
+	parts, err := splitRaw(tag, ',')
+	if err != nil {
+		return "", nil, err
+	}
+
+	name = decodeValue(parts[0])
+	opts = make(map[string]string, len(parts)-1)
+	for _, p := range parts[1:] {
+		if strings.TrimSpace(p) == "" {
+			continue
+		}
+		key, value, _ := cutUnquoted(p, '=')
+		if key = decodeValue(key); key == "" {
+			return "", nil, fmt.Errorf("missing key in tag option %q", strings.TrimSpace(p))
+		}
+		opts[key] = decodeValue(value)
+	}
+
+	return name, opts, nil
+}
@@ -0,0 +1,74 @@
+package gobag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseParams(t *testing.T) {
+	p, err := ParseParams(`host=db  port=5432 opt=a opt="b c" flag 'app name'='my app'`, ' ', '=')
+	if err != nil {
+		t.Fatalf("ParseParams() error = %v", err)
+	}
+	want := Params{
+		{"host", "db"},
+		{"port", "5432"},
+		{"opt", "a"},
+		{"opt", "b c"},
+		{"flag", ""},
+		{"app name", "my app"},
+	}
+	if !reflect.DeepEqual(p, want) {
+		t.Fatalf("ParseParams() = %q, want %q", p, want)
+	}
+
+	if v := p.Get("opt"); v != "a" {
+		t.Errorf(`Get("opt") = %q, want "a"`, v)
+	}
+	if v := p.Values("opt"); !reflect.DeepEqual(v, []string{"a", "b c"}) {
+		t.Errorf(`Values("opt") = %q`, v)
+	}
+	if !p.Has("flag") || p.Has("missing") {
+		t.Error("Has() is wrong")
+	}
+	if k := p.Keys(); !reflect.DeepEqual(k, []string{"host", "port", "opt", "flag", "app name"}) {
+		t.Errorf("Keys() = %q", k)
+	}
+
+	p, err = ParseParams("a: 1; b: 'x;y'", ';', ':')
+	if err != nil || !reflect.DeepEqual(p, Params{{"a", "1"}, {"b", "x;y"}}) {
+		t.Errorf("ParseParams() with custom runes = %q, %v", p, err)
+	}
+
+	for _, bad := range []string{"=x", `a="b`} {
+		if _, err := ParseParams(bad, ' ', '='); err == nil {
+			t.Errorf("ParseParams(%q): expected error", bad)
+		}
+	}
+}
+
+func TestParseTagOptions(t *testing.T) {
+	tests := []struct {
+		tag  string
+		name string
+		opts map[string]string
+	}{
+		{"", "", map[string]string{}},
+		{"name", "name", map[string]string{}},
+		{"name,omitempty,max=5", "name", map[string]string{"omitempty": "", "max": "5"}},
+		{",omitempty", "", map[string]string{"omitempty": ""}},
+		{`id, pattern='a,b', default="x y"`, "id", map[string]string{"pattern": "a,b", "default": "x y"}},
+	}
+	for _, tt := range tests {
+		name, opts, err := ParseTagOptions(tt.tag)
+		if err != nil || name != tt.name || !reflect.DeepEqual(opts, tt.opts) {
+			t.Errorf("ParseTagOptions(%q) = %q, %v, %v, want %q, %v", tt.tag, name, opts, err, tt.name, tt.opts)
+		}
+	}
+
+	for _, bad := range []string{`name,max="5`, "name,=5"} {
+		if _, _, err := ParseTagOptions(bad); err == nil {
+			t.Errorf("ParseTagOptions(%q): expected error", bad)
+		}
+	}
+}
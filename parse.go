@@ -0,0 +1,28 @@
+package gobag
+
+import (
+	"errors"
+	"fmt"
+)
+
+// FirstParse tries each parser on s in turn and returns the result of
+// the first one that succeeds. If all parsers fail, the returned error
+// joins the errors of every parser. Returns an error if no parsers are
+// given.
+func FirstParse[T any](s string, parsers ...func(string) (T, error)) (T, error) {
+	var zero T
+	if len(parsers) == 0 {
+		return zero, errors.New("no parsers given")
+	}
+
+	errs := make([]error, 0, len(parsers))
+	for _, parse := range parsers {
+		v, err := parse(s)
+		if err == nil {
+			return v, nil
+		}
+		errs = append(errs, err)
+	}
+
+	return zero, fmt.Errorf("unable to parse %q: %w", s, errors.Join(errs...))
+}
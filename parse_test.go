@@ -0,0 +1,39 @@
+package gobag
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestFirstParse(t *testing.T) {
+	seconds := func(s string) (time.Duration, error) {
+		n, err := strconv.Atoi(s)
+		return time.Duration(n) * time.Second, err
+	}
+
+	tests := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"30", 30 * time.Second},
+		{"1m30s", 90 * time.Second},
+	}
+	for _, tt := range tests {
+		got, err := FirstParse(tt.input, seconds, time.ParseDuration)
+		if err != nil || got != tt.expected {
+			t.Errorf("FirstParse(%q) = %v, %v, want %v, nil", tt.input, got, err, tt.expected)
+		}
+	}
+
+	_, err := FirstParse("soon", seconds, time.ParseDuration)
+	var numErr *strconv.NumError
+	if err == nil || !errors.As(err, &numErr) {
+		t.Errorf("FirstParse(soon) error = %v, want joined parser errors", err)
+	}
+
+	if _, err := FirstParse[int]("1"); err == nil {
+		t.Error("FirstParse() without parsers: expected error")
+	}
+}
@@ -0,0 +1,125 @@
+package gobag
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Plural returns the count n followed by singular if n is 1 or -1, and
+// by plural otherwise, e.g. "1 error" or "3 fields".
+func Plural(n int, singular, plural string) string {
+	return strconv.Itoa(n) + " " + Ternary(n == 1 || n == -1, singular, plural)
+}
+
+// Pluralizer derives English plural forms from singular nouns using
+// suffix rules, with exceptions for irregular and uncountable nouns.
+// A Pluralizer is not safe for concurrent modification.
+type Pluralizer struct {
+	irregular   map[string]string
+	uncountable map[string]bool
+	rules       []pluralRule
+}
+
+type pluralRule struct {
+	suffix      string
+	replacement string
+}
+
+// NewPluralizer returns a Pluralizer with rules for regular English
+// nouns and a small set of common irregular and uncountable ones.
+func NewPluralizer() *Pluralizer {
+	p := &Pluralizer{
+		irregular:   make(map[string]string),
+		uncountable: make(map[string]bool),
+	}
+	for _, suffix := range []string{"s", "x", "z", "ch", "sh"} {
+		p.AddRule(suffix, suffix+"es")
+	}
+	for _, c := range "bcdfghjklmnpqrstvwxz" {
+		p.AddRule(string(c)+"y", string(c)+"ies")
+	}
+	for singular, plural := range map[string]string{
+		"person": "people",
+		"child":  "children",
+		"man":    "men",
+		"woman":  "women",
+		"mouse":  "mice",
+		"goose":  "geese",
+		"foot":   "feet",
+		"tooth":  "teeth",
+		"index":  "indices",
+		"matrix": "matrices",
+		"vertex": "vertices",
+		"leaf":   "leaves",
+		"life":   "lives",
+		"knife":  "knives",
+	} {
+		p.AddIrregular(singular, plural)
+	}
+	for _, word := range []string{"sheep", "fish", "series", "species", "information", "data", "metadata", "equipment"} {
+		p.AddUncountable(word)
+	}
+
+	return p
+}
+
+// AddRule adds a rule replacing the suffix of a singular noun by
+// replacement. When several rules match, the one with the longest
+// suffix wins, and among equally long ones the last added.
+func (p *Pluralizer) AddRule(suffix, replacement string) {
+	p.rules = append(p.rules, pluralRule{strings.ToLower(suffix), replacement})
+}
+
+// AddIrregular adds a noun whose plural does not follow any rule.
+func (p *Pluralizer) AddIrregular(singular, plural string) {
+	p.irregular[strings.ToLower(singular)] = strings.ToLower(plural)
+}
+
+// AddUncountable adds a noun whose plural is the same as its singular.
+func (p *Pluralizer) AddUncountable(word string) {
+	p.uncountable[strings.ToLower(word)] = true
+}
+
+// Plural returns the plural form of the singular noun word, keeping
+// its capitalization if it is capitalized or all upper case. Nouns
+// matching no rule get an "s" appended.
+func (p *Pluralizer) Plural(word string) string {
+	lower := strings.ToLower(word)
+	if lower == "" || p.uncountable[lower] {
+		return word
+	}
+
+	plural, ok := p.irregular[lower]
+	if !ok {
+		plural = lower + "s"
+		best := -1
+		for _, rule := range p.rules {
+			if len(rule.suffix) >= best && strings.HasSuffix(lower, rule.suffix) {
+				plural = lower[:len(lower)-len(rule.suffix)] + rule.replacement
+				best = len(rule.suffix)
+			}
+		}
+	}
+
+	switch {
+	case word == strings.ToUpper(word) && len(word) > 1:
+		return strings.ToUpper(plural)
+	case unicode.IsUpper(firstRune(word)):
+		r, size := utf8.DecodeRuneInString(plural)
+		return string(unicode.ToUpper(r)) + plural[size:]
+	}
+	return plural
+}
+
+// Count returns the count n followed by word, pluralized unless n is 1
+// or -1, e.g. "1 error" or "2 indices".
+func (p *Pluralizer) Count(n int, word string) string {
+	return Plural(n, word, p.Plural(word))
+}
+
+func firstRune(s string) rune {
+	r, _ := utf8.DecodeRuneInString(s)
+	return r
+}
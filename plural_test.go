@@ -0,0 +1,67 @@
+package gobag
+
+import "testing"
+
+func TestPlural(t *testing.T) {
+	tests := []struct {
+		n        int
+		expected string
+	}{
+		{0, "0 fields"},
+		{1, "1 field"},
+		{2, "2 fields"},
+		{-1, "-1 field"},
+	}
+	for _, tt := range tests {
+		if got := Plural(tt.n, "field", "fields"); got != tt.expected {
+			t.Errorf("Plural(%d) = %q, want %q", tt.n, got, tt.expected)
+		}
+	}
+}
+
+func TestPluralizer(t *testing.T) {
+	p := NewPluralizer()
+	tests := map[string]string{
+		"field":  "fields",
+		"error":  "errors",
+		"box":    "boxes",
+		"match":  "matches",
+		"bus":    "buses",
+		"entry":  "entries",
+		"key":    "keys",
+		"child":  "children",
+		"Person": "People",
+		"INDEX":  "INDICES",
+		"sheep":  "sheep",
+		"Series": "Series",
+		"":       "",
+		"A":      "As",
+	}
+	for word, expected := range tests {
+		if got := p.Plural(word); got != expected {
+			t.Errorf("Plural(%q) = %q, want %q", word, got, expected)
+		}
+	}
+
+	p.AddRule("us", "i")
+	p.AddIrregular("ox", "oxen")
+	p.AddUncountable("moose")
+	custom := map[string]string{
+		"cactus": "cacti",
+		"ox":     "oxen",
+		"moose":  "moose",
+		"fox":    "foxes",
+	}
+	for word, expected := range custom {
+		if got := p.Plural(word); got != expected {
+			t.Errorf("Plural(%q) = %q, want %q", word, got, expected)
+		}
+	}
+
+	if got := p.Count(1, "error"); got != "1 error" {
+		t.Errorf("Count(1) = %q, want %q", got, "1 error")
+	}
+	if got := p.Count(3, "entry"); got != "3 entries" {
+		t.Errorf("Count(3) = %q, want %q", got, "3 entries")
+	}
+}
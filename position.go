@@ -0,0 +1,130 @@
+package gobag
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// Position is a location in a text stream.
+type Position struct {
+	Offset int64 // Byte offset, starting at 0.
+	Line   int   // Line number, starting at 1.
+	Column int   // Column in runes, starting at 1.
+}
+
+// String returns the position in line:column form.
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+func startPosition() Position {
+	return Position{Line: 1, Column: 1}
+}
+
+// advance moves the position past b.
+func (p *Position) advance(b []byte) {
+	for _, c := range b {
+		p.Offset++
+		switch {
+		case c == '\n':
+			p.Line++
+			p.Column = 1
+		case c&0xC0 != 0x80:
+			// Count the first byte of every UTF-8 sequence.
+			p.Column++
+		}
+	}
+}
+
+// PosReader wraps an io.Reader and tracks the position of the bytes
+// read through it, for error reporting.
+type PosReader struct {
+	r   io.Reader
+	pos Position
+}
+
+// NewPosReader returns a PosReader reading from r.
+func NewPosReader(r io.Reader) *PosReader {
+	return &PosReader{r: r, pos: startPosition()}
+}
+
+// Read implements io.Reader.
+func (r *PosReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.pos.advance(p[:n])
+	return n, err
+}
+
+// Position returns the position of the next byte to be read.
+func (r *PosReader) Position() Position {
+	return r.pos
+}
+
+// PosScanner is a bufio.Scanner that tracks the position of each
+// token in the input. Like bufio.Scanner it splits input into lines by
+// default.
+type PosScanner struct {
+	*bufio.Scanner
+	split    bufio.SplitFunc
+	pos      Position
+	tokenPos Position
+}
+
+// NewPosScanner returns a PosScanner reading from r.
+func NewPosScanner(r io.Reader) *PosScanner {
+	s := &PosScanner{
+		Scanner:  bufio.NewScanner(r),
+		split:    bufio.ScanLines,
+		pos:      startPosition(),
+		tokenPos: startPosition(),
+	}
+	s.Scanner.Split(s.scan)
+	return s
+}
+
+// Split sets the split function of the scanner. It must be called
+// before the first call to Scan.
+func (s *PosScanner) Split(split bufio.SplitFunc) {
+	s.split = split
+}
+
+// Position returns the position of the start of the most recent token
+// returned by Scan.
+func (s *PosScanner) Position() Position {
+	return s.tokenPos
+}
+
+func (s *PosScanner) scan(data []byte, atEOF bool) (int, []byte, error) {
+	advance, token, err := s.split(data, atEOF)
+	if err != nil && err != bufio.ErrFinalToken {
+		return advance, token, err
+	}
+
+	consumed := data[:min(max(advance, 0), len(data))]
+	if token != nil {
+		s.tokenPos = s.pos
+		s.tokenPos.advance(consumed[:tokenIndex(consumed, token)])
+	}
+	s.pos.advance(consumed)
+
+	return advance, token, err
+}
+
+// tokenIndex returns the index in the consumed bytes data where token
+// starts. Tokens are normally subslices of data, which tells where
+// they start. Tokens the split function built itself are looked up by
+// content, and are otherwise assumed to start at the beginning.
+func tokenIndex(data, token []byte) int {
+	if len(token) == 0 {
+		return 0
+	}
+	if i := cap(data) - cap(token); i >= 0 && i < len(data) && &data[i] == &token[0] {
+		return i
+	}
+	if i := bytes.Index(data, token); i >= 0 {
+		return i
+	}
+	return 0
+}
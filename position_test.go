@@ -0,0 +1,121 @@
+package gobag
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestPosReader(t *testing.T) {
+	r := NewPosReader(strings.NewReader("ab\næøå\nx"))
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := r.Position(), (Position{Offset: 5, Line: 2, Column: 2}); got != want {
+		t.Errorf("Position() = %+v, want %+v", got, want)
+	}
+
+	if _, err := io.ReadAll(r); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := r.Position(), (Position{Offset: 11, Line: 3, Column: 2}); got != want {
+		t.Errorf("Position() = %+v, want %+v", got, want)
+	}
+	if s := r.Position().String(); s != "3:2" {
+		t.Errorf("String() = %q, want 3:2", s)
+	}
+}
+
+func TestPosScanner(t *testing.T) {
+	s := NewPosScanner(strings.NewReader("first\nsecond line\n\nlast"))
+	var lines []string
+	for s.Scan() {
+		lines = append(lines, s.Position().String()+" "+s.Text())
+	}
+	want := []string{"1:1 first", "2:1 second line", "3:1 ", "4:1 last"}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+
+	s = NewPosScanner(strings.NewReader("  one\n  two  three"))
+	s.Split(bufio.ScanWords)
+	var words []string
+	for s.Scan() {
+		words = append(words, s.Position().String()+" "+s.Text())
+	}
+	want = []string{"1:3 one", "2:3 two", "2:8 three"}
+	if strings.Join(words, "|") != strings.Join(want, "|") {
+		t.Errorf("words = %q, want %q", words, want)
+	}
+
+	s = NewPosScanner(strings.NewReader("a,\"multi\nline\"\nb\n"))
+	s.Split(ScanRecords)
+	var records []string
+	for s.Scan() {
+		records = append(records, s.Position().String())
+	}
+	if strings.Join(records, "|") != "1:1|3:1" {
+		t.Errorf("record positions = %q, want [1:1 3:1]", records)
+	}
+}
+
+func TestPosScannerSplitFuncs(t *testing.T) {
+	tests := []struct {
+		name  string
+		split bufio.SplitFunc
+		want  []string
+	}{
+		{
+			// Tokens copied from the data, with less capacity.
+			name: "copying",
+			split: func(data []byte, atEOF bool) (int, []byte, error) {
+				advance, token, err := bufio.ScanWords(data, atEOF)
+				if token != nil {
+					token = bytes.Clone(token)
+				}
+				return advance, token, err
+			},
+			want: []string{"1:3 one", "2:3 two", "2:8 three", "3:1 stop", "3:6 after"},
+		},
+		{
+			// Tokens rewritten by the split function start where the
+			// bytes consumed for them do.
+			name: "upper case",
+			split: func(data []byte, atEOF bool) (int, []byte, error) {
+				advance, token, err := bufio.ScanWords(data, atEOF)
+				if token != nil {
+					token = bytes.ToUpper(token)
+				}
+				return advance, token, err
+			},
+			want: []string{"1:1 ONE", "2:1 TWO", "2:7 THREE", "3:1 STOP", "3:6 AFTER"},
+		},
+		{
+			name: "final token",
+			split: func(data []byte, atEOF bool) (int, []byte, error) {
+				advance, token, err := bufio.ScanWords(data, atEOF)
+				if string(token) == "stop" {
+					return advance, token, bufio.ErrFinalToken
+				}
+				return advance, token, err
+			},
+			want: []string{"1:3 one", "2:3 two", "2:8 three", "3:1 stop"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewPosScanner(strings.NewReader("  one\n  two  three\nstop after"))
+			s.Split(tt.split)
+			var words []string
+			for s.Scan() {
+				words = append(words, s.Position().String()+" "+s.Text())
+			}
+			if strings.Join(words, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("words = %q, want %q", words, tt.want)
+			}
+		})
+	}
+}
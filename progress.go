@@ -0,0 +1,106 @@
+package gobag
+
+import (
+	"sync"
+	"time"
+)
+
+// ProgressInfo is a snapshot of the state of a long-running operation
+// as reported to a ProgressFunc.
+type ProgressInfo struct {
+	Done    int64         // Number of items processed so far.
+	Total   int64         // Total number of items, or 0 if unknown.
+	Elapsed time.Duration // Time since the operation started.
+	ETA     time.Duration // Estimated time remaining, or 0 if unknown.
+}
+
+// ProgressFunc is called with progress updates of a long-running
+// operation.
+type ProgressFunc func(ProgressInfo)
+
+// Progress tracks the number of processed items of a long-running
+// operation and reports it to a ProgressFunc. It is safe for
+// concurrent use, so a single Progress can be shared by all workers
+// of a pool.
+type Progress struct {
+	mu       sync.Mutex
+	clock    Clock
+	fn       ProgressFunc
+	interval time.Duration
+	total    int64
+	done     int64
+	start    time.Time
+	last     time.Time
+}
+
+// NewProgress returns a Progress for an operation of total items (0
+// if unknown), calling fn at most once per interval. A zero interval
+// reports every update.
+func NewProgress(total int64, interval time.Duration, fn ProgressFunc) *Progress {
+	return NewProgressClock(total, interval, fn, RealClock{})
+}
+
+// NewProgressClock is like NewProgress, but measures time by clock.
+func NewProgressClock(total int64, interval time.Duration, fn ProgressFunc, clock Clock) *Progress {
+	now := clock.Now()
+	return &Progress{
+		clock:    clock,
+		fn:       fn,
+		interval: interval,
+		total:    total,
+		start:    now,
+		last:     now,
+	}
+}
+
+// Add records n more processed items. The ProgressFunc is called if
+// the interval has passed since the last report, or if the total has
+// been reached. It is called without holding the lock of p, so it may
+// call Info, but reports of concurrent calls may arrive out of order.
+func (p *Progress) Add(n int64) {
+	p.mu.Lock()
+	p.done += n
+	now := p.clock.Now()
+	if now.Sub(p.last) < p.interval && (p.total <= 0 || p.done < p.total) {
+		p.mu.Unlock()
+		return
+	}
+	p.last = now
+	info := p.info(now)
+	p.mu.Unlock()
+
+	p.report(info)
+}
+
+// Finish reports the final state unconditionally. It should be called
+// once the operation is complete.
+func (p *Progress) Finish() {
+	p.report(p.Info())
+}
+
+// Info returns the current progress.
+func (p *Progress) Info() ProgressInfo {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.info(p.clock.Now())
+}
+
+func (p *Progress) report(info ProgressInfo) {
+	if p.fn != nil {
+		p.fn(info)
+	}
+}
+
+func (p *Progress) info(now time.Time) ProgressInfo {
+	info := ProgressInfo{
+		Done:    p.done,
+		Total:   p.total,
+		Elapsed: now.Sub(p.start),
+	}
+	if p.total > 0 && p.done > 0 && p.done < p.total {
+		info.ETA = time.Duration(float64(info.Elapsed) / float64(p.done) * float64(p.total-p.done))
+	}
+
+	return info
+}
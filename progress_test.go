@@ -0,0 +1,67 @@
+package gobag
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestProgress(t *testing.T) {
+	var reports []ProgressInfo
+	p := NewProgress(100, 0, func(info ProgressInfo) {
+		reports = append(reports, info)
+	})
+
+	p.Add(25)
+	if len(reports) != 1 || reports[0].Done != 25 || reports[0].Total != 100 {
+		t.Fatalf("reports = %+v, want one report of 25/100", reports)
+	}
+	p.Add(75)
+	if last := reports[len(reports)-1]; last.Done != 100 || last.ETA != 0 {
+		t.Fatalf("last report = %+v, want 100/100 and no ETA", last)
+	}
+
+	reports = nil
+	p = NewProgress(10, time.Hour, func(info ProgressInfo) {
+		reports = append(reports, info)
+	})
+	var wg sync.WaitGroup
+	for range 9 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.Add(1)
+		}()
+	}
+	wg.Wait()
+	if len(reports) != 0 {
+		t.Fatalf("got %d reports within interval, want 0", len(reports))
+	}
+	p.Add(1)
+	if len(reports) != 1 || reports[0].Done != 10 {
+		t.Fatalf("reports = %+v, want a report on completion", reports)
+	}
+}
+
+func TestProgressCallbackCallsInfo(t *testing.T) {
+	var p *Progress
+	var seen []int64
+	p = NewProgress(2, 0, func(info ProgressInfo) {
+		seen = append(seen, p.Info().Done)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		p.Add(1)
+		p.Finish()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Add() deadlocked with a ProgressFunc calling Info")
+	}
+	if len(seen) != 2 || seen[0] != 1 || seen[1] != 1 {
+		t.Errorf("Info() from ProgressFunc = %v, want [1 1]", seen)
+	}
+}
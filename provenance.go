@@ -0,0 +1,74 @@
+package gobag
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Provenanced is a parsed value together with the location in the
+// source it was read from, so that errors found when validating the
+// value later can point back to it.
+type Provenanced[T any] struct {
+	Value T
+	File  string // Empty if the source is not a named file.
+	Position
+}
+
+// Location returns the source location in the form file:line:column,
+// or line:column if the file is not known.
+func (p Provenanced[T]) Location() string {
+	if p.File == "" {
+		return p.Position.String()
+	}
+	return p.File + ":" + p.Position.String()
+}
+
+// Errorf returns an error formatted like fmt.Errorf and prefixed by
+// the location of the value.
+func (p Provenanced[T]) Errorf(format string, a ...any) error {
+	return fmt.Errorf("%s: %w", p.Location(), fmt.Errorf(format, a...))
+}
+
+// ParseParamsProvenanced is like ParseParams, but returns every
+// parameter with the position of its key within s, read from the
+// named file.
+func ParseParamsProvenanced(s string, sep, assign rune, file string) ([]Provenanced[Param], error) {
+	fields, err := splitRaw(s, sep)
+	if err != nil {
+		return nil, err
+	}
+
+	params := make([]Provenanced[Param], 0, len(fields))
+	pos := startPosition()
+	for i, f := range fields {
+		if i > 0 {
+			pos.advance([]byte(string(sep)))
+		}
+		if strings.TrimSpace(f) == "" {
+			pos.advance([]byte(f))
+			continue
+		}
+
+		lead := len(f) - len(strings.TrimLeftFunc(f, unicode.IsSpace))
+		pos.advance([]byte(f[:lead]))
+		key, value, _ := cutUnquoted(f, assign)
+		p := Provenanced[Param]{
+			Value:    Param{Key: decodeValue(key), Value: decodeValue(value)},
+			File:     file,
+			Position: pos,
+		}
+		if p.Value.Key == "" {
+			return nil, p.Errorf("missing key in parameter %q", strings.TrimSpace(f))
+		}
+		params = append(params, p)
+		pos.advance([]byte(f[lead:]))
+	}
+
+	return params, nil
+}
+
+// String returns the location followed by the value.
+func (p Provenanced[T]) String() string {
+	return fmt.Sprintf("%s: %v", p.Location(), p.Value)
+}
@@ -0,0 +1,48 @@
+package gobag
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseParamsProvenanced(t *testing.T) {
+	params, err := ParseParamsProvenanced("host=db1,  port = 5432,\n  user=\"äb\", ,x", ',', '=', "app.conf")
+	if err != nil {
+		t.Fatalf("ParseParamsProvenanced() error = %v", err)
+	}
+
+	want := []Provenanced[Param]{
+		{Param{"host", "db1"}, "app.conf", Position{0, 1, 1}},
+		{Param{"port", "5432"}, "app.conf", Position{11, 1, 12}},
+		{Param{"user", "äb"}, "app.conf", Position{26, 2, 3}},
+		{Param{"x", ""}, "app.conf", Position{39, 2, 15}},
+	}
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("ParseParamsProvenanced() =\n%v\nwant\n%v", params, want)
+	}
+
+	_, err = ParseParamsProvenanced("a=1, =2", ',', '=', "")
+	if err == nil || err.Error() != `1:6: missing key in parameter "=2"` {
+		t.Errorf("ParseParamsProvenanced() error = %v", err)
+	}
+}
+
+func TestProvenancedErrorf(t *testing.T) {
+	p := Provenanced[int]{Value: 70000, File: "app.conf", Position: Position{Line: 3, Column: 7}}
+	if got := p.Location(); got != "app.conf:3:7" {
+		t.Errorf("Location() = %q", got)
+	}
+	if got := p.String(); got != "app.conf:3:7: 70000" {
+		t.Errorf("String() = %q", got)
+	}
+
+	base := errors.New("out of range")
+	err := p.Errorf("port %d: %w", p.Value, base)
+	if err.Error() != "app.conf:3:7: port 70000: out of range" {
+		t.Errorf("Errorf() = %q", err)
+	}
+	if !errors.Is(err, base) {
+		t.Error("Errorf() does not wrap its %w argument")
+	}
+}
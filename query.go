@@ -0,0 +1,213 @@
+package gobag
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Query is a parsed boolean query over string fields. It is safe for
+// concurrent use.
+type Query struct {
+	root queryNode
+}
+
+type queryNode interface {
+	match(set map[string]struct{}) bool
+}
+
+type queryTerm string
+
+func (t queryTerm) match(set map[string]struct{}) bool {
+	_, ok := set[string(t)]
+	return ok
+}
+
+type queryNot struct{ operand queryNode }
+
+func (n queryNot) match(set map[string]struct{}) bool { return !n.operand.match(set) }
+
+type queryAnd []queryNode
+
+func (n queryAnd) match(set map[string]struct{}) bool {
+	for _, operand := range n {
+		if !operand.match(set) {
+			return false
+		}
+	}
+	return true
+}
+
+type queryOr []queryNode
+
+func (n queryOr) match(set map[string]struct{}) bool {
+	for _, operand := range n {
+		if operand.match(set) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match reports whether fields satisfy query. See ParseQuery for the
+// query syntax.
+func Match(query string, fields []string) (bool, error) {
+	q, err := ParseQuery(query)
+	if err != nil {
+		return false, err
+	}
+	return q.Match(fields), nil
+}
+
+// ParseQuery parses a boolean query such as
+//
+//	error AND NOT (timeout OR "connection reset")
+//
+// A term matches if it is equal to one of the fields. Terms are
+// combined with the operators NOT, AND and OR, from highest to lowest
+// precedence, and grouped with parentheses. Adjacent terms are
+// implicitly combined with AND. Terms follow the quoting and escaping
+// rules of Fields, so a quoted "AND" is a term rather than an operator.
+// An empty query matches everything.
+func ParseQuery(query string) (*Query, error) {
+	tokens, err := queryTokens(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return &Query{root: queryAnd{}}, nil
+	}
+
+	p := &queryParser{tokens: tokens}
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in query", p.tokens[p.pos].text)
+	}
+
+	return &Query{root: root}, nil
+}
+
+// Match reports whether fields satisfy the query.
+func (q *Query) Match(fields []string) bool {
+	set := make(map[string]struct{}, len(fields))
+	for _, f := range fields {
+		set[f] = struct{}{}
+	}
+	return q.root.match(set)
+}
+
+type queryToken struct {
+	text     string
+	operator bool // An operator or parenthesis, rather than a term.
+}
+
+func queryTokens(query string) ([]queryToken, error) {
+	p := &sexprParser{s: query}
+	var tokens []queryToken
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.s) {
+			return tokens, nil
+		}
+
+		switch c := p.s[p.pos]; c {
+		case '(', ')':
+			tokens = append(tokens, queryToken{text: string(c), operator: true})
+			p.pos++
+			continue
+		}
+		start := p.pos
+		n, err := p.atom()
+		if err != nil {
+			return nil, err
+		}
+		raw := p.s[start:p.pos]
+		op := !n.Quoted && (raw == "AND" || raw == "OR" || raw == "NOT")
+		tokens = append(tokens, queryToken{text: n.Atom, operator: op})
+	}
+}
+
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+}
+
+func (p *queryParser) peek(op string) bool {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].operator && p.tokens[p.pos].text == op
+}
+
+func (p *queryParser) or() (queryNode, error) {
+	n, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	nodes := queryOr{n}
+	for p.peek("OR") {
+		p.pos++
+		n, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return nodes, nil
+}
+
+func (p *queryParser) and() (queryNode, error) {
+	n, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	nodes := queryAnd{n}
+	for p.pos < len(p.tokens) && !p.peek("OR") && !p.peek(")") {
+		if p.peek("AND") {
+			p.pos++
+		}
+		n, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return nodes, nil
+}
+
+func (p *queryParser) not() (queryNode, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, errors.New("unexpected end of query")
+	}
+
+	tok := p.tokens[p.pos]
+	p.pos++
+	if !tok.operator {
+		return queryTerm(tok.text), nil
+	}
+	switch tok.text {
+	case "NOT":
+		n, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return queryNot{n}, nil
+	case "(":
+		n, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peek(")") {
+			return nil, errors.New("unbalanced parentheses in query")
+		}
+		p.pos++
+		return n, nil
+	}
+
+	return nil, fmt.Errorf("unexpected %q in query", tok.text)
+}
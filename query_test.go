@@ -0,0 +1,49 @@
+package gobag
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	fields := []string{"2024-01-01", "error", "connection reset", "AND"}
+	tests := []struct {
+		query    string
+		expected bool
+	}{
+		{"", true},
+		{"error", true},
+		{"warning", false},
+		{"error AND warning", false},
+		{"error warning", false},
+		{"error OR warning", true},
+		{"NOT warning", true},
+		{"NOT NOT error", true},
+		{`error AND "connection reset"`, true},
+		{`error AND NOT (timeout OR 'connection reset')`, false},
+		{`warning OR error AND NOT timeout`, true},
+		{`(warning OR error) AND timeout`, false},
+		{`"AND"`, true},
+		{`connection\ reset`, true},
+	}
+	for _, tt := range tests {
+		got, err := Match(tt.query, fields)
+		if err != nil || got != tt.expected {
+			t.Errorf("Match(%q) = %t, %v, want %t", tt.query, got, err, tt.expected)
+		}
+	}
+
+	errs := []struct {
+		query string
+		err   string
+	}{
+		{"error AND", "unexpected end of query"},
+		{"(error", "unbalanced parentheses in query"},
+		{"error)", `unexpected ")" in query`},
+		{"OR error", `unexpected "OR" in query`},
+		{`"error`, "unbalanced double quote in string"},
+	}
+	for _, tt := range errs {
+		_, err := Match(tt.query, fields)
+		if err == nil || err.Error() != tt.err {
+			t.Errorf("Match(%q) error = %v, want %q", tt.query, err, tt.err)
+		}
+	}
+}
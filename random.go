@@ -0,0 +1,68 @@
+package gobag
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	mrand "math/rand/v2"
+)
+
+// Rand is a source of uniformly distributed random numbers, consumed by
+// the helpers of this package that need randomness. It matches the
+// Source interface of math/rand/v2, so any such source can be used.
+// Tests can use a source from NewSeededRand to be reproducible.
+type Rand interface {
+	Uint64() uint64
+}
+
+// NewSeededRand returns a deterministic Rand producing the same numbers
+// for the same seed. It is not safe for concurrent use and not suited
+// for security purposes.
+func NewSeededRand(seed uint64) Rand {
+	return mrand.NewPCG(seed, seed^0x9e3779b97f4a7c15)
+}
+
+// CryptoRand is a Rand reading from crypto/rand, for random values that
+// must be unpredictable, such as tokens. It is safe for concurrent
+// use. The zero value is ready to use.
+type CryptoRand struct{}
+
+// Uint64 returns a random number from crypto/rand.
+func (CryptoRand) Uint64() uint64 {
+	var b [8]byte
+	rand.Read(b[:])
+	return binary.LittleEndian.Uint64(b[:])
+}
+
+// newRand returns a *rand.Rand drawing from r, or from a randomly
+// seeded source if r is nil.
+func newRand(r Rand) *mrand.Rand {
+	if r == nil {
+		r = mrand.NewPCG(mrand.Uint64(), mrand.Uint64())
+	}
+	return mrand.New(r)
+}
+
+// Shuffle randomizes the order of the elements of s in place, using
+// the random numbers of r, or of the default source if r is nil.
+func Shuffle[T any](s []T, r Rand) {
+	newRand(r).Shuffle(len(s), func(i, j int) {
+		s[i], s[j] = s[j], s[i]
+	})
+}
+
+// RandString returns a string of n runes picked uniformly from
+// alphabet, using the random numbers of r, or of the default source if
+// r is nil. It panics if alphabet is empty.
+func RandString(n int, alphabet string, r Rand) string {
+	runes := []rune(alphabet)
+	if len(runes) == 0 {
+		panic("gobag: RandString alphabet is empty")
+	}
+
+	random := newRand(r)
+	out := make([]rune, n)
+	for i := range out {
+		out[i] = runes[random.IntN(len(runes))]
+	}
+	return string(out)
+}
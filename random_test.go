@@ -0,0 +1,70 @@
+package gobag
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSeededRand(t *testing.T) {
+	a, b := NewSeededRand(42), NewSeededRand(42)
+	for range 10 {
+		if x, y := a.Uint64(), b.Uint64(); x != y {
+			t.Fatalf("seeded sources differ: %d != %d", x, y)
+		}
+	}
+	if NewSeededRand(1).Uint64() == NewSeededRand(2).Uint64() {
+		t.Error("different seeds gave the same number")
+	}
+}
+
+func TestCryptoRand(t *testing.T) {
+	var r CryptoRand
+	if r.Uint64() == r.Uint64() {
+		t.Error("CryptoRand returned the same number twice")
+	}
+}
+
+func TestShuffle(t *testing.T) {
+	s1 := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	s2 := slices.Clone(s1)
+	Shuffle(s1, NewSeededRand(7))
+	Shuffle(s2, NewSeededRand(7))
+	if !slices.Equal(s1, s2) {
+		t.Errorf("Shuffle() with equal seeds = %v and %v", s1, s2)
+	}
+	if slices.IsSorted(s1) {
+		t.Errorf("Shuffle() left %v in order", s1)
+	}
+	slices.Sort(s1)
+	if !slices.Equal(s1, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}) {
+		t.Errorf("Shuffle() changed the elements: %v", s1)
+	}
+	Shuffle([]int{}, nil)
+}
+
+func TestRandString(t *testing.T) {
+	s := RandString(20, "abcæøå", NewSeededRand(3))
+	if RuneLen(s) != 20 || strings.Trim(s, "abcæøå") != "" {
+		t.Errorf("RandString() = %q", s)
+	}
+	if again := RandString(20, "abcæøå", NewSeededRand(3)); again != s {
+		t.Errorf("RandString() with equal seeds = %q and %q", s, again)
+	}
+	if got := RandString(0, "x", nil); got != "" {
+		t.Errorf("RandString(0) = %q", got)
+	}
+}
+
+func TestReservoirRand(t *testing.T) {
+	sample := func() []int {
+		r := NewReservoirRand[int](5, NewSeededRand(11))
+		for i := range 1000 {
+			r.Add(i)
+		}
+		return r.Sample()
+	}
+	if a, b := sample(), sample(); !slices.Equal(a, b) {
+		t.Errorf("seeded reservoirs sampled %v and %v", a, b)
+	}
+}
@@ -0,0 +1,132 @@
+package gobag
+
+import (
+	"sync"
+	"time"
+)
+
+// EWMA is an exponentially weighted moving average, where each new
+// value has a fixed weight and the weight of older values decays
+// geometrically. It is safe for concurrent use.
+type EWMA struct {
+	mu    sync.Mutex
+	alpha float64
+	value float64
+	set   bool
+}
+
+// NewEWMA returns an EWMA giving each new value the weight alpha,
+// between 0 and 1. A larger alpha follows changes more quickly; an
+// alpha of 2/(n+1) is comparable to an average over the last n values.
+// It panics if alpha is out of range.
+func NewEWMA(alpha float64) *EWMA {
+	if !(alpha > 0 && alpha <= 1) {
+		panic("gobag: EWMA alpha must be in (0, 1]")
+	}
+	return &EWMA{alpha: alpha}
+}
+
+// Update adds a value to the average. The first value sets the average
+// as is.
+func (e *EWMA) Update(v float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.set {
+		e.value, e.set = v, true
+		return
+	}
+	e.value += e.alpha * (v - e.value)
+}
+
+// Value returns the current average, or 0 if no value has been added.
+func (e *EWMA) Value() float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.value
+}
+
+// rateBuckets is the number of buckets a RateCounter divides its
+// window into.
+const rateBuckets = 60
+
+// RateCounter counts events over a sliding time window, such as lines
+// parsed in the last minute. The window is divided into buckets that
+// expire one at a time, so counts are accurate to within one bucket,
+// a 60th of the window. It is safe for concurrent use.
+type RateCounter struct {
+	mu      sync.Mutex
+	window  time.Duration
+	width   time.Duration // Duration of each bucket.
+	buckets [rateBuckets]int64
+	slot    int64 // Number of the bucket for the current time.
+	clock   Clock
+}
+
+// NewRateCounter returns a RateCounter over the given window. It
+// panics if window is less than 60ns.
+func NewRateCounter(window time.Duration) *RateCounter {
+	return NewRateCounterClock(window, RealClock{})
+}
+
+// NewRateCounterClock is like NewRateCounter, but measures time by
+// clock.
+func NewRateCounterClock(window time.Duration, clock Clock) *RateCounter {
+	if window < rateBuckets {
+		panic("gobag: RateCounter window too short")
+	}
+	r := &RateCounter{
+		window: window,
+		width:  window / rateBuckets,
+		clock:  clock,
+	}
+	r.slot = r.currentSlot()
+	return r
+}
+
+func (r *RateCounter) currentSlot() int64 {
+	return r.clock.Now().UnixNano() / int64(r.width)
+}
+
+// bucketIndex returns the index of the bucket of slot, which is
+// negative for times before 1970.
+func bucketIndex(slot int64) int64 {
+	return (slot%rateBuckets + rateBuckets) % rateBuckets
+}
+
+// advance expires the buckets that have left the window.
+func (r *RateCounter) advance() {
+	slot := r.currentSlot()
+	for s := r.slot + 1; s <= slot && s <= r.slot+rateBuckets; s++ {
+		r.buckets[bucketIndex(s)] = 0
+	}
+	r.slot = max(r.slot, slot)
+}
+
+// Add records n events.
+func (r *RateCounter) Add(n int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.advance()
+	r.buckets[bucketIndex(r.slot)] += n
+}
+
+// Count returns the number of events within the window.
+func (r *RateCounter) Count() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.advance()
+	var n int64
+	for _, b := range r.buckets {
+		n += b
+	}
+	return n
+}
+
+// Rate returns the number of events per second within the window.
+func (r *RateCounter) Rate() float64 {
+	return float64(r.Count()) / r.window.Seconds()
+}
@@ -0,0 +1,75 @@
+package gobag
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEWMA(t *testing.T) {
+	e := NewEWMA(0.5)
+	if e.Value() != 0 {
+		t.Errorf("Value() of empty EWMA = %v, want 0", e.Value())
+	}
+	for _, v := range []float64{10, 20, 20, 0} {
+		e.Update(v)
+	}
+	// 10, then 15, 17.5 and 8.75.
+	if got := e.Value(); math.Abs(got-8.75) > 1e-9 {
+		t.Errorf("Value() = %v, want 8.75", got)
+	}
+
+	for _, alpha := range []float64{0, -1, 1.5, math.NaN()} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewEWMA(%v) did not panic", alpha)
+				}
+			}()
+			NewEWMA(alpha)
+		}()
+	}
+}
+
+func TestRateCounter(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	r := NewRateCounterClock(time.Minute, clock)
+
+	r.Add(30)
+	clock.Advance(30 * time.Second)
+	r.Add(90)
+	if got := r.Count(); got != 120 {
+		t.Errorf("Count() = %d, want 120", got)
+	}
+	if got := r.Rate(); got != 2 {
+		t.Errorf("Rate() = %v, want 2", got)
+	}
+
+	clock.Advance(45 * time.Second)
+	if got := r.Count(); got != 90 {
+		t.Errorf("Count() after first events expired = %d, want 90", got)
+	}
+	clock.Advance(time.Hour)
+	if got := r.Count(); got != 0 {
+		t.Errorf("Count() after window passed = %d, want 0", got)
+	}
+}
+
+func TestRateCounterConcurrent(t *testing.T) {
+	r := NewRateCounter(time.Hour)
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				r.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := r.Count(); got != 8000 {
+		t.Errorf("Count() = %d, want 8000", got)
+	}
+}
@@ -0,0 +1,183 @@
+package gobag
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind is the type of a column value.
+type Kind int
+
+// Column kinds and the Go types of their values.
+const (
+	KindString   Kind = iota // string
+	KindInt                  // int
+	KindFloat                // float64
+	KindBool                 // bool
+	KindDuration             // time.Duration
+	KindTime                 // time.Time
+)
+
+// String returns the name of the kind.
+func (k Kind) String() string {
+	switch k {
+	case KindString:
+		return "string"
+	case KindInt:
+		return "int"
+	case KindFloat:
+		return "float"
+	case KindBool:
+		return "bool"
+	case KindDuration:
+		return "duration"
+	case KindTime:
+		return "time"
+	}
+	return "unknown"
+}
+
+// parse converts s to a value of the kind. Ints may be written in any
+// base accepted by strconv.ParseInt with base 0, bools as accepted by
+// DSN.GetBool, and times in any of the built-in layouts of
+// ParseTimeAny.
+func (k Kind) parse(s string) (any, bool) {
+	switch k {
+	case KindString:
+		return s, true
+	case KindInt:
+		n, err := strconv.ParseInt(s, 0, strconv.IntSize)
+		return int(n), err == nil
+	case KindFloat:
+		f, err := strconv.ParseFloat(s, 64)
+		return f, err == nil
+	case KindBool:
+		return parseBool(s)
+	case KindDuration:
+		d, err := time.ParseDuration(s)
+		return d, err == nil
+	case KindTime:
+		t, err := ParseTimeAny(s)
+		return t, err == nil
+	}
+	return nil, false
+}
+
+// Column describes a column of a Schema.
+type Column struct {
+	Name string
+	Kind Kind
+
+	// Required columns must have a non-empty value.
+	Required bool
+
+	// Default is used for an empty or missing value of an optional
+	// column. It must be valid for the kind. Optional columns without
+	// a default are left out of the record when empty.
+	Default string
+}
+
+// Schema describes records of fields separated by a separator rune,
+// with one field per column in order.
+type Schema struct {
+	sep     rune
+	columns []Column
+}
+
+// Record is a parsed record, mapping column names to values of the Go
+// type of their kind.
+type Record map[string]any
+
+// NewSchema returns a schema for records of fields separated by sep.
+// Returns an error if column names are empty or repeated, or a default
+// is not valid for its column.
+func NewSchema(sep rune, columns ...Column) (*Schema, error) {
+	seen := make(map[string]bool, len(columns))
+	for _, c := range columns {
+		if c.Name == "" || seen[c.Name] {
+			return nil, fmt.Errorf("invalid or repeated column name %q", c.Name)
+		}
+		seen[c.Name] = true
+		if c.Default != "" {
+			if _, ok := c.Kind.parse(c.Default); !ok {
+				return nil, fmt.Errorf("column '%s': invalid default %q for %s", c.Name, c.Default, c.Kind)
+			}
+		}
+	}
+
+	return &Schema{sep: sep, columns: columns}, nil
+}
+
+// Columns returns the columns of the schema.
+func (s *Schema) Columns() []Column {
+	return s.columns
+}
+
+// ParseRecord splits line into fields like Fields and converts each to
+// the kind of its column, after trimming and unquoting it. Returns an
+// error naming the column for values of the wrong kind and missing
+// required values, or if the line has more fields than columns.
+func (s *Schema) ParseRecord(line string) (Record, error) {
+	fields, err := splitRaw(line, s.sep)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) > len(s.columns) {
+		return nil, fmt.Errorf("expected at most %d columns, got %d", len(s.columns), len(fields))
+	}
+
+	rec := make(Record, len(s.columns))
+	for i, c := range s.columns {
+		var v string
+		if i < len(fields) {
+			v = decodeValue(fields[i])
+		}
+		if v == "" {
+			if c.Required {
+				return nil, fmt.Errorf("column '%s' is required", c.Name)
+			}
+			if v = c.Default; v == "" {
+				continue
+			}
+		}
+
+		value, ok := c.Kind.parse(v)
+		if !ok {
+			return nil, fmt.Errorf("column '%s' expects %s, got '%s'", c.Name, c.Kind, v)
+		}
+		rec[c.Name] = value
+	}
+
+	return rec, nil
+}
+
+// ParseRecords parses every line read from r with ParseRecord,
+// skipping blank lines. Errors are prefixed with the line number.
+func (s *Schema) ParseRecords(r io.Reader) ([]Record, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<24)
+
+	records := make([]Record, 0)
+	var lineno int
+	for scanner.Scan() {
+		lineno++
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		rec, err := s.ParseRecord(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineno, err)
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
@@ -0,0 +1,88 @@
+package gobag
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testSchema(t *testing.T) *Schema {
+	t.Helper()
+	s, err := NewSchema(',',
+		Column{Name: "host", Kind: KindString, Required: true},
+		Column{Name: "port", Kind: KindInt, Default: "22"},
+		Column{Name: "weight", Kind: KindFloat},
+		Column{Name: "enabled", Kind: KindBool, Default: "yes"},
+		Column{Name: "timeout", Kind: KindDuration},
+		Column{Name: "since", Kind: KindTime},
+	)
+	if err != nil {
+		t.Fatalf("NewSchema() error = %v", err)
+	}
+	return s
+}
+
+func TestParseRecord(t *testing.T) {
+	s := testSchema(t)
+
+	tests := []struct {
+		line     string
+		expected Record
+		err      string
+	}{
+		{
+			`"db 1", 0x10, 1.5, off, 3s, 2024-05-01T12:00:00Z`,
+			Record{"host": "db 1", "port": 16, "weight": 1.5, "enabled": false,
+				"timeout": 3 * time.Second, "since": time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)},
+			"",
+		},
+		{"web", Record{"host": "web", "port": 22, "enabled": true}, ""},
+		{"web,,2", Record{"host": "web", "port": 22, "weight": 2.0, "enabled": true}, ""},
+		{",80", nil, "column 'host' is required"},
+		{"web,abc", nil, "column 'port' expects int, got 'abc'"},
+		{"web,1,x", nil, "column 'weight' expects float, got 'x'"},
+		{"web,1,2,maybe", nil, "column 'enabled' expects bool, got 'maybe'"},
+		{"a,1,2,on,1s,now,extra", nil, "expected at most 6 columns, got 7"},
+		{`web,"80`, nil, "unbalanced double quote in string"},
+	}
+	for _, tt := range tests {
+		got, err := s.ParseRecord(tt.line)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("ParseRecord(%q) error = %v, want %q", tt.line, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("ParseRecord(%q) = %v, %v, want %v", tt.line, got, err, tt.expected)
+		}
+	}
+}
+
+func TestParseRecords(t *testing.T) {
+	s := testSchema(t)
+
+	records, err := s.ParseRecords(strings.NewReader("a,1\n\nb,2\r\n"))
+	if err != nil {
+		t.Fatalf("ParseRecords() error = %v", err)
+	}
+	if len(records) != 2 || records[1]["port"] != 2 {
+		t.Errorf("ParseRecords() = %v", records)
+	}
+
+	_, err = s.ParseRecords(strings.NewReader("a,1\n\nb,abc\n"))
+	if err == nil || err.Error() != "line 3: column 'port' expects int, got 'abc'" {
+		t.Errorf("ParseRecords() error = %v", err)
+	}
+}
+
+func TestNewSchemaErrors(t *testing.T) {
+	if _, err := NewSchema(',', Column{Name: "a"}, Column{Name: "a"}); err == nil {
+		t.Error("NewSchema() with repeated column succeeded")
+	}
+	_, err := NewSchema(',', Column{Name: "n", Kind: KindInt, Default: "x"})
+	if err == nil || err.Error() != `column 'n': invalid default "x" for int` {
+		t.Errorf("NewSchema() error = %v", err)
+	}
+}
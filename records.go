@@ -0,0 +1,215 @@
+package gobag
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// quoteState tracks quoting, grouping and escaping while scanning
+// text rune by rune, following the rules of Fields.
+type quoteState struct {
+	balance            int
+	inSingle, inDouble bool
+	escaped            bool
+}
+
+// next advances the state past r, and reports whether r is at the top
+// level, i.e. not escaped, quoted or within parentheses.
+func (q *quoteState) next(r rune) bool {
+	if q.escaped {
+		q.escaped = false
+		return false
+	}
+
+	switch r {
+	case '\\':
+		q.escaped = true
+		return false
+	case '"':
+		if !q.inSingle {
+			q.inDouble = !q.inDouble
+		}
+		return false
+	case '\'':
+		if !q.inDouble {
+			q.inSingle = !q.inSingle
+		}
+		return false
+	case '(':
+		if !q.inSingle && !q.inDouble {
+			q.balance++
+		}
+		return false
+	case ')':
+		if !q.inSingle && !q.inDouble {
+			q.balance--
+		}
+		return false
+	}
+
+	return q.balance == 0 && !q.inSingle && !q.inDouble
+}
+
+// err returns the error Fields would report for input ending in the
+// current state, or nil if the state is balanced.
+func (q *quoteState) err() error {
+	switch {
+	case q.escaped:
+		return errors.New("dangling escape character at end of string")
+	case q.balance < 0:
+		return errors.New("too many closing parentheses")
+	case q.balance != 0:
+		return errors.New("unbalanced parentheses in string")
+	case q.inSingle:
+		return errors.New("unbalanced single quote in string")
+	case q.inDouble:
+		return errors.New("unbalanced double quote in string")
+	}
+	return nil
+}
+
+// SplitRecords splits s into records at newlines that are not within
+// quotes or parentheses, and not escaped. A trailing carriage return
+// is removed from each record, and a final newline does not produce an
+// empty record. The records are returned verbatim, ready for Fields.
+// Returns an error if quotes or parentheses are unbalanced.
+func SplitRecords(s string) ([]string, error) {
+	var state quoteState
+	records := make([]string, 0)
+	start := 0
+	for i, r := range s {
+		if state.next(r) && r == '\n' {
+			records = append(records, strings.TrimSuffix(s[start:i], "\r"))
+			start = i + 1
+		}
+	}
+	if err := state.err(); err != nil {
+		return nil, err
+	}
+
+	if start < len(s) {
+		records = append(records, strings.TrimSuffix(s[start:], "\r"))
+	}
+	return records, nil
+}
+
+// ScanRecords is a split function for a bufio.Scanner that returns
+// records as split by SplitRecords, allowing quoted values to span
+// multiple lines of the input:
+//
+//	scanner := bufio.NewScanner(r)
+//	scanner.Split(gobag.ScanRecords)
+//	for scanner.Scan() {
+//		fields, err := gobag.Fields(scanner.Text(), ',')
+//		...
+//	}
+//
+// Being stateless, ScanRecords rescans a record from its start every
+// time the scanner reads more of it. Use NewRecordSplitFunc for input
+// with records spanning many reads.
+func ScanRecords(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	var scan recordScan
+	return scan.split(data, atEOF)
+}
+
+// NewRecordSplitFunc returns a split function like ScanRecords that
+// remembers how far it has scanned the data of the scanner, so every
+// byte of the input is scanned once. The function must not be shared
+// between scanners.
+func NewRecordSplitFunc() bufio.SplitFunc {
+	var scan recordScan
+	return scan.split
+}
+
+// recordScan is the state of a record split function between calls.
+type recordScan struct {
+	state   quoteState
+	scanned int // Bytes of the current record scanned with state.
+}
+
+func (s *recordScan) split(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	for s.scanned < len(data) {
+		rest := data[s.scanned:]
+		if !atEOF && !utf8.FullRune(rest) {
+			break
+		}
+		r, size := utf8.DecodeRune(rest)
+		if s.state.next(r) && r == '\n' {
+			end := s.scanned
+			s.scanned = 0
+			return end + 1, bytes.TrimSuffix(data[:end], []byte{'\r'}), nil
+		}
+		s.scanned += size
+	}
+
+	if !atEOF {
+		// Request more data.
+		return 0, nil, nil
+	}
+	if err := s.state.err(); err != nil {
+		return 0, nil, err
+	}
+
+	s.scanned = 0
+	return len(data), bytes.TrimSuffix(data, []byte{'\r'}), nil
+}
+
+// splitRaw splits s at separators that are not within quotes or
+// parentheses, and not escaped, like Fields. Unlike Fields the fields
+// are returned verbatim, with quotes and escape characters intact, and
+// a trailing empty field is kept.
+func splitRaw(s string, sep rune) ([]string, error) {
+	var state quoteState
+	fields := make([]string, 0)
+	start := 0
+	for i, r := range s {
+		if state.next(r) && r == sep {
+			fields = append(fields, s[start:i])
+			start = i + utf8.RuneLen(r)
+		}
+	}
+	if err := state.err(); err != nil {
+		return nil, err
+	}
+
+	return append(fields, s[start:]), nil
+}
+
+// unescape removes escape characters from s, keeping the characters
+// they escape.
+func unescape(s string) string {
+	if !strings.ContainsRune(s, '\\') {
+		return s
+	}
+
+	var sb strings.Builder
+	escaped := false
+	for _, r := range s {
+		if r == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		escaped = false
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
+
+// decodeValue returns the value of a raw field: surrounding whitespace
+// is removed, and the field is unquoted if it is a single quoted token
+// or unescaped otherwise.
+func decodeValue(s string) string {
+	s = strings.TrimSpace(s)
+	if v, err := UnquoteStringWith(s, UnquoteOptions{Strict: true, SingleQuote: true}); err == nil {
+		return v
+	}
+	return unescape(s)
+}
@@ -0,0 +1,95 @@
+package gobag
+
+import (
+	"bufio"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestSplitRecords(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+		err      error
+	}{
+		{"empty", "", []string{}, nil},
+		{"simple lines", "a,b\nc,d\n", []string{"a,b", "c,d"}, nil},
+		{"crlf", "a\r\nb\r\n", []string{"a", "b"}, nil},
+		{"empty line", "a\n\nb", []string{"a", "", "b"}, nil},
+		{"quoted newline", "a,\"b\nc\"\nd", []string{"a,\"b\nc\"", "d"}, nil},
+		{"single quoted newline", "'a\nb'\nc", []string{"'a\nb'", "c"}, nil},
+		{"grouped newline", "a,(b,\nc)\nd", []string{"a,(b,\nc)", "d"}, nil},
+		{"escaped newline", "a\\\nb\nc", []string{"a\\\nb", "c"}, nil},
+		{"unbalanced", "a\n\"b\nc", nil, errors.New("unbalanced double quote in string")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SplitRecords(tt.input)
+			if tt.err != nil {
+				if err == nil || err.Error() != tt.err.Error() {
+					t.Errorf("SplitRecords(%q) error = %v, want %v", tt.input, err, tt.err)
+				}
+			} else if err != nil {
+				t.Errorf("SplitRecords(%q) error = %v, want nil", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("SplitRecords(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+
+			if tt.err != nil {
+				return
+			}
+			scanner := bufio.NewScanner(strings.NewReader(tt.input))
+			scanner.Split(ScanRecords)
+			scanned := make([]string, 0)
+			for scanner.Scan() {
+				scanned = append(scanned, scanner.Text())
+			}
+			if err := scanner.Err(); err != nil {
+				t.Fatalf("ScanRecords error = %v", err)
+			}
+			if !reflect.DeepEqual(scanned, tt.expected) {
+				t.Errorf("ScanRecords(%q) = %q, want %q", tt.input, scanned, tt.expected)
+			}
+
+			scanner = bufio.NewScanner(iotest.OneByteReader(strings.NewReader(tt.input)))
+			scanner.Split(NewRecordSplitFunc())
+			scanned = make([]string, 0)
+			for scanner.Scan() {
+				scanned = append(scanned, scanner.Text())
+			}
+			if err := scanner.Err(); err != nil {
+				t.Fatalf("NewRecordSplitFunc() error = %v", err)
+			}
+			if !reflect.DeepEqual(scanned, tt.expected) {
+				t.Errorf("NewRecordSplitFunc() on %q = %q, want %q", tt.input, scanned, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRecordSplitFuncLongRecord(t *testing.T) {
+	// A record spanning many reads, with multibyte characters split
+	// between them.
+	long := "\"" + strings.Repeat("æ,\n", 5000) + "\""
+	input := "a\n" + long + "\nb\n\"unbalanced"
+
+	scanner := bufio.NewScanner(iotest.HalfReader(strings.NewReader(input)))
+	scanner.Buffer(nil, 1<<20)
+	scanner.Split(NewRecordSplitFunc())
+	var scanned []string
+	for scanner.Scan() {
+		scanned = append(scanned, scanner.Text())
+	}
+	if !reflect.DeepEqual(scanned, []string{"a", long, "b"}) {
+		t.Errorf("NewRecordSplitFunc() scanned %d records, want a, the long record and b", len(scanned))
+	}
+	if err := scanner.Err(); err == nil || err.Error() != "unbalanced double quote in string" {
+		t.Errorf("NewRecordSplitFunc() error = %v, want unbalanced double quote", err)
+	}
+}
@@ -0,0 +1,52 @@
+package gobag
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is returned by Recovered, and passed to the panic handler
+// of SafeGo, when a function panics. It holds the value given to panic
+// and the stack trace of the panicking goroutine.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+// Error implements the error interface.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error, making it
+// reachable by errors.Is and errors.As.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Recovered calls fn and converts a panic in fn into a *PanicError.
+// It returns nil if fn returns normally.
+func Recovered(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	fn()
+
+	return nil
+}
+
+// SafeGo runs fn in a new goroutine. If fn panics the panic is
+// recovered and passed to onPanic, instead of crashing the process.
+// A nil onPanic silently discards the panic.
+func SafeGo(fn func(), onPanic func(*PanicError)) {
+	go func() {
+		if err := Recovered(fn); err != nil && onPanic != nil {
+			onPanic(err.(*PanicError))
+		}
+	}()
+}
@@ -0,0 +1,34 @@
+package gobag
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRecovered(t *testing.T) {
+	if err := Recovered(func() {}); err != nil {
+		t.Fatalf("Recovered() error = %v, want nil", err)
+	}
+
+	err := Recovered(func() { panic(io.EOF) })
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		t.Fatalf("Recovered() error = %v, want *PanicError", err)
+	}
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf("Recovered() error does not wrap %v", io.EOF)
+	}
+	if !strings.Contains(string(pe.Stack), "TestRecovered") {
+		t.Fatalf("Recovered() stack does not include caller:\n%s", pe.Stack)
+	}
+}
+
+func TestSafeGo(t *testing.T) {
+	got := make(chan *PanicError)
+	SafeGo(func() { panic("boom") }, func(pe *PanicError) { got <- pe })
+	if pe := <-got; pe.Value != "boom" {
+		t.Fatalf("SafeGo() panic value = %v, want boom", pe.Value)
+	}
+}
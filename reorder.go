@@ -0,0 +1,72 @@
+package gobag
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Reorderer restores the order of results completed out of order, such
+// as lines parsed by a pool of workers. Each value is put with its
+// index in the input, and values are passed on in index order as soon
+// as all earlier ones have arrived. It is safe for concurrent use.
+type Reorderer[T any] struct {
+	mu      sync.Mutex
+	next    int
+	pending map[int]T
+	emit    func(T)
+}
+
+// NewReorderer returns a Reorderer calling emit with the values in
+// index order, starting at index start. Emit is called by Put with a
+// lock held, so calls are never concurrent, and it must not call Put.
+func NewReorderer[T any](start int, emit func(T)) *Reorderer[T] {
+	return &Reorderer[T]{
+		next:    start,
+		pending: make(map[int]T),
+		emit:    emit,
+	}
+}
+
+// Put records value as the result for index, and emits it and any
+// following values that are ready if index is the next one due.
+// Returns an error if index has already been put.
+func (r *Reorderer[T]) Put(index int, value T) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.pending[index]; ok || index < r.next {
+		return fmt.Errorf("index %d put more than once", index)
+	}
+	if index != r.next {
+		r.pending[index] = value
+		return nil
+	}
+
+	r.emit(value)
+	r.next++
+	for {
+		v, ok := r.pending[r.next]
+		if !ok {
+			return nil
+		}
+		delete(r.pending, r.next)
+		r.emit(v)
+		r.next++
+	}
+}
+
+// Next returns the index of the next value to be emitted.
+func (r *Reorderer[T]) Next() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.next
+}
+
+// Pending returns the number of values waiting for earlier ones.
+func (r *Reorderer[T]) Pending() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.pending)
+}
@@ -0,0 +1,74 @@
+package gobag
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestReorderer(t *testing.T) {
+	var got []string
+	r := NewReorderer(1, func(s string) { got = append(got, s) })
+
+	for _, i := range []int{3, 2, 5} {
+		if err := r.Put(i, fmt.Sprint("v", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(got) != 0 || r.Pending() != 3 {
+		t.Errorf("emitted %q with %d pending before index 1", got, r.Pending())
+	}
+	r.Put(1, "v1")
+	if want := []string{"v1", "v2", "v3"}; !slices.Equal(got, want) {
+		t.Errorf("emitted %q, want %q", got, want)
+	}
+	if r.Next() != 4 || r.Pending() != 1 {
+		t.Errorf("Next() = %d, Pending() = %d, want 4, 1", r.Next(), r.Pending())
+	}
+
+	for _, i := range []int{2, 5} {
+		if err := r.Put(i, "again"); err == nil || err.Error() != fmt.Sprintf("index %d put more than once", i) {
+			t.Errorf("Put(%d) again error = %v", i, err)
+		}
+	}
+}
+
+func TestReordererParallelFields(t *testing.T) {
+	lines := make([]string, 500)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line=%d,\"quoted, text\"", i)
+	}
+
+	var got []string
+	r := NewReorderer(0, func(fields []string) { got = append(got, fields[0]) })
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fields, err := Fields(lines[i], ',')
+				if err != nil {
+					t.Error(err)
+				}
+				r.Put(i, fields)
+			}
+		}()
+	}
+	for i := range lines {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i, f := range got {
+		if want := fmt.Sprint("line=", i); f != want {
+			t.Fatalf("value %d = %q, want %q", i, f, want)
+		}
+	}
+	if len(got) != len(lines) {
+		t.Errorf("emitted %d values, want %d", len(got), len(lines))
+	}
+}
@@ -0,0 +1,109 @@
+package gobag
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Byte classes used by quote-aware string functions.
+const (
+	classSyntax  = iota // Quote, parenthesis or escape character.
+	classOutside        // Unquoted text outside parentheses.
+	classInside         // Text within quotes or parentheses.
+)
+
+// classify returns the class of each byte in s, following the
+// quoting, grouping and escaping rules of Fields. Unterminated quotes
+// or groups extend to the end of the string.
+func classify(s string) []uint8 {
+	classes := make([]uint8, len(s))
+	var balance int
+	var inSingle, inDouble, isEscaped bool
+
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		class := uint8(classOutside)
+		if balance > 0 || inSingle || inDouble {
+			class = classInside
+		}
+
+		switch {
+		case isEscaped:
+			isEscaped = false
+			class = classSyntax
+		case r == '\\':
+			isEscaped = true
+			class = classSyntax
+		case r == '"' && !inSingle:
+			inDouble = !inDouble
+			if balance == 0 && !inSingle {
+				class = classSyntax
+			}
+		case r == '\'' && !inDouble:
+			inSingle = !inSingle
+			if balance == 0 && !inDouble {
+				class = classSyntax
+			}
+		case r == '(' && !inSingle && !inDouble:
+			balance++
+			if balance == 1 {
+				class = classSyntax
+			}
+		case r == ')' && !inSingle && !inDouble:
+			balance--
+			if balance <= 0 {
+				class = classSyntax
+			}
+		}
+
+		for end := i + size; i < end; i++ {
+			classes[i] = class
+		}
+	}
+
+	return classes
+}
+
+// ReplaceUnquoted returns a copy of s with all non-overlapping
+// instances of old replaced by new, but only where old occurs outside
+// quotes and parentheses and is not escaped. If old is empty, s is
+// returned unchanged.
+func ReplaceUnquoted(s, old, new string) string {
+	return replaceClass(s, old, new, classOutside)
+}
+
+// ReplaceQuoted is the inverse of ReplaceUnquoted. It only replaces
+// instances of old occurring within quotes or parentheses.
+func ReplaceQuoted(s, old, new string) string {
+	return replaceClass(s, old, new, classInside)
+}
+
+func replaceClass(s, old, new string, class uint8) string {
+	if old == "" || !strings.Contains(s, old) {
+		return s
+	}
+
+	classes := classify(s)
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i := 0; i < len(s); {
+		if strings.HasPrefix(s[i:], old) && allClass(classes[i:i+len(old)], class) {
+			sb.WriteString(new)
+			i += len(old)
+			continue
+		}
+		sb.WriteByte(s[i])
+		i++
+	}
+
+	return sb.String()
+}
+
+func allClass(classes []uint8, class uint8) bool {
+	for _, c := range classes {
+		if c != class {
+			return false
+		}
+	}
+	return true
+}
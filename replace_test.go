@@ -0,0 +1,28 @@
+package gobag
+
+import "testing"
+
+func TestReplaceUnquoted(t *testing.T) {
+	tests := []struct {
+		input, old, new string
+		unquoted        string
+		quoted          string
+	}{
+		{`a,b,c`, ",", ";", `a;b;c`, `a,b,c`},
+		{`a,"b,c",d`, ",", ";", `a;"b,c";d`, `a,"b;c",d`},
+		{`a,'b,c',d`, ",", ";", `a;'b,c';d`, `a,'b;c',d`},
+		{`a,(b,(c,d)),e`, ",", ";", `a;(b,(c,d));e`, `a,(b;(c;d)),e`},
+		{`a\,b,c`, ",", ";", `a\,b;c`, `a\,b,c`},
+		{`x  "y  z"  w`, "  ", " ", `x "y  z" w`, `x  "y z"  w`},
+		{`"a"b"`, "b", "B", `"a"B"`, `"a"b"`},
+		{`abc`, "", "x", `abc`, `abc`},
+	}
+	for _, tt := range tests {
+		if got := ReplaceUnquoted(tt.input, tt.old, tt.new); got != tt.unquoted {
+			t.Errorf("ReplaceUnquoted(%q, %q, %q) = %q, want %q", tt.input, tt.old, tt.new, got, tt.unquoted)
+		}
+		if got := ReplaceQuoted(tt.input, tt.old, tt.new); got != tt.quoted {
+			t.Errorf("ReplaceQuoted(%q, %q, %q) = %q, want %q", tt.input, tt.old, tt.new, got, tt.quoted)
+		}
+	}
+}
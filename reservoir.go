@@ -0,0 +1,168 @@
+package gobag
+
+import (
+	"container/heap"
+	"math"
+	"math/rand/v2"
+	"slices"
+)
+
+// Reservoir keeps a uniform random sample of fixed size from a stream
+// of unknown length: after n items, every item has had the same chance
+// of size/n to be in the sample. It uses Algorithm L, which draws
+// random numbers only for the O(size log(n/size)) items that enter the
+// sample, so adding items is cheap. A Reservoir is not safe for
+// concurrent use.
+type Reservoir[T any] struct {
+	size   int
+	items  []T
+	count  int64
+	w      float64
+	next   int64 // Count at which the next item enters the sample.
+	random *rand.Rand
+}
+
+// NewReservoir returns an empty Reservoir keeping a sample of at most
+// size items. It panics if size is less than 1.
+func NewReservoir[T any](size int) *Reservoir[T] {
+	return NewReservoirRand[T](size, nil)
+}
+
+// NewReservoirRand is like NewReservoir, but draws random numbers from
+// r, or from a randomly seeded source if r is nil.
+func NewReservoirRand[T any](size int, r Rand) *Reservoir[T] {
+	if size < 1 {
+		panic("gobag: Reservoir size must be at least 1")
+	}
+	return &Reservoir[T]{
+		size:   size,
+		items:  make([]T, 0, size),
+		random: newRand(r),
+	}
+}
+
+// Add offers item to the sample.
+func (r *Reservoir[T]) Add(item T) {
+	r.count++
+	if len(r.items) < r.size {
+		r.items = append(r.items, item)
+		if len(r.items) == r.size {
+			r.w = math.Exp(math.Log(r.uniform()) / float64(r.size))
+			r.skip()
+		}
+		return
+	}
+	if r.count < r.next {
+		return
+	}
+
+	r.items[r.random.IntN(r.size)] = item
+	r.w *= math.Exp(math.Log(r.uniform()) / float64(r.size))
+	r.skip()
+}
+
+// skip sets the count at which the next item enters the sample.
+func (r *Reservoir[T]) skip() {
+	r.next = r.count + int64(math.Floor(math.Log(r.uniform())/math.Log1p(-r.w))) + 1
+}
+
+// uniform returns a random number in (0, 1].
+func (r *Reservoir[T]) uniform() float64 {
+	return 1 - r.random.Float64()
+}
+
+// Sample returns a copy of the current sample, in no particular order.
+// It holds all items added if there were no more than the size of the
+// reservoir.
+func (r *Reservoir[T]) Sample() []T {
+	return slices.Clone(r.items)
+}
+
+// Count returns the number of items added.
+func (r *Reservoir[T]) Count() int64 {
+	return r.count
+}
+
+// WeightedReservoir is like Reservoir, but samples items with
+// probability proportional to their weight, using the A-Res algorithm
+// of Efraimidis and Spirakis. A WeightedReservoir is not safe for
+// concurrent use.
+type WeightedReservoir[T any] struct {
+	size   int
+	items  weightedHeap[T]
+	count  int64
+	random *rand.Rand
+}
+
+type weightedItem[T any] struct {
+	item T
+	key  float64
+}
+
+// NewWeightedReservoir returns an empty WeightedReservoir keeping a
+// sample of at most size items. It panics if size is less than 1.
+func NewWeightedReservoir[T any](size int) *WeightedReservoir[T] {
+	return NewWeightedReservoirRand[T](size, nil)
+}
+
+// NewWeightedReservoirRand is like NewWeightedReservoir, but draws
+// random numbers from r, or from a randomly seeded source if r is nil.
+func NewWeightedReservoirRand[T any](size int, r Rand) *WeightedReservoir[T] {
+	if size < 1 {
+		panic("gobag: WeightedReservoir size must be at least 1")
+	}
+	return &WeightedReservoir[T]{
+		size:   size,
+		items:  make(weightedHeap[T], 0, size),
+		random: newRand(r),
+	}
+}
+
+// Add offers item with the given weight to the sample. Items with a
+// weight of zero or less are never sampled.
+func (r *WeightedReservoir[T]) Add(item T, weight float64) {
+	r.count++
+	if weight <= 0 {
+		return
+	}
+
+	// The key u^(1/weight) is compared in log space, where it does not
+	// underflow for small weights.
+	key := math.Log(1-r.random.Float64()) / weight
+	switch {
+	case len(r.items) < r.size:
+		heap.Push(&r.items, weightedItem[T]{item, key})
+	case key > r.items[0].key:
+		r.items[0] = weightedItem[T]{item, key}
+		heap.Fix(&r.items, 0)
+	}
+}
+
+// Sample returns a copy of the current sample, in no particular order.
+func (r *WeightedReservoir[T]) Sample() []T {
+	sample := make([]T, 0, len(r.items))
+	for _, it := range r.items {
+		sample = append(sample, it.item)
+	}
+	return sample
+}
+
+// Count returns the number of items added.
+func (r *WeightedReservoir[T]) Count() int64 {
+	return r.count
+}
+
+// weightedHeap is a min-heap of items by key.
+type weightedHeap[T any] []weightedItem[T]
+
+func (h weightedHeap[T]) Len() int           { return len(h) }
+func (h weightedHeap[T]) Less(i, j int) bool { return h[i].key < h[j].key }
+func (h weightedHeap[T]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *weightedHeap[T]) Push(x any)        { *h = append(*h, x.(weightedItem[T])) }
+
+func (h *weightedHeap[T]) Pop() any {
+	old := *h
+	it := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return it
+}
@@ -0,0 +1,74 @@
+package gobag
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestReservoirSmall(t *testing.T) {
+	r := NewReservoir[int](5)
+	for i := range 3 {
+		r.Add(i)
+	}
+	if got := r.Sample(); !slices.Equal(got, []int{0, 1, 2}) {
+		t.Errorf("Sample() = %v, want all items", got)
+	}
+	if r.Count() != 3 {
+		t.Errorf("Count() = %d, want 3", r.Count())
+	}
+}
+
+func TestReservoirUniform(t *testing.T) {
+	const n, size, runs = 100, 10, 4000
+	hits := make([]int, n)
+	for range runs {
+		r := NewReservoir[int](size)
+		for i := range n {
+			r.Add(i)
+		}
+		sample := r.Sample()
+		if len(sample) != size {
+			t.Fatalf("Sample() returned %d items, want %d", len(sample), size)
+		}
+		for _, v := range sample {
+			hits[v]++
+		}
+	}
+
+	// Each item is expected runs*size/n = 400 times, with a standard
+	// deviation of about 19.
+	for i, h := range hits {
+		if math.Abs(float64(h)-400) > 100 {
+			t.Errorf("item %d sampled %d times, want about 400", i, h)
+		}
+	}
+}
+
+func TestWeightedReservoir(t *testing.T) {
+	const runs = 4000
+	hits := map[string]int{}
+	for range runs {
+		r := NewWeightedReservoir[string](1)
+		r.Add("light", 1)
+		r.Add("heavy", 3)
+		r.Add("never", 0)
+		for _, v := range r.Sample() {
+			hits[v]++
+		}
+	}
+	if hits["never"] != 0 {
+		t.Errorf("item of weight 0 sampled %d times", hits["never"])
+	}
+	if got := float64(hits["heavy"]) / runs; math.Abs(got-0.75) > 0.05 {
+		t.Errorf("item of weight 3 of 4 sampled with frequency %.3f, want about 0.75", got)
+	}
+
+	r := NewWeightedReservoir[int](3)
+	for i := range 10 {
+		r.Add(i, 1)
+	}
+	if len(r.Sample()) != 3 || r.Count() != 10 {
+		t.Errorf("Sample() = %v, Count() = %d", r.Sample(), r.Count())
+	}
+}
@@ -0,0 +1,76 @@
+package gobag
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// RewriteFile rewrites the named text file with the lines returned by
+// transform, which is called with the file's lines. If transform fails
+// the file is left untouched. Otherwise, unless the content is
+// unchanged, the original is saved as path + ".bak" and the file is
+// replaced atomically, keeping its permissions. The line endings and
+// final newline of the original are kept. RewriteFile reports whether
+// the file was changed.
+//
+// RewriteFile fails without writing if the file is modified by
+// someone else while transform runs.
+func RewriteFile(path string, transform func(lines []string) ([]string, error)) (bool, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	sum := sha256.Sum256(data)
+
+	content := string(data)
+	eol := "\n"
+	if i := strings.IndexByte(content, '\n'); i > 0 && content[i-1] == '\r' {
+		eol = "\r\n"
+	}
+	final := content == "" || strings.HasSuffix(content, "\n")
+	content = strings.TrimSuffix(content, eol)
+	lines := make([]string, 0)
+	if content != "" {
+		lines = strings.Split(content, eol)
+	}
+
+	lines, err = transform(lines)
+	if err != nil {
+		return false, err
+	}
+
+	var buf bytes.Buffer
+	for i, line := range lines {
+		buf.WriteString(line)
+		if i < len(lines)-1 || final {
+			buf.WriteString(eol)
+		}
+	}
+	if sha256.Sum256(buf.Bytes()) == sum {
+		return false, nil
+	}
+
+	current, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	if sha256.Sum256(current) != sum {
+		return false, fmt.Errorf("%s: file changed during rewrite", path)
+	}
+
+	if err := WriteFileAtomic(path+".bak", data, fi.Mode().Perm()); err != nil {
+		return false, errors.Join(errors.New("unable to write backup"), err)
+	}
+	if err := WriteFileAtomic(path, buf.Bytes(), fi.Mode().Perm()); err != nil {
+		return false, err
+	}
+	return true, nil
+}
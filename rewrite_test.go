@@ -0,0 +1,84 @@
+package gobag
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRewriteFile(t *testing.T) {
+	upper := func(lines []string) ([]string, error) {
+		for i := range lines {
+			lines[i] = strings.ToUpper(lines[i])
+		}
+		return lines, nil
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a=1\nb=2\n", "A=1\nB=2\n"},
+		{"a=1\r\nb=2\r\n", "A=1\r\nB=2\r\n"},
+		{"a=1\nb=2", "A=1\nB=2"},
+		{"a\n\n", "A\n\n"},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "conf")
+		os.WriteFile(path, []byte(tt.input), 0o640)
+
+		changed, err := RewriteFile(path, upper)
+		if err != nil || !changed {
+			t.Fatalf("RewriteFile(%q) = %v, %v, want true, nil", tt.input, changed, err)
+		}
+		if got, _ := os.ReadFile(path); string(got) != tt.expected {
+			t.Errorf("RewriteFile(%q) wrote %q, want %q", tt.input, got, tt.expected)
+		}
+		if got, _ := os.ReadFile(path + ".bak"); string(got) != tt.input {
+			t.Errorf("backup of %q = %q", tt.input, got)
+		}
+		if fi, _ := os.Stat(path); fi.Mode().Perm() != 0o640 {
+			t.Errorf("mode = %v, want %v", fi.Mode().Perm(), os.FileMode(0o640))
+		}
+	}
+}
+
+func TestRewriteFileUnchanged(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "conf")
+	os.WriteFile(path, []byte("same\n"), 0o644)
+
+	changed, err := RewriteFile(path, func(lines []string) ([]string, error) {
+		return lines, nil
+	})
+	if err != nil || changed {
+		t.Fatalf("RewriteFile() = %v, %v, want false, nil", changed, err)
+	}
+	if _, err := os.Stat(path + ".bak"); !os.IsNotExist(err) {
+		t.Error("RewriteFile() wrote a backup for unchanged content")
+	}
+
+	fail := errors.New("bad edit")
+	_, err = RewriteFile(path, func(lines []string) ([]string, error) {
+		return nil, fail
+	})
+	if !errors.Is(err, fail) {
+		t.Fatalf("RewriteFile() error = %v, want %v", err, fail)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "same\n" {
+		t.Errorf("file changed to %q after failed transform", got)
+	}
+
+	_, err = RewriteFile(path, func(lines []string) ([]string, error) {
+		os.WriteFile(path, []byte("concurrent\n"), 0o644)
+		return append(lines, "more"), nil
+	})
+	if err == nil {
+		t.Error("RewriteFile() succeeded despite concurrent modification")
+	}
+	if got, _ := os.ReadFile(path); string(got) != "concurrent\n" {
+		t.Errorf("file = %q, want concurrent edit kept", got)
+	}
+}
@@ -0,0 +1,220 @@
+package gobag
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DurationRange is a half-open range [Start, End) of durations. In a
+// Schedule it holds a time of day range as offsets from midnight,
+// where End may be less than Start for ranges past midnight.
+type DurationRange struct {
+	Start, End time.Duration
+}
+
+// Contains reports whether d lies within the range. If End is less
+// than Start, the range wraps around and contains d >= Start as well
+// as d < End.
+func (r DurationRange) Contains(d time.Duration) bool {
+	if r.wraps() {
+		return d >= r.Start || d < r.End
+	}
+	return d >= r.Start && d < r.End
+}
+
+func (r DurationRange) wraps() bool {
+	return r.End < r.Start
+}
+
+// ScheduleRule is a single rule of a Schedule: a set of weekdays and
+// the time of day ranges that apply on those days.
+type ScheduleRule struct {
+	Days  [7]bool // Indexed by time.Weekday.
+	Times []DurationRange
+}
+
+// Schedule is a weekly schedule of time ranges, such as opening hours
+// or maintenance windows. The zero value contains no times.
+type Schedule struct {
+	Rules []ScheduleRule
+}
+
+// ParseSchedule parses a schedule from a compact string of rules
+// separated by commas, e.g. "mon-fri 08:00-17:00, sat 10:00-14:00".
+// Each rule consists of day names or day ranges, followed by time
+// ranges in HH:MM or HH:MM:SS form. Day names are English, full or
+// three letters and case-insensitive, and day ranges may wrap past
+// sunday as in "fri-mon". A rule without days applies to every day, and
+// a rule without times covers the whole day. A time range ending
+// before it starts, such as "22:00-06:00", continues past midnight
+// into the next day. An empty string yields an empty schedule.
+func ParseSchedule(s string) (*Schedule, error) {
+	rules, err := Fields(s, ',')
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := &Schedule{}
+	for _, r := range rules {
+		rule, err := parseScheduleRule(r)
+		if err != nil {
+			return nil, err
+		}
+		schedule.Rules = append(schedule.Rules, rule)
+	}
+
+	return schedule, nil
+}
+
+func parseScheduleRule(s string) (ScheduleRule, error) {
+	var rule ScheduleRule
+	tokens, err := Fields(s, ' ')
+	if err != nil {
+		return rule, err
+	}
+
+	anyDay := false
+	for _, tok := range tokens {
+		tok = strings.TrimSpace(tok)
+		switch {
+		case tok == "":
+			continue
+		case strings.Contains(tok, ":"):
+			r, err := parseClockRange(tok)
+			if err != nil {
+				return rule, err
+			}
+			rule.Times = append(rule.Times, r)
+		default:
+			if len(rule.Times) > 0 {
+				return rule, fmt.Errorf("day %q after time ranges in schedule rule %q", tok, strings.TrimSpace(s))
+			}
+			first, last, err := parseDayRange(tok)
+			if err != nil {
+				return rule, err
+			}
+			for d := first; ; d = (d + 1) % 7 {
+				rule.Days[d] = true
+				if d == last {
+					break
+				}
+			}
+			anyDay = true
+		}
+	}
+
+	if !anyDay && len(rule.Times) == 0 {
+		return rule, fmt.Errorf("empty schedule rule")
+	}
+	if !anyDay {
+		rule.Days = [7]bool{true, true, true, true, true, true, true}
+	}
+	if len(rule.Times) == 0 {
+		rule.Times = []DurationRange{{Start: 0, End: 24 * time.Hour}}
+	}
+
+	return rule, nil
+}
+
+var weekdayNames = func() map[string]time.Weekday {
+	names := make(map[string]time.Weekday)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		names[name] = d
+		names[name[:3]] = d
+	}
+	return names
+}()
+
+func parseDayRange(s string) (first, last time.Weekday, err error) {
+	from, to, isRange := strings.Cut(s, "-")
+	first, ok := weekdayNames[strings.ToLower(from)]
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid day %q", from)
+	}
+	if !isRange {
+		return first, first, nil
+	}
+	last, ok = weekdayNames[strings.ToLower(to)]
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid day %q", to)
+	}
+
+	return first, last, nil
+}
+
+func parseClockRange(s string) (DurationRange, error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return DurationRange{}, fmt.Errorf("invalid time range %q", s)
+	}
+	start, err := parseClock(from)
+	if err != nil {
+		return DurationRange{}, err
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return DurationRange{}, err
+	}
+	if start == end || start == 24*time.Hour {
+		return DurationRange{}, fmt.Errorf("empty time range %q", s)
+	}
+	if end == 24*time.Hour && start != 0 {
+		end = 0
+	}
+
+	return DurationRange{Start: start, End: end}, nil
+}
+
+// parseClock parses a time of day in H:MM, HH:MM or HH:MM:SS form into
+// an offset from midnight. "24:00" is accepted as the end of the day.
+func parseClock(s string) (time.Duration, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid time of day %q", s)
+	}
+
+	limits := []int{24, 59, 59}
+	var d time.Duration
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 || n > limits[i] || (i > 0 && len(p) != 2) {
+			return 0, fmt.Errorf("invalid time of day %q", s)
+		}
+		d += time.Duration(n) * []time.Duration{time.Hour, time.Minute, time.Second}[i]
+	}
+	if d > 24*time.Hour {
+		return 0, fmt.Errorf("invalid time of day %q", s)
+	}
+
+	return d, nil
+}
+
+// Contains reports whether t, in its own location, falls within the
+// schedule.
+func (s *Schedule) Contains(t time.Time) bool {
+	day := t.Weekday()
+	prev := (day + 6) % 7
+	h, m, sec := t.Clock()
+	tod := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute +
+		time.Duration(sec)*time.Second + time.Duration(t.Nanosecond())
+
+	for _, rule := range s.Rules {
+		for _, r := range rule.Times {
+			switch {
+			case !r.wraps():
+				if rule.Days[day] && r.Contains(tod) {
+					return true
+				}
+			case rule.Days[day] && tod >= r.Start:
+				return true
+			case rule.Days[prev] && tod < r.End:
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,63 @@
+package gobag
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedule(t *testing.T) {
+	s, err := ParseSchedule("mon-fri 08:00-17:00, sat 10:00-14:00, Sunday 22:00-06:00")
+	if err != nil {
+		t.Fatalf("ParseSchedule() error = %v", err)
+	}
+
+	// 2024-01-01 is a monday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		t        time.Time
+		expected bool
+	}{
+		{at(1, 8, 0), true},
+		{at(1, 7, 59), false},
+		{at(5, 16, 59), true},
+		{at(5, 17, 0), false},
+		{at(6, 12, 0), true},
+		{at(6, 9, 0), false},
+		{at(7, 23, 0), true},
+		{at(8, 5, 59), true},
+		{at(8, 6, 0), false},
+		{at(7, 3, 0), false},
+	}
+	for _, tt := range tests {
+		if got := s.Contains(tt.t); got != tt.expected {
+			t.Errorf("Contains(%s) = %t, want %t", tt.t.Format("Mon 15:04"), got, tt.expected)
+		}
+	}
+
+	all, err := ParseSchedule("fri-mon")
+	if err != nil {
+		t.Fatalf("ParseSchedule(fri-mon) error = %v", err)
+	}
+	if !all.Contains(at(1, 23, 59)) || all.Contains(at(2, 12, 0)) {
+		t.Error("ParseSchedule(fri-mon) does not cover whole wrapped days")
+	}
+
+	for _, bad := range []string{"mon 08:00", "funday", "mon 8:00-25:00", "09:00-17:00 mon", "mon 10:00-10:00"} {
+		if _, err := ParseSchedule(bad); err == nil {
+			t.Errorf("ParseSchedule(%q): expected error", bad)
+		}
+	}
+}
+
+func TestDurationRange(t *testing.T) {
+	r := DurationRange{Start: time.Hour, End: 2 * time.Hour}
+	if !r.Contains(time.Hour) || r.Contains(2*time.Hour) {
+		t.Error("DurationRange.Contains() is not half-open")
+	}
+	wrapped := DurationRange{Start: 22 * time.Hour, End: time.Hour}
+	if !wrapped.Contains(23*time.Hour) || !wrapped.Contains(0) || wrapped.Contains(12*time.Hour) {
+		t.Error("DurationRange.Contains() does not wrap")
+	}
+}
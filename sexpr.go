@@ -0,0 +1,149 @@
+package gobag
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SNode is a node of an S-expression: either an atom or a list of
+// nodes.
+type SNode struct {
+	Atom   string   // The value of an atom, with quotes and escapes removed.
+	Quoted bool     // Whether the atom was quoted.
+	List   []*SNode // The elements of a list.
+	IsList bool
+}
+
+// String returns the S-expression of the node. Atoms are quoted when
+// needed to parse back to the same value.
+func (n *SNode) String() string {
+	if !n.IsList {
+		if n.Quoted || n.Atom == "" || strings.ContainsFunc(n.Atom, func(r rune) bool {
+			return unicode.IsSpace(r) || strings.ContainsRune(`()"'\`, r)
+		}) {
+			return QuoteString(n.Atom)
+		}
+		return n.Atom
+	}
+
+	elems := make([]string, 0, len(n.List))
+	for _, e := range n.List {
+		elems = append(elems, e.String())
+	}
+	return "(" + strings.Join(elems, " ") + ")"
+}
+
+// ParseSExpr parses a single S-expression such as
+// `(rule "allow ssh" (port 22) (from 10.0.0.0/8))` into a tree of
+// nodes. Atoms are separated by whitespace or parentheses, and follow
+// the quoting and escaping rules of Fields: they may be quoted with
+// double or single quotes, and a backslash escapes the next character.
+// Returns an error if the input is not a single balanced expression.
+func ParseSExpr(s string) (*SNode, error) {
+	p := &sexprParser{s: s}
+	p.skipSpace()
+	if p.pos >= len(p.s) {
+		return nil, errors.New("empty expression")
+	}
+
+	n, err := p.node()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.s) {
+		if p.s[p.pos] == ')' {
+			return nil, errors.New("too many closing parentheses")
+		}
+		return nil, fmt.Errorf("offset %d: unexpected text after expression", p.pos)
+	}
+
+	return n, nil
+}
+
+type sexprParser struct {
+	s   string
+	pos int
+}
+
+func (p *sexprParser) skipSpace() {
+	p.pos += len(p.s[p.pos:]) - len(strings.TrimLeftFunc(p.s[p.pos:], unicode.IsSpace))
+}
+
+func (p *sexprParser) node() (*SNode, error) {
+	switch p.s[p.pos] {
+	case '(':
+		return p.list()
+	case ')':
+		return nil, errors.New("too many closing parentheses")
+	}
+	return p.atom()
+}
+
+func (p *sexprParser) list() (*SNode, error) {
+	p.pos++
+	n := &SNode{IsList: true, List: make([]*SNode, 0)}
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.s) {
+			return nil, errors.New("unbalanced parentheses in string")
+		}
+		if p.s[p.pos] == ')' {
+			p.pos++
+			return n, nil
+		}
+
+		child, err := p.node()
+		if err != nil {
+			return nil, err
+		}
+		n.List = append(n.List, child)
+	}
+}
+
+func (p *sexprParser) atom() (*SNode, error) {
+	n := &SNode{}
+	var sb strings.Builder
+	var quote rune
+
+	for p.pos < len(p.s) {
+		r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+		switch {
+		case r == '\\':
+			if p.pos+size >= len(p.s) {
+				return nil, errors.New("dangling escape character at end of string")
+			}
+			p.pos += size
+			r, size = utf8.DecodeRuneInString(p.s[p.pos:])
+			sb.WriteRune(r)
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				sb.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			n.Quoted = true
+		case unicode.IsSpace(r) || r == '(' || r == ')':
+			n.Atom = sb.String()
+			return n, nil
+		default:
+			sb.WriteRune(r)
+		}
+		p.pos += size
+	}
+
+	switch quote {
+	case '"':
+		return nil, errors.New("unbalanced double quote in string")
+	case '\'':
+		return nil, errors.New("unbalanced single quote in string")
+	}
+	n.Atom = sb.String()
+
+	return n, nil
+}
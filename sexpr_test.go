@@ -0,0 +1,59 @@
+package gobag
+
+import (
+	"testing"
+)
+
+func TestParseSExpr(t *testing.T) {
+	n, err := ParseSExpr(`(rule "allow ssh" (port 22) (from 10.0.0.0/8 'a b') ())`)
+	if err != nil {
+		t.Fatalf("ParseSExpr() error = %v", err)
+	}
+	if !n.IsList || len(n.List) != 5 {
+		t.Fatalf("ParseSExpr() = %v, want list of 5", n)
+	}
+	if a := n.List[1]; a.IsList || a.Atom != "allow ssh" || !a.Quoted {
+		t.Errorf("second element = %+v, want quoted atom", a)
+	}
+	if port := n.List[2]; !port.IsList || port.List[1].Atom != "22" {
+		t.Errorf("third element = %v", port)
+	}
+	if got, want := n.String(), `(rule "allow ssh" (port 22) (from 10.0.0.0/8 "a b") ())`; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"atom", "atom"},
+		{`a\ b`, `"a b"`},
+		{`  (a(b)c)  `, "(a (b) c)"},
+		{`(x"y z")`, `("xy z")`},
+	}
+	for _, tt := range tests {
+		n, err := ParseSExpr(tt.input)
+		if err != nil || n.String() != tt.expected {
+			t.Errorf("ParseSExpr(%q) = %v, %v, want %s", tt.input, n, err, tt.expected)
+		}
+	}
+
+	errs := []struct {
+		input string
+		err   string
+	}{
+		{"", "empty expression"},
+		{"(a (b)", "unbalanced parentheses in string"},
+		{"(a))", "too many closing parentheses"},
+		{")", "too many closing parentheses"},
+		{`(a "b)`, "unbalanced double quote in string"},
+		{`(a \`, "dangling escape character at end of string"},
+		{"a b", "offset 2: unexpected text after expression"},
+	}
+	for _, tt := range errs {
+		_, err := ParseSExpr(tt.input)
+		if err == nil || err.Error() != tt.err {
+			t.Errorf("ParseSExpr(%q) error = %v, want %q", tt.input, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package gobag
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Shutdown coordinates the graceful shutdown of a program. Subsystems
+// register cleanup functions, which run when Shutdown is triggered by
+// a call to Run or by a signal. It is safe for concurrent use.
+type Shutdown struct {
+	mu    sync.Mutex
+	hooks []shutdownHook
+	once  sync.Once
+	done  chan struct{}
+	err   error
+}
+
+type shutdownHook struct {
+	name     string
+	priority int
+	timeout  time.Duration
+	fn       func(ctx context.Context) error
+}
+
+// NewShutdown returns a Shutdown without cleanup functions.
+func NewShutdown() *Shutdown {
+	return &Shutdown{done: make(chan struct{})}
+}
+
+// Register adds the cleanup function fn, named name in errors. Cleanup
+// functions run by descending priority, and those of equal priority
+// run concurrently, so servers can stop accepting requests before the
+// databases they use are closed. The context passed to fn is canceled
+// after timeout, or never for a zero timeout. Functions registered
+// after shutdown has started are not run.
+func (s *Shutdown) Register(name string, priority int, timeout time.Duration, fn func(ctx context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.hooks = append(s.hooks, shutdownHook{name, priority, timeout, fn})
+}
+
+// Run runs the cleanup functions and returns their errors joined, each
+// prefixed with its name. A function exceeding its timeout is
+// abandoned and reported with ErrTimeout. Only the first call starts
+// the functions, passing them its ctx. Every call waits for them to
+// complete and returns the same result, or ctx.Err() if its ctx is
+// done first, in which case the functions keep running in the
+// background; Wait waits for them.
+func (s *Shutdown) Run(ctx context.Context) error {
+	s.once.Do(func() {
+		s.mu.Lock()
+		hooks := slices.Clone(s.hooks)
+		s.hooks = nil
+		s.mu.Unlock()
+
+		go func() {
+			s.err = runShutdownHooks(ctx, hooks)
+			close(s.done)
+		}()
+	})
+
+	select {
+	case <-s.done:
+		return s.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Notify makes the given signals, by default os.Interrupt, trigger Run
+// with a background context. Use Wait to wait for the shutdown to
+// complete.
+func (s *Shutdown) Notify(sig ...os.Signal) {
+	if len(sig) == 0 {
+		sig = []os.Signal{os.Interrupt}
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sig...)
+	go func() {
+		select {
+		case <-ch:
+			s.Run(context.Background())
+		case <-s.done:
+		}
+		signal.Stop(ch)
+	}()
+}
+
+// Done returns a channel that is closed when shutdown has completed.
+func (s *Shutdown) Done() <-chan struct{} {
+	return s.done
+}
+
+// Wait waits for shutdown to complete and returns the result of Run.
+func (s *Shutdown) Wait() error {
+	<-s.done
+	return s.err
+}
+
+func runShutdownHooks(ctx context.Context, hooks []shutdownHook) error {
+	slices.SortStableFunc(hooks, func(a, b shutdownHook) int {
+		return cmp.Compare(b.priority, a.priority)
+	})
+
+	var errs []error
+	for len(hooks) > 0 {
+		n := 1
+		for n < len(hooks) && hooks[n].priority == hooks[0].priority {
+			n++
+		}
+		group := hooks[:n]
+		hooks = hooks[n:]
+
+		results := make([]error, len(group))
+		var wg sync.WaitGroup
+		for i, h := range group {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := h.run(ctx); err != nil {
+					results[i] = fmt.Errorf("%s: %w", h.name, err)
+				}
+			}()
+		}
+		wg.Wait()
+		errs = append(errs, results...)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (h shutdownHook) run(ctx context.Context) error {
+	if h.timeout <= 0 {
+		return h.fn(ctx)
+	}
+	_, err := RunWithTimeoutCtx(ctx, h.timeout, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, h.fn(ctx)
+	})
+	return err
+}
@@ -0,0 +1,92 @@
+package gobag
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShutdown(t *testing.T) {
+	s := NewShutdown()
+	var mu sync.Mutex
+	var order []string
+	record := func(name string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+			return err
+		}
+	}
+	s.Register("db", 0, 0, record("db", errors.New("close failed")))
+	s.Register("http", 10, time.Second, record("http", nil))
+	s.Register("cache", 0, time.Second, record("cache", nil))
+	s.Register("slow", 5, 10*time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		return ctx.Err()
+	})
+
+	err := s.Run(context.Background())
+	if err == nil {
+		t.Fatal("Run() error = nil")
+	}
+	if !errors.Is(err, ErrTimeout) || !strings.Contains(err.Error(), "slow: operation timed out") ||
+		!strings.Contains(err.Error(), "db: close failed") {
+		t.Errorf("Run() error = %v", err)
+	}
+	if order[0] != "http" || !slices.Contains(order[1:], "db") || !slices.Contains(order[1:], "cache") {
+		t.Errorf("cleanup order = %q", order)
+	}
+
+	if again := s.Run(context.Background()); again != err {
+		t.Errorf("second Run() = %v, want %v", again, err)
+	}
+	if s.Wait() != err {
+		t.Errorf("Wait() = %v, want %v", s.Wait(), err)
+	}
+	select {
+	case <-s.Done():
+	default:
+		t.Error("Done() not closed after Run")
+	}
+}
+
+func TestShutdownConcurrentRun(t *testing.T) {
+	s := NewShutdown()
+	release := make(chan struct{})
+	boom := errors.New("boom")
+	s.Register("slow", 0, 0, func(context.Context) error {
+		<-release
+		return boom
+	})
+
+	first := make(chan error, 1)
+	go func() { first <- s.Run(context.Background()) }()
+
+	// A caller giving up early returns without waiting for the hooks.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := s.Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("concurrent Run() = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("concurrent Run() returned after %v", elapsed)
+	}
+
+	close(release)
+	if err := <-first; !errors.Is(err, boom) {
+		t.Errorf("first Run() = %v, want %v", err, boom)
+	}
+	if err := s.Run(context.Background()); !errors.Is(err, boom) {
+		t.Errorf("later Run() = %v, want %v", err, boom)
+	}
+	if err := s.Wait(); !errors.Is(err, boom) {
+		t.Errorf("Wait() = %v, want %v", err, boom)
+	}
+}
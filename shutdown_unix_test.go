@@ -0,0 +1,30 @@
+//go:build unix
+
+package gobag
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestShutdownNotify(t *testing.T) {
+	s := NewShutdown()
+	ran := make(chan struct{})
+	s.Register("hook", 0, 0, func(context.Context) error {
+		close(ran)
+		return nil
+	})
+	s.Notify(syscall.SIGUSR1)
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+
+	select {
+	case <-ran:
+	case <-time.After(5 * time.Second):
+		t.Fatal("signal did not trigger shutdown")
+	}
+	if err := s.Wait(); err != nil {
+		t.Errorf("Wait() = %v, want nil", err)
+	}
+}
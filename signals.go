@@ -0,0 +1,95 @@
+package gobag
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// osExit is os.Exit, replaced in tests.
+var osExit = os.Exit
+
+// SignalError is the cause of the cancellation of a context returned
+// by WithSignals, holding the signal received.
+type SignalError struct {
+	Signal os.Signal
+}
+
+// Error implements the error interface.
+func (e *SignalError) Error() string {
+	return "received signal " + e.Signal.String()
+}
+
+// SignalOptions holds optional settings for WithSignalsWith.
+type SignalOptions struct {
+	// Signals to listen for. The default is os.Interrupt.
+	Signals []os.Signal
+
+	// ExitOnSecond makes a second signal, received while the program
+	// is shutting down after the first, exit the program at once with
+	// ExitCode. This lets users interrupt a shutdown that hangs.
+	ExitOnSecond bool
+
+	// ExitCode is the exit code used by ExitOnSecond.
+	ExitCode int
+}
+
+// WithSignals is like signal.NotifyContext: it returns a copy of ctx
+// that is canceled when one of the signals, by default os.Interrupt, is
+// received, or when the returned stop function is called. The cause of
+// the cancellation is a *SignalError telling which signal was
+// received, see ReceivedSignal. Calling stop also stops listening for
+// the signals.
+func WithSignals(ctx context.Context, sig ...os.Signal) (context.Context, context.CancelFunc) {
+	return WithSignalsWith(ctx, SignalOptions{Signals: sig})
+}
+
+// WithSignalsWith is like WithSignals, but takes options altering its
+// behavior.
+func WithSignalsWith(ctx context.Context, opts SignalOptions) (context.Context, context.CancelFunc) {
+	sigs := opts.Signals
+	if len(sigs) == 0 {
+		sigs = []os.Signal{os.Interrupt}
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+	stopped := make(chan struct{})
+
+	go func() {
+		defer signal.Stop(ch)
+
+		select {
+		case sig := <-ch:
+			cancel(&SignalError{Signal: sig})
+		case <-ctx.Done():
+			return
+		case <-stopped:
+			return
+		}
+		if !opts.ExitOnSecond {
+			return
+		}
+		select {
+		case <-ch:
+			osExit(opts.ExitCode)
+		case <-stopped:
+		}
+	}()
+
+	return ctx, sync.OnceFunc(func() {
+		close(stopped)
+		cancel(context.Canceled)
+	})
+}
+
+// ReceivedSignal returns the signal that canceled a context returned by
+// WithSignals, or one derived from it, and whether there was one.
+func ReceivedSignal(ctx context.Context) (os.Signal, bool) {
+	if err, ok := context.Cause(ctx).(*SignalError); ok {
+		return err.Signal, true
+	}
+	return nil, false
+}
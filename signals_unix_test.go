@@ -0,0 +1,75 @@
+//go:build unix
+
+package gobag
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestWithSignals(t *testing.T) {
+	ctx, stop := WithSignals(context.Background(), syscall.SIGUSR1, syscall.SIGUSR2)
+	defer stop()
+	if _, ok := ReceivedSignal(ctx); ok {
+		t.Error("ReceivedSignal() before any signal reported one")
+	}
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context not canceled by signal")
+	}
+	if sig, ok := ReceivedSignal(ctx); !ok || sig != syscall.SIGUSR2 {
+		t.Errorf("ReceivedSignal() = %v, %v, want %v", sig, ok, syscall.SIGUSR2)
+	}
+	if err := context.Cause(ctx); err.Error() != "received signal user defined signal 2" {
+		t.Errorf("Cause() = %v", err)
+	}
+}
+
+func TestWithSignalsStop(t *testing.T) {
+	ctx, stop := WithSignals(context.Background(), syscall.SIGUSR1)
+	stop()
+	stop()
+	if ctx.Err() != context.Canceled {
+		t.Errorf("Err() after stop = %v", ctx.Err())
+	}
+	if _, ok := ReceivedSignal(ctx); ok {
+		t.Error("ReceivedSignal() after stop reported a signal")
+	}
+}
+
+func TestWithSignalsExitOnSecond(t *testing.T) {
+	exited := make(chan int, 1)
+	osExit = func(code int) { exited <- code }
+	defer func() { osExit = os.Exit }()
+
+	ctx, stop := WithSignalsWith(context.Background(), SignalOptions{
+		Signals:      []os.Signal{syscall.SIGUSR1},
+		ExitOnSecond: true,
+		ExitCode:     130,
+	})
+	defer stop()
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	<-ctx.Done()
+	select {
+	case <-exited:
+		t.Fatal("exited on first signal")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	select {
+	case code := <-exited:
+		if code != 130 {
+			t.Errorf("exit code = %d, want 130", code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("second signal did not exit")
+	}
+}
@@ -0,0 +1,203 @@
+package gobag
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// SimilarityAlgorithm selects how SimilarityWith scores two strings.
+type SimilarityAlgorithm int
+
+// Similarity algorithms.
+const (
+	// JaroWinkler scores by matching characters and transpositions,
+	// favoring strings with a common prefix. It suits short strings
+	// such as names and keys.
+	JaroWinkler SimilarityAlgorithm = iota
+	// Trigram scores by the share of three character sequences the
+	// words of the strings have in common, like PostgreSQL's pg_trgm.
+	// It is insensitive to the order of words, and ignores
+	// punctuation.
+	Trigram
+	// Levenshtein scores by the number of single character insertions,
+	// deletions and substitutions turning one string into the other,
+	// relative to the length of the longer string.
+	Levenshtein
+)
+
+// SimilarityOptions holds optional settings for SimilarityWith and
+// BestMatchesWith.
+type SimilarityOptions struct {
+	// Algorithm selects the scoring algorithm. The default is
+	// JaroWinkler.
+	Algorithm SimilarityAlgorithm
+
+	// IgnoreCase compares the strings ignoring case.
+	IgnoreCase bool
+}
+
+// Similarity returns how similar a and b are using the Jaro-Winkler
+// algorithm, from 0 for nothing in common to 1 for equal strings.
+func Similarity(a, b string) float64 {
+	return SimilarityWith(a, b, SimilarityOptions{})
+}
+
+// SimilarityWith is like Similarity, but takes options altering its
+// behavior.
+func SimilarityWith(a, b string, opts SimilarityOptions) float64 {
+	if opts.IgnoreCase {
+		a, b = strings.ToLower(a), strings.ToLower(b)
+	}
+	if a == b {
+		return 1
+	}
+
+	switch opts.Algorithm {
+	case Trigram:
+		return trigramSimilarity(a, b)
+	case Levenshtein:
+		ra, rb := []rune(a), []rune(b)
+		return 1 - float64(levenshtein(ra, rb))/float64(max(len(ra), len(rb)))
+	default:
+		return jaroWinkler([]rune(a), []rune(b))
+	}
+}
+
+// ScoredMatch is a candidate string scored by BestMatches.
+type ScoredMatch struct {
+	Value string
+	Score float64
+}
+
+// BestMatches returns the n candidates most similar to query using the
+// Jaro-Winkler algorithm, best first. Candidates with equal scores keep
+// their order. A negative n returns all candidates.
+func BestMatches(query string, candidates []string, n int) []ScoredMatch {
+	return BestMatchesWith(query, candidates, n, SimilarityOptions{})
+}
+
+// BestMatchesWith is like BestMatches, but takes options altering its
+// behavior.
+func BestMatchesWith(query string, candidates []string, n int, opts SimilarityOptions) []ScoredMatch {
+	matches := make([]ScoredMatch, 0, len(candidates))
+	for _, c := range candidates {
+		matches = append(matches, ScoredMatch{Value: c, Score: SimilarityWith(query, c, opts)})
+	}
+	slices.SortStableFunc(matches, func(a, b ScoredMatch) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	if n >= 0 && n < len(matches) {
+		matches = matches[:n]
+	}
+
+	return matches
+}
+
+// jaroWinkler returns the Jaro-Winkler similarity of a and b, boosting
+// the Jaro similarity of strings above 0.7 by their common prefix of
+// up to four characters.
+func jaroWinkler(a, b []rune) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	window := max(max(len(a), len(b))/2-1, 0)
+	matchedA := make([]bool, len(a))
+	matchedB := make([]bool, len(b))
+	matches := 0
+	for i, r := range a {
+		for j := max(i-window, 0); j < min(i+window+1, len(b)); j++ {
+			if !matchedB[j] && b[j] == r {
+				matchedA[i], matchedB[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	j := 0
+	for i, r := range a {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if b[j] != r {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(a)) + m/float64(len(b)) + (m-float64(transpositions/2))/m) / 3
+	if jaro <= 0.7 {
+		return jaro
+	}
+
+	prefix := 0
+	for prefix < min(len(a), len(b), 4) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// trigramSimilarity returns the share of distinct trigrams of a and b
+// that they have in common.
+func trigramSimilarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	common := 0
+	for t := range ta {
+		if _, ok := tb[t]; ok {
+			common++
+		}
+	}
+	if union := len(ta) + len(tb) - common; union > 0 {
+		return float64(common) / float64(union)
+	}
+	return 0
+}
+
+// trigrams returns the trigrams of the words of s, each word padded
+// with two spaces in front and one behind, so short words and word
+// starts count.
+func trigrams(s string) map[[3]rune]struct{} {
+	set := make(map[[3]rune]struct{})
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		padded := []rune("  " + word + " ")
+		for i := range len(padded) - 2 {
+			set[[3]rune(padded[i:i+3])] = struct{}{}
+		}
+	}
+	return set
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := range a {
+		cur[0] = i + 1
+		for j := range b {
+			cost := 1
+			if a[i] == b[j] {
+				cost = 0
+			}
+			cur[j+1] = min(prev[j+1]+1, cur[j]+1, prev[j]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
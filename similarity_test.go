@@ -0,0 +1,71 @@
+package gobag
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b      string
+		algorithm SimilarityAlgorithm
+		expected  float64
+	}{
+		{"MARTHA", "MARHTA", JaroWinkler, 0.9611},
+		{"DIXON", "DICKSONX", JaroWinkler, 0.8133},
+		{"DWAYNE", "DUANE", JaroWinkler, 0.84},
+		{"abc", "xyz", JaroWinkler, 0},
+		{"", "abc", JaroWinkler, 0},
+		{"word", "words", Trigram, 0.5714},
+		{"hello world", "world, hello", Trigram, 1},
+		{"hello world", "hello there", Trigram, 0.3333},
+		{"--", "..", Trigram, 0},
+		{"abc", "xyz", Trigram, 0},
+		{"kitten", "sitting", Levenshtein, 0.5714},
+		{"", "abc", Levenshtein, 0},
+		{"", "", Levenshtein, 1},
+		{"same", "same", Trigram, 1},
+	}
+	for _, tt := range tests {
+		got := SimilarityWith(tt.a, tt.b, SimilarityOptions{Algorithm: tt.algorithm})
+		if math.Abs(got-tt.expected) > 0.0001 {
+			t.Errorf("SimilarityWith(%q, %q, %d) = %.4f, want %.4f", tt.a, tt.b, tt.algorithm, got, tt.expected)
+		}
+		if back := SimilarityWith(tt.b, tt.a, SimilarityOptions{Algorithm: tt.algorithm}); math.Abs(got-back) > 1e-9 {
+			t.Errorf("SimilarityWith(%q, %q, %d) = %.4f, not symmetric", tt.b, tt.a, tt.algorithm, back)
+		}
+	}
+
+	if got := Similarity("Hostname", "hostname"); got == 1 {
+		t.Errorf("Similarity() ignored case, got %v", got)
+	}
+	if got := SimilarityWith("Hostname", "hostname", SimilarityOptions{IgnoreCase: true}); got != 1 {
+		t.Errorf("SimilarityWith() with IgnoreCase = %v, want 1", got)
+	}
+}
+
+func TestBestMatches(t *testing.T) {
+	candidates := []string{"hostname", "port", "host", "hostnames", "timeout"}
+
+	got := BestMatches("hostnme", candidates, 2)
+	values := []string{got[0].Value, got[1].Value}
+	if want := []string{"hostname", "hostnames"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("BestMatches() = %v, want values %q", got, want)
+	}
+	if got[0].Score < got[1].Score {
+		t.Errorf("BestMatches() not sorted by score: %v", got)
+	}
+
+	if got := BestMatches("x", candidates, -1); len(got) != len(candidates) {
+		t.Errorf("BestMatches(-1) returned %d matches, want %d", len(got), len(candidates))
+	}
+	if got := BestMatches("x", nil, 3); len(got) != 0 {
+		t.Errorf("BestMatches(nil) = %v, want none", got)
+	}
+
+	got = BestMatchesWith("qq", []string{"a", "b", "c"}, 2, SimilarityOptions{Algorithm: Trigram})
+	if got[0].Value != "a" || got[1].Value != "b" {
+		t.Errorf("BestMatchesWith() with equal scores = %v, want input order", got)
+	}
+}
@@ -0,0 +1,107 @@
+package gobag
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SlugOptions holds optional settings for SlugifyWith.
+type SlugOptions struct {
+	// Separator replaces runs of other characters between words. The
+	// default is "-".
+	Separator string
+
+	// MaxLength limits the length of the slug in bytes, cutting at a
+	// word boundary when possible. Zero means no limit.
+	MaxLength int
+}
+
+// Slugify returns a lowercase ASCII slug of s for use in identifiers,
+// URLs and file names, e.g. "Crème Brûlée à la Øre" becomes
+// "creme-brulee-a-la-ore". Common accented and special letters are
+// transliterated, and runs of any other characters are replaced by a
+// single hyphen.
+func Slugify(s string) string {
+	return SlugifyWith(s, SlugOptions{})
+}
+
+// SlugifyWith is like Slugify, but takes options altering its
+// behavior.
+func SlugifyWith(s string, opts SlugOptions) string {
+	sep := opts.Separator
+	if sep == "" {
+		sep = "-"
+	}
+
+	var sb strings.Builder
+	pending := false
+	for _, r := range s {
+		r = unicode.ToLower(r)
+		var t string
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			t = string(r)
+		case unicode.Is(unicode.Mn, r):
+			// Combining marks of decomposed accents.
+			continue
+		default:
+			t = transliterations[r]
+		}
+
+		if t == "" {
+			pending = sb.Len() > 0
+			continue
+		}
+		if pending {
+			sb.WriteString(sep)
+			pending = false
+		}
+		sb.WriteString(t)
+	}
+
+	slug := sb.String()
+	if opts.MaxLength > 0 && len(slug) > opts.MaxLength {
+		cut := slug[:opts.MaxLength]
+		if i := strings.LastIndex(cut, sep); i > 0 && !strings.HasPrefix(slug[opts.MaxLength:], sep) {
+			cut = cut[:i]
+		}
+		slug = strings.TrimSuffix(cut, sep)
+	}
+
+	return slug
+}
+
+// transliterations maps common non-ASCII lowercase letters to ASCII.
+var transliterations = func() map[rune]string {
+	m := make(map[rune]string)
+	for ascii, runes := range map[string]string{
+		"a":  "àáâãäåāăą",
+		"ae": "æ",
+		"c":  "çćĉċč",
+		"d":  "ďđð",
+		"e":  "èéêëēĕėęě",
+		"g":  "ĝğġģ",
+		"h":  "ĥħ",
+		"i":  "ìíîïĩīĭįı",
+		"j":  "ĵ",
+		"k":  "ķ",
+		"l":  "ĺļľŀł",
+		"n":  "ñńņňŉ",
+		"o":  "òóôõöøōŏő",
+		"oe": "œ",
+		"r":  "ŕŗř",
+		"s":  "śŝşšș",
+		"ss": "ß",
+		"t":  "ţťŧț",
+		"th": "þ",
+		"u":  "ùúûüũūŭůűų",
+		"w":  "ŵ",
+		"y":  "ýÿŷ",
+		"z":  "źżž",
+	} {
+		for _, r := range runes {
+			m[r] = ascii
+		}
+	}
+	return m
+}()
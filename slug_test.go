@@ -0,0 +1,40 @@
+package gobag
+
+import "testing"
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Hello, World!", "hello-world"},
+		{"  Crème Brûlée à la Øre  ", "creme-brulee-a-la-ore"},
+		{"Straße & Æble", "strasse-aeble"},
+		{"été", "ete"},
+		{"max_size=10", "max-size-10"},
+		{"日本", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Slugify(tt.input); got != tt.expected {
+			t.Errorf("Slugify(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+
+	withOpts := []struct {
+		input    string
+		opts     SlugOptions
+		expected string
+	}{
+		{"Hello World", SlugOptions{Separator: "_"}, "hello_world"},
+		{"one two three", SlugOptions{MaxLength: 9}, "one-two"},
+		{"one two three", SlugOptions{MaxLength: 7}, "one-two"},
+		{"onetwothree", SlugOptions{MaxLength: 6}, "onetwo"},
+		{"one two", SlugOptions{MaxLength: 4}, "one"},
+	}
+	for _, tt := range withOpts {
+		if got := SlugifyWith(tt.input, tt.opts); got != tt.expected {
+			t.Errorf("SlugifyWith(%q, %+v) = %q, want %q", tt.input, tt.opts, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package gobag
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Split is a named lap time recorded by a Stopwatch.
+type Split struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Stopwatch measures the time of consecutive steps of a task. It is
+// safe for concurrent use.
+type Stopwatch struct {
+	mu     sync.Mutex
+	clock  Clock
+	start  time.Time
+	last   time.Time
+	splits []Split
+}
+
+// NewStopwatch returns a running Stopwatch.
+func NewStopwatch() *Stopwatch {
+	return NewStopwatchClock(RealClock{})
+}
+
+// NewStopwatchClock is like NewStopwatch, but measures time by clock.
+func NewStopwatchClock(clock Clock) *Stopwatch {
+	now := clock.Now()
+	return &Stopwatch{clock: clock, start: now, last: now}
+}
+
+// Lap records the time since the previous lap, or since the start, as
+// a split named name, and returns it.
+func (s *Stopwatch) Lap(name string) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	d := now.Sub(s.last)
+	s.last = now
+	s.splits = append(s.splits, Split{Name: name, Duration: d})
+	return d
+}
+
+// Splits returns the recorded splits in order.
+func (s *Stopwatch) Splits() []Split {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Split(nil), s.splits...)
+}
+
+// Elapsed returns the time since the stopwatch was started or reset.
+func (s *Stopwatch) Elapsed() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.clock.Now().Sub(s.start)
+}
+
+// Reset discards all splits and restarts the stopwatch.
+func (s *Stopwatch) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.start = s.clock.Now()
+	s.last = s.start
+	s.splits = nil
+}
+
+// String returns the splits and the elapsed time, in the form
+// "read=1.2ms parse=3.4ms total=4.6ms".
+func (s *Stopwatch) String() string {
+	var sb strings.Builder
+	for _, split := range s.Splits() {
+		sb.WriteString(split.Name + "=" + split.Duration.String() + " ")
+	}
+	sb.WriteString("total=" + s.Elapsed().String())
+	return sb.String()
+}
+
+// Timed calls fn and returns its results along with the time it took.
+func Timed[T any](fn func() (T, error)) (T, time.Duration, error) {
+	start := time.Now()
+	v, err := fn()
+	return v, time.Since(start), err
+}
+
+// DeferTimer starts a timer and returns a function that calls log with
+// the time elapsed since. It is meant to time a function with a single
+// line:
+//
+//	defer gobag.DeferTimer(func(d time.Duration) {
+//		log.Printf("parsed config in %v", d)
+//	})()
+func DeferTimer(log func(d time.Duration)) func() {
+	start := time.Now()
+	return func() {
+		log(time.Since(start))
+	}
+}
@@ -0,0 +1,56 @@
+package gobag
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStopwatch(t *testing.T) {
+	sw := NewStopwatch()
+	time.Sleep(2 * time.Millisecond)
+	first := sw.Lap("read")
+	second := sw.Lap("parse")
+
+	if first < 2*time.Millisecond {
+		t.Errorf("Lap() = %v, want at least 2ms", first)
+	}
+	splits := sw.Splits()
+	if len(splits) != 2 || splits[0] != (Split{"read", first}) || splits[1] != (Split{"parse", second}) {
+		t.Errorf("Splits() = %v", splits)
+	}
+	if sw.Elapsed() < first+second {
+		t.Errorf("Elapsed() = %v, want at least %v", sw.Elapsed(), first+second)
+	}
+	if s := sw.String(); !strings.HasPrefix(s, "read=") || !strings.Contains(s, " parse=") || !strings.Contains(s, " total=") {
+		t.Errorf("String() = %q", s)
+	}
+
+	sw.Reset()
+	if len(sw.Splits()) != 0 || sw.Elapsed() >= first {
+		t.Errorf("Reset() kept splits %v or elapsed %v", sw.Splits(), sw.Elapsed())
+	}
+}
+
+func TestTimed(t *testing.T) {
+	fail := errors.New("fail")
+	v, d, err := Timed(func() (int, error) {
+		time.Sleep(time.Millisecond)
+		return 42, fail
+	})
+	if v != 42 || !errors.Is(err, fail) || d < time.Millisecond {
+		t.Errorf("Timed() = %d, %v, %v", v, d, err)
+	}
+}
+
+func TestDeferTimer(t *testing.T) {
+	var got time.Duration
+	func() {
+		defer DeferTimer(func(d time.Duration) { got = d })()
+		time.Sleep(time.Millisecond)
+	}()
+	if got < time.Millisecond {
+		t.Errorf("DeferTimer() logged %v, want at least 1ms", got)
+	}
+}
@@ -0,0 +1,239 @@
+package gobag
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// RuneLen returns the number of runes in s. Unlike len(s) it does not
+// count bytes, so "æøå" has a length of 3.
+func RuneLen(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
+// Graphemes splits s into user-perceived characters, approximating
+// Unicode extended grapheme clusters: a base rune is kept together with
+// following combining marks, variation selectors and emoji modifiers,
+// runes joined by a zero width joiner, pairs of regional indicators
+// forming a flag, and CR LF.
+func Graphemes(s string) []string {
+	clusters := make([]string, 0, len(s))
+	for len(s) > 0 {
+		n := nextGrapheme(s)
+		clusters = append(clusters, s[:n])
+		s = s[n:]
+	}
+	return clusters
+}
+
+// GraphemeLen returns the number of user-perceived characters in s, as
+// split by Graphemes.
+func GraphemeLen(s string) int {
+	var n int
+	for len(s) > 0 {
+		s = s[nextGrapheme(s):]
+		n++
+	}
+	return n
+}
+
+// nextGrapheme returns the length in bytes of the first grapheme
+// cluster of s.
+func nextGrapheme(s string) int {
+	r, n := utf8.DecodeRuneInString(s)
+	if r == '\r' && strings.HasPrefix(s[n:], "\n") {
+		return n + 1
+	}
+	if isRegionalIndicator(r) {
+		if r2, n2 := utf8.DecodeRuneInString(s[n:]); isRegionalIndicator(r2) {
+			return n + n2
+		}
+		return n
+	}
+
+	for n < len(s) {
+		next, size := utf8.DecodeRuneInString(s[n:])
+		switch {
+		case isGraphemeExtend(next):
+			n += size
+		case next == zeroWidthJoiner:
+			n += size
+			if n < len(s) {
+				_, size = utf8.DecodeRuneInString(s[n:])
+				n += size
+			}
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+const zeroWidthJoiner = '\u200d'
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+func isGraphemeExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		(r >= 0xFE00 && r <= 0xFE0F) || // Variation selectors.
+		(r >= 0x1F3FB && r <= 0x1F3FF) || // Emoji skin tone modifiers.
+		(r >= 0xE0020 && r <= 0xE007F) // Tags.
+}
+
+// Width returns the number of terminal columns needed to display s.
+// East Asian wide and fullwidth characters and emoji take two columns,
+// while combining marks and control characters take none.
+func Width(s string) int {
+	var w int
+	for len(s) > 0 {
+		n := nextGrapheme(s)
+		w += graphemeWidth(s[:n])
+		s = s[n:]
+	}
+	return w
+}
+
+func graphemeWidth(g string) int {
+	r, n := utf8.DecodeRuneInString(g)
+	if isRegionalIndicator(r) || (n < len(g) && strings.ContainsAny(g, "\u200d\ufe0f")) {
+		return 2
+	}
+	return RuneWidth(r)
+}
+
+// RuneWidth returns the number of terminal columns needed to display
+// the rune r on its own: 0 for control characters and combining marks,
+// 2 for East Asian wide and fullwidth characters and emoji, and 1
+// otherwise.
+func RuneWidth(r rune) int {
+	switch {
+	case r == 0 || unicode.IsControl(r) || isGraphemeExtend(r) || r == zeroWidthJoiner ||
+		unicode.Is(unicode.Cf, r):
+		return 0
+	case isWideRune(r):
+		return 2
+	}
+	return 1
+}
+
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F},   // Hangul Jamo.
+	{0x231A, 0x231B},   // Watch, hourglass.
+	{0x2329, 0x232A},   // Angle brackets.
+	{0x23E9, 0x23EC},   // Media controls.
+	{0x23F0, 0x23F0},   // Alarm clock.
+	{0x23F3, 0x23F3},   // Hourglass.
+	{0x25FD, 0x25FE},   // Small squares.
+	{0x2614, 0x2615},   // Umbrella, hot beverage.
+	{0x2648, 0x2653},   // Zodiac.
+	{0x26A1, 0x26A1},   // High voltage.
+	{0x26AA, 0x26AB},   // Circles.
+	{0x26BD, 0x26BE},   // Balls.
+	{0x26C4, 0x26C5},   // Snowman, sun.
+	{0x26D4, 0x26D4},   // No entry.
+	{0x26EA, 0x26EA},   // Church.
+	{0x26F2, 0x26F5},   // Fountain to sailboat.
+	{0x26FA, 0x26FD},   // Tent to fuel pump.
+	{0x2705, 0x2705},   // Check mark.
+	{0x270A, 0x270B},   // Raised fists.
+	{0x2728, 0x2728},   // Sparkles.
+	{0x274C, 0x274C},   // Cross mark.
+	{0x2753, 0x2757},   // Question and exclamation marks.
+	{0x2795, 0x2797},   // Plus, minus, division.
+	{0x27B0, 0x27BF},   // Loops.
+	{0x2B1B, 0x2B1C},   // Large squares.
+	{0x2B50, 0x2B55},   // Star, circle.
+	{0x2E80, 0x303E},   // CJK radicals to CJK symbols.
+	{0x3041, 0x33FF},   // Hiragana to CJK compatibility.
+	{0x3400, 0x4DBF},   // CJK extension A.
+	{0x4E00, 0x9FFF},   // CJK unified ideographs.
+	{0xA000, 0xA4CF},   // Yi.
+	{0xA960, 0xA97F},   // Hangul Jamo extended A.
+	{0xAC00, 0xD7A3},   // Hangul syllables.
+	{0xF900, 0xFAFF},   // CJK compatibility ideographs.
+	{0xFE10, 0xFE19},   // Vertical forms.
+	{0xFE30, 0xFE6F},   // CJK compatibility forms.
+	{0xFF00, 0xFF60},   // Fullwidth forms.
+	{0xFFE0, 0xFFE6},   // Fullwidth signs.
+	{0x16FE0, 0x18CFF}, // Tangut and others.
+	{0x1B000, 0x1B2FF}, // Kana supplement and extended.
+	{0x1F004, 0x1F004}, // Mahjong tile.
+	{0x1F0CF, 0x1F0CF}, // Playing card.
+	{0x1F18E, 0x1F18E}, // AB button.
+	{0x1F191, 0x1F19A}, // Squared words.
+	{0x1F200, 0x1F2FF}, // Enclosed ideographic supplement.
+	{0x1F300, 0x1F64F}, // Pictographs and emoticons.
+	{0x1F680, 0x1F6FF}, // Transport and map symbols.
+	{0x1F7E0, 0x1F7EB}, // Colored circles and squares.
+	{0x1F90C, 0x1F9FF}, // Supplemental symbols and pictographs.
+	{0x1FA70, 0x1FAFF}, // Symbols and pictographs extended A.
+	{0x20000, 0x2FFFD}, // CJK extensions B to F.
+	{0x30000, 0x3FFFD}, // CJK extension G and later.
+}
+
+func isWideRune(r rune) bool {
+	if r < wideRanges[0][0] {
+		return false
+	}
+	lo, hi := 0, len(wideRanges)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		switch {
+		case r < wideRanges[mid][0]:
+			hi = mid
+		case r > wideRanges[mid][1]:
+			lo = mid + 1
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// ReverseString returns s with its user-perceived characters, as split
+// by Graphemes, in reverse order, so combining marks and emoji
+// sequences stay intact.
+func ReverseString(s string) string {
+	clusters := Graphemes(s)
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i := len(clusters) - 1; i >= 0; i-- {
+		sb.WriteString(clusters[i])
+	}
+	return sb.String()
+}
+
+// SubstrByRunes returns the substring of s starting at the rune index
+// start and spanning at most length runes. Indexes beyond the end of s
+// are clamped, and a negative length extends to the end of s.
+func SubstrByRunes(s string, start, length int) string {
+	return substr(s, start, length, func(s string) int {
+		_, n := utf8.DecodeRuneInString(s)
+		return n
+	})
+}
+
+// SubstrByGraphemes is like SubstrByRunes, but counts user-perceived
+// characters as split by Graphemes, so it never splits a character.
+func SubstrByGraphemes(s string, start, length int) string {
+	return substr(s, start, length, nextGrapheme)
+}
+
+func substr(s string, start, length int, next func(string) int) string {
+	start = max(start, 0)
+	for ; start > 0 && len(s) > 0; start-- {
+		s = s[next(s):]
+	}
+	if length < 0 {
+		return s
+	}
+
+	end := 0
+	for ; length > 0 && end < len(s); length-- {
+		end += next(s[end:])
+	}
+	return s[:end]
+}
@@ -0,0 +1,83 @@
+package gobag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGraphemes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"", []string{}},
+		{"abc", []string{"a", "b", "c"}},
+		{"e\u0301le\u0300ve", []string{"e\u0301", "l", "e\u0300", "v", "e"}},
+		{"👍🏽!", []string{"👍🏽", "!"}},
+		{"👩\u200d👩\u200d👧x", []string{"👩\u200d👩\u200d👧", "x"}},
+		{"🇳🇴🇸🇪", []string{"🇳🇴", "🇸🇪"}},
+		{"a\r\nb", []string{"a", "\r\n", "b"}},
+	}
+	for _, tt := range tests {
+		if got := Graphemes(tt.input); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Graphemes(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+		if got := GraphemeLen(tt.input); got != len(tt.expected) {
+			t.Errorf("GraphemeLen(%q) = %d, want %d", tt.input, got, len(tt.expected))
+		}
+	}
+
+	if n := RuneLen("æøå"); n != 3 {
+		t.Errorf("RuneLen() = %d, want 3", n)
+	}
+}
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{"abc", 3},
+		{"æøå", 3},
+		{"e\u0301", 1},
+		{"日本語", 6},
+		{"ｈｉ", 4},
+		{"👍🏽", 2},
+		{"👩\u200d👩\u200d👧", 2},
+		{"🇳🇴", 2},
+		{"a\tb", 2},
+	}
+	for _, tt := range tests {
+		if got := Width(tt.input); got != tt.expected {
+			t.Errorf("Width(%q) = %d, want %d", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestReverseAndSubstr(t *testing.T) {
+	if got := ReverseString("aé🇳🇴"); got != "🇳🇴éa" {
+		t.Errorf("ReverseString() = %q", got)
+	}
+
+	tests := []struct {
+		input         string
+		start, length int
+		runes         string
+		graphemes     string
+	}{
+		{"København", 1, 4, "øben", "øben"},
+		{"København", 5, -1, "havn", "havn"},
+		{"København", 8, 10, "n", "n"},
+		{"København", 20, 1, "", ""},
+		{"e\u0301te\u0301", 0, 2, "e\u0301", "e\u0301t"},
+		{"abc", -1, 1, "a", "a"},
+	}
+	for _, tt := range tests {
+		if got := SubstrByRunes(tt.input, tt.start, tt.length); got != tt.runes {
+			t.Errorf("SubstrByRunes(%q, %d, %d) = %q, want %q", tt.input, tt.start, tt.length, got, tt.runes)
+		}
+		if got := SubstrByGraphemes(tt.input, tt.start, tt.length); got != tt.graphemes {
+			t.Errorf("SubstrByGraphemes(%q, %d, %d) = %q, want %q", tt.input, tt.start, tt.length, got, tt.graphemes)
+		}
+	}
+}
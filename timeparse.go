@@ -0,0 +1,85 @@
+package gobag
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Pseudo layouts of ParseTimeAny for times given as a number of
+// seconds or milliseconds since the Unix epoch.
+const (
+	LayoutUnix      = "unix"
+	LayoutUnixMilli = "unixmilli"
+)
+
+// defaultTimeLayouts are the layouts ParseTimeAny tries by default.
+var defaultTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"02/Jan/2006:15:04:05 -0700", // Common Log Format.
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	time.RubyDate,
+	time.UnixDate,
+	time.ANSIC,
+	time.Stamp, // Syslog.
+	LayoutUnix,
+	LayoutUnixMilli,
+}
+
+// ParseTimeAny parses s with the first of layouts that accepts it,
+// trying a built-in list of common layouts if none are given: RFC 3339
+// and its variants with a space or without a zone, plain dates, the
+// Common Log Format, the layouts of RFC 1123, RFC 850, ANSI C and Unix
+// date, and syslog time stamps. Times without a zone are in UTC, and
+// syslog time stamps, which lack a year, are in year 0.
+//
+// The pseudo layouts LayoutUnix and LayoutUnixMilli accept the number
+// of seconds, possibly with a fraction, and milliseconds since the
+// Unix epoch. In the built-in list, numbers with more than 11 digits
+// are taken as milliseconds. Returns an error listing the layouts
+// tried if none accepts s.
+func ParseTimeAny(s string, layouts ...string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	builtin := len(layouts) == 0
+	if builtin {
+		layouts = defaultTimeLayouts
+	}
+
+	for _, layout := range layouts {
+		switch layout {
+		case LayoutUnix:
+			if builtin && len(strings.TrimLeft(s, "-")) > 11 && !strings.Contains(s, ".") {
+				continue
+			}
+			if f, err := strconv.ParseFloat(s, 64); err == nil && isDecimal(s) {
+				sec, frac := math.Modf(f)
+				return time.Unix(int64(sec), int64(frac*1e9)).UTC(), nil
+			}
+		case LayoutUnixMilli:
+			if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return time.UnixMilli(n).UTC(), nil
+			}
+		default:
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, nil
+			}
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unable to parse time %q, tried layouts: %s", s, strings.Join(layouts, ", "))
+}
+
+// isDecimal reports whether s is a plain decimal number, without an
+// exponent or the special values accepted by strconv.ParseFloat.
+func isDecimal(s string) bool {
+	return s != "" && strings.Trim(s, "-+.0123456789") == ""
+}
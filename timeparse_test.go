@@ -0,0 +1,66 @@
+package gobag
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTimeAny(t *testing.T) {
+	ref := time.Date(2024, 5, 1, 12, 30, 45, 0, time.UTC)
+	cet := time.FixedZone("", 2*60*60)
+
+	tests := []struct {
+		input    string
+		expected time.Time
+	}{
+		{"2024-05-01T12:30:45Z", ref},
+		{"2024-05-01T14:30:45+02:00", ref.In(cet)},
+		{"2024-05-01T12:30:45.5Z", ref.Add(500 * time.Millisecond)},
+		{"2024-05-01 12:30:45", ref},
+		{"2024-05-01T12:30:45", ref},
+		{"2024-05-01", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{"2024/05/01 12:30:45", ref},
+		{"01/May/2024:14:30:45 +0200", ref.In(cet)},
+		{"Wed, 01 May 2024 12:30:45 +0000", ref.In(time.FixedZone("", 0))},
+		{"Wed May  1 12:30:45 2024", ref},
+		{"May  1 12:30:45", time.Date(0, 5, 1, 12, 30, 45, 0, time.UTC)},
+		{"1714566645", ref},
+		{"1714566645.25", ref.Add(250 * time.Millisecond)},
+		{"1714566645123", ref.Add(123 * time.Millisecond)},
+	}
+	for _, tt := range tests {
+		got, err := ParseTimeAny(tt.input)
+		if err != nil {
+			t.Errorf("ParseTimeAny(%q) error = %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.expected) {
+			t.Errorf("ParseTimeAny(%q) = %v, want %v", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestParseTimeAnyLayouts(t *testing.T) {
+	got, err := ParseTimeAny("01.05.2024", "2006-01-02", "02.01.2006")
+	if err != nil || !got.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("ParseTimeAny() = %v, %v", got, err)
+	}
+
+	got, err = ParseTimeAny("1714566645", LayoutUnixMilli)
+	if err != nil || got.UnixMilli() != 1714566645 {
+		t.Errorf("ParseTimeAny(unixmilli) = %v, %v", got, err)
+	}
+
+	_, err = ParseTimeAny("soon", "2006-01-02", LayoutUnix)
+	want := `unable to parse time "soon", tried layouts: 2006-01-02, unix`
+	if err == nil || err.Error() != want {
+		t.Errorf("ParseTimeAny() error = %v, want %q", err, want)
+	}
+
+	for _, s := range []string{"", "NaN", "1e9", "Inf"} {
+		if _, err := ParseTimeAny(s); err == nil || !strings.Contains(err.Error(), "tried layouts") {
+			t.Errorf("ParseTimeAny(%q) error = %v", s, err)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package gobag
+
+import (
+	"cmp"
+	"container/heap"
+	"slices"
+)
+
+// TopK finds the most frequent items of a stream in fixed memory, using
+// the Space-Saving algorithm. It tracks at most capacity items; when a
+// new item arrives while full, it takes over the slot of the least
+// frequent tracked item, inheriting its count as possible error.
+//
+// Counts are never underestimated, and overestimated by at most
+// N/capacity for a stream of N items. Every item occurring more than
+// N/capacity times is guaranteed to be tracked. A TopK is not safe for
+// concurrent use.
+type TopK[T comparable] struct {
+	capacity int
+	total    uint64
+	entries  map[T]*topKEntry[T]
+	heap     topKHeap[T]
+}
+
+// TopKItem is an item reported by TopK.Top. The true count of the item
+// lies between Count-Error and Count.
+type TopKItem[T comparable] struct {
+	Item  T
+	Count uint64
+	Error uint64
+}
+
+type topKEntry[T comparable] struct {
+	TopKItem[T]
+	index int // Position in the heap.
+}
+
+// NewTopK returns a TopK tracking at most capacity items. A larger
+// capacity gives more accurate counts. It panics if capacity is less
+// than 1.
+func NewTopK[T comparable](capacity int) *TopK[T] {
+	if capacity < 1 {
+		panic("gobag: TopK capacity must be at least 1")
+	}
+	return &TopK[T]{
+		capacity: capacity,
+		entries:  make(map[T]*topKEntry[T], capacity),
+	}
+}
+
+// Add counts one occurrence of item.
+func (t *TopK[T]) Add(item T) {
+	t.AddN(item, 1)
+}
+
+// AddN counts n occurrences of item.
+func (t *TopK[T]) AddN(item T, n uint64) {
+	t.total += n
+	if e, ok := t.entries[item]; ok {
+		e.Count += n
+		heap.Fix(&t.heap, e.index)
+		return
+	}
+	if len(t.heap) < t.capacity {
+		e := &topKEntry[T]{TopKItem: TopKItem[T]{Item: item, Count: n}}
+		t.entries[item] = e
+		heap.Push(&t.heap, e)
+		return
+	}
+
+	e := t.heap[0]
+	delete(t.entries, e.Item)
+	e.Item, e.Error = item, e.Count
+	e.Count += n
+	t.entries[item] = e
+	heap.Fix(&t.heap, 0)
+}
+
+// Top returns up to n of the most frequent items, by descending count.
+// A negative n returns all tracked items.
+func (t *TopK[T]) Top(n int) []TopKItem[T] {
+	items := make([]TopKItem[T], 0, len(t.heap))
+	for _, e := range t.heap {
+		items = append(items, e.TopKItem)
+	}
+	slices.SortFunc(items, func(a, b TopKItem[T]) int {
+		// Items with less uncertainty first among equal counts.
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Error, b.Error))
+	})
+	if n >= 0 && n < len(items) {
+		items = items[:n]
+	}
+	return items
+}
+
+// Total returns the number of items added.
+func (t *TopK[T]) Total() uint64 {
+	return t.total
+}
+
+// MaxError returns the bound on how much any count may be
+// overestimated, which is Total()/capacity.
+func (t *TopK[T]) MaxError() uint64 {
+	return t.total / uint64(t.capacity)
+}
+
+// topKHeap is a min-heap of entries by count.
+type topKHeap[T comparable] []*topKEntry[T]
+
+func (h topKHeap[T]) Len() int { return len(h) }
+
+func (h topKHeap[T]) Less(i, j int) bool { return h[i].Count < h[j].Count }
+
+func (h topKHeap[T]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+
+func (h *topKHeap[T]) Push(x any) {
+	e := x.(*topKEntry[T])
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *topKHeap[T]) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return e
+}
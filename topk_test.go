@@ -0,0 +1,71 @@
+package gobag
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestTopKExact(t *testing.T) {
+	tk := NewTopK[string](10)
+	for _, w := range []string{"a", "b", "a", "c", "a", "b"} {
+		tk.Add(w)
+	}
+	tk.AddN("d", 5)
+
+	expected := []TopKItem[string]{{"d", 5, 0}, {"a", 3, 0}, {"b", 2, 0}}
+	if got := tk.Top(3); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Top(3) = %v, want %v", got, expected)
+	}
+	if got := tk.Top(-1); len(got) != 4 {
+		t.Errorf("Top(-1) returned %d items, want 4", len(got))
+	}
+	if tk.Total() != 11 {
+		t.Errorf("Total() = %d, want 11", tk.Total())
+	}
+}
+
+func TestTopKHeavyHitters(t *testing.T) {
+	const capacity = 20
+	tk := NewTopK[string](capacity)
+	counts := map[string]uint64{}
+	// Three heavy items among a long tail of items seen once.
+	for i := range 10000 {
+		var item string
+		switch {
+		case i%5 == 0:
+			item = "heavy1"
+		case i%7 == 0:
+			item = "heavy2"
+		case i%11 == 0:
+			item = "heavy3"
+		default:
+			item = "tail" + strconv.Itoa(i)
+		}
+		counts[item]++
+		tk.Add(item)
+	}
+
+	top := tk.Top(3)
+	for i, name := range []string{"heavy1", "heavy2", "heavy3"} {
+		got := top[i]
+		if got.Item != name {
+			t.Fatalf("Top(3) = %v, want %s at %d", top, name, i)
+		}
+		if got.Count < counts[name] || got.Count-got.Error > counts[name] {
+			t.Errorf("%s: count %d, error %d, true count %d out of bounds", name, got.Count, got.Error, counts[name])
+		}
+		if got.Count-counts[name] > tk.MaxError() {
+			t.Errorf("%s: overestimated by %d, more than MaxError() %d", name, got.Count-counts[name], tk.MaxError())
+		}
+	}
+}
+
+func TestNewTopKPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewTopK(0) did not panic")
+		}
+	}()
+	NewTopK[int](0)
+}
@@ -0,0 +1,53 @@
+package gobag
+
+// TraceEventKind identifies the kind of a tokenizer event.
+type TraceEventKind int
+
+// Tokenizer events reported to a TraceFunc.
+const (
+	TraceFieldStart TraceEventKind = iota // A new field begins.
+	TraceFieldEnd                         // A field is complete; see TraceEvent.Field.
+	TraceQuoteEnter                       // An opening quote.
+	TraceQuoteExit                        // A closing quote.
+	TraceGroupEnter                       // An opening parenthesis.
+	TraceGroupExit                        // A closing parenthesis.
+	TraceEscape                           // An escape character.
+)
+
+var traceEventKindNames = [...]string{
+	TraceFieldStart: "field-start",
+	TraceFieldEnd:   "field-end",
+	TraceQuoteEnter: "quote-enter",
+	TraceQuoteExit:  "quote-exit",
+	TraceGroupEnter: "group-enter",
+	TraceGroupExit:  "group-exit",
+	TraceEscape:     "escape",
+}
+
+// String returns the name of the event kind.
+func (k TraceEventKind) String() string {
+	if k >= 0 && int(k) < len(traceEventKindNames) {
+		return traceEventKindNames[k]
+	}
+	return "unknown"
+}
+
+// TraceEvent describes a single tokenizer event.
+type TraceEvent struct {
+	Kind   TraceEventKind
+	Offset int    // Byte offset of the event in the input.
+	Rune   rune   // The rune at Offset, or 0 at the end of input.
+	Depth  int    // Parenthesis depth after the event.
+	Field  string // The completed field, for TraceFieldEnd.
+}
+
+// TraceFunc receives tokenizer events, e.g. to debug why a line is
+// split unexpectedly.
+type TraceFunc func(TraceEvent)
+
+func quoteEvent(entered bool) TraceEventKind {
+	if entered {
+		return TraceQuoteEnter
+	}
+	return TraceQuoteExit
+}
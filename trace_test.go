@@ -0,0 +1,30 @@
+package gobag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFieldsTrace(t *testing.T) {
+	var got []string
+	trace := func(e TraceEvent) {
+		got = append(got, e.Kind.String()+"@"+string(rune('0'+e.Offset)))
+	}
+
+	fields, err := FieldsWith(`a,"b",(c)`, ',', FieldsOptions{Trace: trace})
+	if err != nil {
+		t.Fatalf("FieldsWith() error = %v", err)
+	}
+	if want := []string{"a", `"b"`, "(c)"}; !reflect.DeepEqual(fields, want) {
+		t.Fatalf("FieldsWith() = %q, want %q", fields, want)
+	}
+
+	want := []string{
+		"field-start@0", "field-end@1",
+		"field-start@2", "quote-enter@2", "quote-exit@4", "field-end@5",
+		"field-start@6", "group-enter@6", "group-exit@8", "field-end@9",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("trace = %q, want %q", got, want)
+	}
+}
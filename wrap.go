@@ -0,0 +1,70 @@
+package gobag
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Wrap wraps s into lines of at most width runes. Lines are broken at
+// whitespace, which is collapsed, or after a comma or semicolon, but
+// never within quotes or parentheses. A word longer than width is put
+// on a line of its own. A width of zero or less disables wrapping.
+func Wrap(s string, width int) []string {
+	type piece struct {
+		text  string
+		space bool // Whether the piece was preceded by whitespace.
+	}
+
+	var pieces []piece
+	var state quoteState
+	var cur strings.Builder
+	space := false
+	flush := func() {
+		if cur.Len() > 0 {
+			pieces = append(pieces, piece{text: cur.String(), space: space})
+			cur.Reset()
+		}
+	}
+	for _, r := range s {
+		top := state.next(r)
+		switch {
+		case top && unicode.IsSpace(r):
+			flush()
+			space = true
+		case top && (r == ',' || r == ';'):
+			cur.WriteRune(r)
+			flush()
+			space = false
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	flush()
+
+	lines := make([]string, 0)
+	var line strings.Builder
+	var lineLen int
+	for _, p := range pieces {
+		n := utf8.RuneCountInString(p.text)
+		sepLen := 0
+		if p.space && line.Len() > 0 {
+			sepLen = 1
+		}
+		if line.Len() > 0 && width > 0 && lineLen+sepLen+n > width {
+			lines = append(lines, line.String())
+			line.Reset()
+			lineLen, sepLen = 0, 0
+		}
+		if sepLen > 0 {
+			line.WriteByte(' ')
+		}
+		line.WriteString(p.text)
+		lineLen += sepLen + n
+	}
+	if line.Len() > 0 {
+		lines = append(lines, line.String())
+	}
+
+	return lines
+}
@@ -0,0 +1,28 @@
+package gobag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		input    string
+		width    int
+		expected []string
+	}{
+		{"", 10, []string{}},
+		{"foo bar baz", 7, []string{"foo bar", "baz"}},
+		{"foo   bar", 0, []string{"foo bar"}},
+		{`opt="a b c d" x`, 8, []string{`opt="a b c d"`, "x"}},
+		{"a,(b, c, d),e", 4, []string{"a,", "(b, c, d),", "e"}},
+		{"alpha,beta,gamma", 11, []string{"alpha,beta,", "gamma"}},
+		{"supercalifragilistic is long", 5, []string{"supercalifragilistic", "is", "long"}},
+		{"ære være", 4, []string{"ære", "være"}},
+	}
+	for _, tt := range tests {
+		if got := Wrap(tt.input, tt.width); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Wrap(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.expected)
+		}
+	}
+}